@@ -131,9 +131,11 @@ func main() { //nolint:funlen,cyclop
 		setupLog.Error(err, "unable to set up uncached client")
 	}
 
+	namespacedUncachedClient := client.NewNamespacedClient(uncachedClient, managedNamespace)
+
 	if err := (&cpmscontroller.ControlPlaneMachineSetReconciler{
 		Client:         mgr.GetClient(),
-		UncachedClient: client.NewNamespacedClient(uncachedClient, managedNamespace),
+		UncachedClient: namespacedUncachedClient,
 		Scheme:         mgr.GetScheme(),
 		Namespace:      managedNamespace,
 		OperatorName:   "control-plane-machine-set",
@@ -166,7 +168,7 @@ func main() { //nolint:funlen,cyclop
 		os.Exit(1)
 	}
 
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+	if err := mgr.AddReadyzCheck("readyz", util.NewAPIServerReadyCheck(namespacedUncachedClient)); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
@@ -145,5 +145,12 @@ const (
 	// replicas under its management that are currently in need of an update.
 	reasonNeedsUpdateReplicas = "NeedsUpdateReplicas"
 
+	// reasonBelowQuorum denotes that the ControlPlaneMachineSet has found fewer ready
+	// Control Plane Machines than are required to maintain etcd quorum.
+	// Removing a ready machine in this state risks losing quorum entirely, so the
+	// ControlPlaneMachineSet will not remove replaced machines until enough machines are ready.
+	// Machines are still created for missing and failed indexes to restore quorum.
+	reasonBelowQuorum = "BelowQuorum"
+
 	// END: Progressing reasons.
 )
@@ -69,6 +69,9 @@ var (
 	// errNoReadyControlPlaneMachines is used to inform users that no control plane machines in the cluster are ready.
 	errNoReadyControlPlaneMachines = errors.New("no ready control plane machines")
 
+	// errControlPlaneBelowQuorum is used to inform users that too few control plane machines are ready to maintain quorum.
+	errControlPlaneBelowQuorum = errors.New("too few ready control plane machines to maintain quorum")
+
 	// errFoundErroredReplacementControlPlaneMachine is used to inform users that one or more replacement control plane machines, have been found.
 	errFoundErroredReplacementControlPlaneMachine = errors.New("found replacement control plane machines in an error state, the following machines(s) are currently reporting an error")
 
@@ -183,6 +186,10 @@ func (r *ControlPlaneMachineSetReconciler) Reconcile(ctx context.Context, req ct
 	if err := r.updateControlPlaneMachineSetStatus(ctx, logger, cpms, patchBase); err != nil {
 		// Don't return an error here so that we have an opportunity to update the cluster operator status.
 		errs = append(errs, fmt.Errorf("error updating control plane machine set status: %w", err))
+	} else if cpms.GetDeletionTimestamp() == nil {
+		if err := r.updateLastRolloutTimeAnnotation(ctx, logger, cpms); err != nil {
+			errs = append(errs, fmt.Errorf("error updating control plane machine set last rollout time: %w", err))
+		}
 	}
 
 	if isActive(cpms) {
@@ -501,6 +508,10 @@ func (r *ControlPlaneMachineSetReconciler) ensureOwnerReferences(ctx context.Con
 //     -- Too few indexes, valid. We will later scale up without user intervention when we perform reconcileMachineUpdates.
 //     -- Too many indexes, invalid. We set the operator to degraded and ask the user for manual intervention.
 //   - No replacement machines (one that doesn't need update but has an equivalent in the index that needs update) have an error.
+//
+// It also reports when fewer than a majority of the desired indexes have a ready machine. This does not invalidate the
+// cluster state, as machines must still be created for missing and failed indexes to restore quorum, but replaced
+// machines will not be removed until quorum is restored.
 func (r *ControlPlaneMachineSetReconciler) validateClusterState(ctx context.Context, logger logr.Logger, cpms *machinev1.ControlPlaneMachineSet, machineInfos map[int32][]machineproviders.MachineInfo) error {
 	sortedIndexedMs := sortMachineInfosByIndex(machineInfos)
 
@@ -529,6 +540,10 @@ func (r *ControlPlaneMachineSetReconciler) validateClusterState(ctx context.Cont
 		return nil
 	}
 
+	// Report when too few of the control plane machines are ready to maintain quorum.
+	// Machines are still created to restore quorum, so this does not stop the reconciliation.
+	r.checkReadyControlPlaneMachinesHaveQuorum(logger, cpms, sortedIndexedMs)
+
 	// Normal conditions case.
 	if meta.FindStatusCondition(cpms.Status.Conditions, conditionDegraded) == nil {
 		meta.SetStatusCondition(&cpms.Status.Conditions, metav1.Condition{
@@ -696,6 +711,46 @@ func (r *ControlPlaneMachineSetReconciler) checkReadyControlPlaneMachineExists(l
 	return true
 }
 
+// checkReadyControlPlaneMachinesHaveQuorum checks that a majority of the desired control plane indexes have a ready machine.
+// When they do not, it sets the Progressing condition to explain that replaced machines will not be removed until
+// quorum is restored. Missing indexes are reported separately from indexes whose machines are not ready.
+func (r *ControlPlaneMachineSetReconciler) checkReadyControlPlaneMachinesHaveQuorum(logger logr.Logger, cpms *machinev1.ControlPlaneMachineSet, sortedIndexedMs []indexToMachineInfos) {
+	readyIndexes, missingIndexes, quorum := controlPlaneQuorum(cpms, sortedIndexedMs)
+	if readyIndexes >= quorum {
+		return
+	}
+
+	var nonReadyMachineNames []string
+
+	for _, indexToMachines := range sortedIndexedMs {
+		indexMachines := indexToMachines.machineInfos
+		if hasAny(readyMachines(indexMachines)) {
+			continue
+		}
+
+		for _, machineInfo := range indexMachines {
+			if machineInfo.MachineRef != nil {
+				nonReadyMachineNames = append(nonReadyMachineNames, machineInfo.MachineRef.ObjectMeta.Name)
+			}
+		}
+	}
+
+	logger.Error(
+		fmt.Errorf("%w: %d ready, %d missing, %d required", errControlPlaneBelowQuorum, readyIndexes, missingIndexes, quorum),
+		"Observed fewer ready control plane machines than required for quorum",
+		"unreadyMachines", strings.Join(nonReadyMachineNames, ","),
+		"missingIndexes", missingIndexes,
+	)
+
+	meta.SetStatusCondition(&cpms.Status.Conditions, metav1.Condition{
+		Type:   conditionProgressing,
+		Status: metav1.ConditionTrue,
+		Reason: reasonBelowQuorum,
+		Message: fmt.Sprintf("Observed %d ready control plane machine(s) and %d missing index(es), at least %d ready machine(s) are required to maintain quorum,"+
+			" replaced machines will not be removed until quorum is restored", readyIndexes, missingIndexes, quorum),
+	})
+}
+
 // checkCorrectNumberOfIndexes checks that the number of control plane machine set indexes found in the cluster is valid.
 func (r *ControlPlaneMachineSetReconciler) checkCorrectNumberOfIndexes(logger logr.Logger, cpms *machinev1.ControlPlaneMachineSet, sortedIndexedMs []indexToMachineInfos) bool {
 	currentIndexesCount := int32(len(sortedIndexedMs))
@@ -736,8 +791,10 @@ func (r *ControlPlaneMachineSetReconciler) checkCorrectNumberOfIndexes(logger lo
 }
 
 // checkNoErrorForReplacements checks that there is no errored replacement machine.
+// The error messages of the errored replacement machines are included in the Degraded condition,
+// so that the provider failure can be diagnosed from the ControlPlaneMachineSet status.
 func (r *ControlPlaneMachineSetReconciler) checkNoErrorForReplacements(logger logr.Logger, cpms *machinev1.ControlPlaneMachineSet, sortedIndexedMs []indexToMachineInfos) bool {
-	var erroredReplacementMachineNames []string
+	var erroredReplacementMachineNames, erroredReplacementMachineErrors []string
 
 	for _, indexToMachines := range sortedIndexedMs {
 		machines := indexToMachines.machineInfos
@@ -748,6 +805,7 @@ func (r *ControlPlaneMachineSetReconciler) checkNoErrorForReplacements(logger lo
 			for _, m := range machinesPending {
 				if m.ErrorMessage != "" {
 					erroredReplacementMachineNames = append(erroredReplacementMachineNames, m.MachineRef.ObjectMeta.Name)
+					erroredReplacementMachineErrors = append(erroredReplacementMachineErrors, fmt.Sprintf("%s: %s", m.MachineRef.ObjectMeta.Name, m.ErrorMessage))
 				}
 			}
 		}
@@ -770,7 +828,7 @@ func (r *ControlPlaneMachineSetReconciler) checkNoErrorForReplacements(logger lo
 			Type:    conditionDegraded,
 			Status:  metav1.ConditionTrue,
 			Reason:  reasonFailedReplacement,
-			Message: fmt.Sprintf("Observed %d replacement machine(s) in error state", len(erroredReplacementMachineNames)),
+			Message: fmt.Sprintf("Observed %d replacement machine(s) in error state: %s", len(erroredReplacementMachineNames), strings.Join(erroredReplacementMachineErrors, "; ")),
 		})
 
 		return false
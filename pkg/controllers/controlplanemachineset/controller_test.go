@@ -2054,6 +2054,73 @@ var _ = Describe("validateClusterState", func() {
 				},
 			},
 		}),
+		Entry("with fewer ready machines than required for quorum", validateClusterTableInput{
+			cpmsBuilder: cpmsBuilder.WithConditions([]metav1.Condition{
+				degradedConditionBuilder.WithStatus(metav1.ConditionFalse).Build(),
+				progressingConditionBuilder.WithStatus(metav1.ConditionFalse).Build(),
+			}).WithReplicas(3),
+			machineInfos: map[int32][]machineproviders.MachineInfo{
+				0: {updatedMachineBuilder.WithIndex(0).WithMachineName("machine-0").WithNodeName("master-0").Build()},
+				1: {updatedMachineBuilder.WithIndex(1).WithMachineName("machine-1").WithNodeName("master-1").WithReady(false).Build()},
+				2: {updatedMachineBuilder.WithIndex(2).WithMachineName("machine-2").WithNodeName("master-2").WithReady(false).Build()},
+			},
+			nodes: []*corev1.Node{
+				masterNodeBuilder.WithName("master-0").Build(),
+				masterNodeBuilder.WithName("master-1").Build(),
+				masterNodeBuilder.WithName("master-2").Build(),
+				workerNodeBuilder.WithName("worker-0").Build(),
+				workerNodeBuilder.WithName("worker-1").Build(),
+				workerNodeBuilder.WithName("worker-2").Build(),
+			},
+			expectedError: nil,
+			expectedConditions: []metav1.Condition{
+				degradedConditionBuilder.WithStatus(metav1.ConditionFalse).Build(),
+				progressingConditionBuilder.WithStatus(metav1.ConditionTrue).WithReason(reasonBelowQuorum).WithMessage("Observed 1 ready control plane machine(s) and 0 missing index(es), at least 2 ready machine(s) are required to maintain quorum, replaced machines will not be removed until quorum is restored").Build(),
+			},
+			expectedLogs: []test.LogEntry{
+				{
+					Error: errors.New("too few ready control plane machines to maintain quorum: 1 ready, 0 missing, 2 required"),
+					KeysAndValues: []interface{}{
+						"unreadyMachines", "machine-1,machine-2",
+						"missingIndexes", int32(0),
+					},
+					Message: "Observed fewer ready control plane machines than required for quorum",
+				},
+			},
+		}),
+		Entry("with missing indexes below quorum", validateClusterTableInput{
+			cpmsBuilder: cpmsBuilder.WithConditions([]metav1.Condition{
+				degradedConditionBuilder.WithStatus(metav1.ConditionFalse).Build(),
+				progressingConditionBuilder.WithStatus(metav1.ConditionFalse).Build(),
+			}).WithReplicas(3),
+			machineInfos: map[int32][]machineproviders.MachineInfo{
+				0: {updatedMachineBuilder.WithIndex(0).WithMachineName("machine-0").WithNodeName("master-0").Build()},
+				1: {},
+				2: {},
+			},
+			nodes: []*corev1.Node{
+				masterNodeBuilder.WithName("master-0").Build(),
+				workerNodeBuilder.WithName("worker-0").Build(),
+				workerNodeBuilder.WithName("worker-1").Build(),
+				workerNodeBuilder.WithName("worker-2").Build(),
+			},
+			expectedError: nil,
+			// The cluster must not be degraded, so that the missing indexes are still created to restore quorum.
+			expectedConditions: []metav1.Condition{
+				degradedConditionBuilder.WithStatus(metav1.ConditionFalse).Build(),
+				progressingConditionBuilder.WithStatus(metav1.ConditionTrue).WithReason(reasonBelowQuorum).WithMessage("Observed 1 ready control plane machine(s) and 2 missing index(es), at least 2 ready machine(s) are required to maintain quorum, replaced machines will not be removed until quorum is restored").Build(),
+			},
+			expectedLogs: []test.LogEntry{
+				{
+					Error: errors.New("too few ready control plane machines to maintain quorum: 1 ready, 2 missing, 2 required"),
+					KeysAndValues: []interface{}{
+						"unreadyMachines", "",
+						"missingIndexes", int32(2),
+					},
+					Message: "Observed fewer ready control plane machines than required for quorum",
+				},
+			},
+		}),
 		Entry("with an additional unowned master node", validateClusterTableInput{
 			cpmsBuilder: cpmsBuilder.WithConditions([]metav1.Condition{
 				degradedConditionBuilder.WithStatus(metav1.ConditionFalse).Build(),
@@ -2136,7 +2203,7 @@ var _ = Describe("validateClusterState", func() {
 			},
 			expectedError: nil,
 			expectedConditions: []metav1.Condition{
-				degradedConditionBuilder.WithStatus(metav1.ConditionTrue).WithReason(reasonFailedReplacement).WithMessage("Observed 1 replacement machine(s) in error state").Build(),
+				degradedConditionBuilder.WithStatus(metav1.ConditionTrue).WithReason(reasonFailedReplacement).WithMessage("Observed 1 replacement machine(s) in error state: machine-replacement-0: Could not create new instance").Build(),
 				progressingConditionBuilder.WithStatus(metav1.ConditionFalse).WithReason(reasonOperatorDegraded).Build(),
 			},
 			expectedLogs: []test.LogEntry{
@@ -2161,7 +2228,7 @@ var _ = Describe("validateClusterState", func() {
 				},
 				1: {
 					updatedMachineBuilder.WithIndex(1).WithMachineName("machine-1").WithNodeName("master-1").WithNeedsUpdate(true).Build(),
-					updatedMachineBuilder.WithIndex(1).WithMachineName("machine-replacement-1").WithErrorMessage("Could not create new instance").WithReady(false).Build(),
+					updatedMachineBuilder.WithIndex(1).WithMachineName("machine-replacement-1").WithErrorMessage("Insufficient capacity").WithReady(false).Build(),
 				},
 				2: {updatedMachineBuilder.WithIndex(2).WithMachineName("machine-2").WithNodeName("master-2").WithNeedsUpdate(true).Build()},
 			},
@@ -2175,7 +2242,7 @@ var _ = Describe("validateClusterState", func() {
 			},
 			expectedError: nil,
 			expectedConditions: []metav1.Condition{
-				degradedConditionBuilder.WithStatus(metav1.ConditionTrue).WithReason(reasonFailedReplacement).WithMessage("Observed 2 replacement machine(s) in error state").Build(),
+				degradedConditionBuilder.WithStatus(metav1.ConditionTrue).WithReason(reasonFailedReplacement).WithMessage("Observed 2 replacement machine(s) in error state: machine-replacement-0: Could not create new instance; machine-replacement-1: Insufficient capacity").Build(),
 				progressingConditionBuilder.WithStatus(metav1.ConditionFalse).WithReason(reasonOperatorDegraded).Build(),
 			},
 			expectedLogs: []test.LogEntry{
@@ -0,0 +1,70 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controlplanemachineset
+
+import (
+	machinev1 "github.com/openshift/api/machine/v1"
+	"github.com/openshift/cluster-control-plane-machine-set-operator/pkg/machineproviders/providers/openshift/machine/v1beta1/providerconfig"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// machineReplacementsTotalMetricName is the name of the counter of machines created by the ControlPlaneMachineSet.
+	machineReplacementsTotalMetricName = "control_plane_machine_set_machine_replacements_total"
+
+	// platformMetricLabel is the metric label holding the platform of the ControlPlaneMachineSet template.
+	platformMetricLabel = "platform"
+
+	// strategyMetricLabel is the metric label holding the update strategy of the ControlPlaneMachineSet.
+	strategyMetricLabel = "strategy"
+
+	// unknownPlatformMetricValue is the platform label value used when the platform of the template cannot be
+	// determined.
+	unknownPlatformMetricValue = "Unknown"
+)
+
+// machineReplacementsTotal counts the Machines created by the ControlPlaneMachineSet to replace outdated, deleted,
+// or missing, Machines. It is labelled with the platform and the update strategy.
+//
+//nolint:gochecknoglobals
+var machineReplacementsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: machineReplacementsTotalMetricName,
+		Help: "Number of control plane machines created by the control plane machine set to replace outdated, deleted or missing machines.",
+	},
+	[]string{platformMetricLabel, strategyMetricLabel},
+)
+
+//nolint:gochecknoinits
+func init() {
+	metrics.Registry.MustRegister(machineReplacementsTotal)
+}
+
+// recordMachineReplacement increments the machine replacements counter for the platform and update strategy
+// of the ControlPlaneMachineSet.
+func recordMachineReplacement(cpms *machinev1.ControlPlaneMachineSet) {
+	platform := unknownPlatformMetricValue
+
+	if template := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine; template != nil {
+		if providerConfig, err := providerconfig.NewProviderConfigFromMachineTemplate(*template); err == nil {
+			platform = string(providerConfig.Type())
+		}
+	}
+
+	machineReplacementsTotal.WithLabelValues(platform, string(cpms.Spec.Strategy.Type)).Inc()
+}
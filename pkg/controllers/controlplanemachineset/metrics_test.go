@@ -0,0 +1,57 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controlplanemachineset
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	machinev1 "github.com/openshift/api/machine/v1"
+	"github.com/openshift/cluster-control-plane-machine-set-operator/pkg/test/resourcebuilder"
+	dto "github.com/prometheus/client_model/go"
+)
+
+var _ = Describe("Metrics", func() {
+	Context("recordMachineReplacement", func() {
+		// replacementsFor returns the current value of the machine replacements counter for the labels.
+		replacementsFor := func(platform, strategy string) float64 {
+			metric := &dto.Metric{}
+			Expect(machineReplacementsTotal.WithLabelValues(platform, strategy).Write(metric)).To(Succeed())
+
+			return metric.GetCounter().GetValue()
+		}
+
+		It("should increment the counter for the platform and strategy", func() {
+			cpms := resourcebuilder.ControlPlaneMachineSet().WithStrategyType(machinev1.OnDelete).Build()
+
+			before := replacementsFor("AWS", "OnDelete")
+			recordMachineReplacement(cpms)
+
+			Expect(replacementsFor("AWS", "OnDelete")).To(Equal(before + 1))
+		})
+
+		It("should record an unknown platform when the template has no provider spec", func() {
+			cpms := resourcebuilder.ControlPlaneMachineSet().Build()
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine = nil
+
+			before := replacementsFor(unknownPlatformMetricValue, "RollingUpdate")
+			recordMachineReplacement(cpms)
+
+			Expect(replacementsFor(unknownPlatformMetricValue, "RollingUpdate")).To(Equal(before + 1))
+		})
+	})
+})
@@ -19,6 +19,7 @@ package controlplanemachineset
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	machinev1 "github.com/openshift/api/machine/v1"
@@ -42,6 +43,13 @@ const (
 
 	// notUpdatingStatus is a log message used to inform users that the ControlPlaneMachineSet status is not being updated.
 	notUpdatingStatus = "No update to control plane machine set status required"
+
+	// updatingLastRolloutTime is a log message used to inform users that the last rollout time annotation is being updated.
+	updatingLastRolloutTime = "Updating control plane machine set last rollout time"
+
+	// lastRolloutTimeAnnotationKey is the annotation used to record the time at which the ControlPlaneMachineSet
+	// last observed all of its replicas become up to date. The value is an RFC3339 timestamp.
+	lastRolloutTimeAnnotationKey = "machine.openshift.io/control-plane-machine-set-last-rollout-time"
 )
 
 // updateControlPlaneMachineSetStatus ensures that the status of the ControlPlaneMachineSet is up to date after
@@ -68,6 +76,40 @@ func (r *ControlPlaneMachineSetReconciler) updateControlPlaneMachineSetStatus(ct
 	return nil
 }
 
+// updateLastRolloutTimeAnnotation records the time at which the ControlPlaneMachineSet last completed a rollout.
+// A rollout is complete when the Progressing condition transitions to false because all replicas are updated,
+// so the annotation mirrors the last transition time of that condition.
+// The annotation is not a part of the status, so it is patched separately, after the status has been updated.
+func (r *ControlPlaneMachineSetReconciler) updateLastRolloutTimeAnnotation(ctx context.Context, logger logr.Logger, cpms *machinev1.ControlPlaneMachineSet) error {
+	progressingCondition := meta.FindStatusCondition(cpms.Status.Conditions, conditionProgressing)
+	if progressingCondition == nil || progressingCondition.Status != metav1.ConditionFalse || progressingCondition.Reason != reasonAllReplicasUpdated {
+		return nil
+	}
+
+	lastRolloutTime := progressingCondition.LastTransitionTime.UTC().Format(time.RFC3339)
+	if cpms.GetAnnotations()[lastRolloutTimeAnnotationKey] == lastRolloutTime {
+		return nil
+	}
+
+	patchBase := client.MergeFrom(cpms.DeepCopy())
+
+	annotations := cpms.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[lastRolloutTimeAnnotationKey] = lastRolloutTime
+	cpms.SetAnnotations(annotations)
+
+	if err := r.Patch(ctx, cpms, patchBase); err != nil {
+		return fmt.Errorf("failed to update last rollout time for control plane machine set object: %w", err)
+	}
+
+	logger.V(3).Info(updatingLastRolloutTime, "lastRolloutTime", lastRolloutTime)
+
+	return nil
+}
+
 // reconcileStatusWithMachineInfo takes the information gathered in the machineInfos and reconciles the status of the
 // ControlPlaneMachineSet to match the data gathered.
 // In particular, it will update the ObservedGeneration, Replicas, ReadyReplicas, UnavailableReplicas and UpdatedReplicas
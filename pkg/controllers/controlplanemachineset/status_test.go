@@ -17,6 +17,8 @@ limitations under the License.
 package controlplanemachineset
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -144,6 +146,113 @@ var _ = Describe("Status", func() {
 		})
 	})
 
+	Context("updateLastRolloutTimeAnnotation", func() {
+		var namespaceName string
+		var logger test.TestLogger
+		var reconciler *ControlPlaneMachineSetReconciler
+		var cpms *machinev1.ControlPlaneMachineSet
+
+		transitionTime := metav1.NewTime(time.Date(2022, time.October, 1, 12, 0, 0, 0, time.UTC))
+
+		BeforeEach(func() {
+			By("Setting up a namespace for the test")
+			ns := resourcebuilder.Namespace().WithGenerateName("control-plane-machine-set-controller-").Build()
+			Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+			namespaceName = ns.GetName()
+
+			By("Setting up the reconciler")
+			logger = test.NewTestLogger()
+			reconciler = &ControlPlaneMachineSetReconciler{
+				Namespace:      namespaceName,
+				Scheme:         testScheme,
+				Client:         k8sClient,
+				UncachedClient: k8sClient,
+			}
+
+			By("Setting up supporting resources")
+			cpms = resourcebuilder.ControlPlaneMachineSet().WithNamespace(namespaceName).Build()
+			Expect(k8sClient.Create(ctx, cpms)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			test.CleanupResources(Default, ctx, cfg, k8sClient, namespaceName,
+				&machinev1.ControlPlaneMachineSet{},
+			)
+		})
+
+		Context("when all replicas are updated", func() {
+			BeforeEach(func() {
+				cpms.Status.Conditions = []metav1.Condition{
+					{
+						Type:               conditionProgressing,
+						Status:             metav1.ConditionFalse,
+						Reason:             reasonAllReplicasUpdated,
+						LastTransitionTime: transitionTime,
+					},
+				}
+
+				Expect(reconciler.updateLastRolloutTimeAnnotation(ctx, logger.Logger(), cpms)).To(Succeed())
+			})
+
+			It("sets the last rollout time on the API", func() {
+				Eventually(komega.Object(cpms)).Should(HaveField("ObjectMeta.Annotations",
+					HaveKeyWithValue(lastRolloutTimeAnnotationKey, "2022-10-01T12:00:00Z"),
+				))
+			})
+
+			It("should log the last rollout time", func() {
+				Expect(logger.Entries()).To(ConsistOf(test.LogEntry{
+					Level: 3,
+					KeysAndValues: []interface{}{
+						"lastRolloutTime", "2022-10-01T12:00:00Z",
+					},
+					Message: updatingLastRolloutTime,
+				}))
+			})
+		})
+
+		Context("when the last rollout time is already recorded", func() {
+			BeforeEach(func() {
+				cpms.SetAnnotations(map[string]string{lastRolloutTimeAnnotationKey: "2022-10-01T12:00:00Z"})
+				cpms.Status.Conditions = []metav1.Condition{
+					{
+						Type:               conditionProgressing,
+						Status:             metav1.ConditionFalse,
+						Reason:             reasonAllReplicasUpdated,
+						LastTransitionTime: transitionTime,
+					},
+				}
+
+				Expect(reconciler.updateLastRolloutTimeAnnotation(ctx, logger.Logger(), cpms)).To(Succeed())
+			})
+
+			It("should not log", func() {
+				Expect(logger.Entries()).To(BeEmpty())
+			})
+		})
+
+		Context("when replicas need an update", func() {
+			BeforeEach(func() {
+				cpms.Status.Conditions = []metav1.Condition{
+					{
+						Type:               conditionProgressing,
+						Status:             metav1.ConditionTrue,
+						Reason:             reasonNeedsUpdateReplicas,
+						LastTransitionTime: transitionTime,
+					},
+				}
+
+				Expect(reconciler.updateLastRolloutTimeAnnotation(ctx, logger.Logger(), cpms)).To(Succeed())
+			})
+
+			It("does not set the last rollout time on the API", func() {
+				Consistently(komega.Object(cpms)).Should(HaveField("ObjectMeta.Annotations",
+					Not(HaveKey(lastRolloutTimeAnnotationKey)),
+				))
+			})
+		})
+	})
+
 	Context("reconcileStatusWithMachineInfo", func() {
 		type reconcileStatusTableInput struct {
 			cpmsBuilder    resourcebuilder.ControlPlaneMachineSetInterface
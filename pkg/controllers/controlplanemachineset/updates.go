@@ -65,6 +65,10 @@ const (
 	noCapacityForExpansion = "Insufficient capacity for expansion, maximum surge has been reached." +
 		" Cannot create a replacement Machine at this time."
 
+	// notRemovingBelowQuorum is a log message used to inform the user that an old Machine, which has been replaced,
+	// has not been deleted because too few control plane machines are ready to maintain quorum.
+	notRemovingBelowQuorum = "Control plane is below quorum, not removing replaced machine"
+
 	// removingOldMachine is a log message used to inform the user that an old Machine has been
 	// deleted as a part of the rollout operation.
 	removingOldMachine = "Removing old machine"
@@ -170,6 +174,10 @@ func (r *ControlPlaneMachineSetReconciler) reconcileMachineRollingUpdate(ctx con
 	// No check for early stoppage is done here,
 	// as deletions can continue even if the maxSurge has been already reached.
 	surgeCount := deviseExistingSurge(cpms, sortedIndexedMs)
+	// While below quorum, replaced machines are not removed, as removing a ready control plane machine
+	// risks losing the control plane entirely. Machines are still created for missing and failed indexes,
+	// as these are required to restore quorum.
+	belowQuorum := isBelowQuorum(cpms, sortedIndexedMs)
 
 	var updated bool
 
@@ -177,7 +185,7 @@ func (r *ControlPlaneMachineSetReconciler) reconcileMachineRollingUpdate(ctx con
 		idx := indexToMachines.index
 		machines := indexToMachines.machineInfos
 
-		if done, result, err := r.deleteReplacedMachines(ctx, logger, machineProvider, machines); err != nil {
+		if done, result, err := r.deleteReplacedMachines(ctx, logger, machineProvider, machines, belowQuorum); err != nil {
 			return result, err
 		} else if done {
 			updated = true
@@ -187,7 +195,7 @@ func (r *ControlPlaneMachineSetReconciler) reconcileMachineRollingUpdate(ctx con
 			updated = true
 		}
 
-		if done, result, err := r.createRollingUpdateReplacementMachines(ctx, logger, machineProvider, machines, idx, maxSurge, &surgeCount); err != nil {
+		if done, result, err := r.createRollingUpdateReplacementMachines(ctx, logger, cpms, machineProvider, machines, idx, maxSurge, &surgeCount); err != nil {
 			return result, err
 		} else if done {
 			updated = true
@@ -237,7 +245,7 @@ func (r *ControlPlaneMachineSetReconciler) reconcileMachineOnDeleteUpdate(ctx co
 			updated = true
 		}
 
-		if done, result, err := r.createOnDeleteReplacementMachines(ctx, logger, machineProvider, machines, idx); err != nil {
+		if done, result, err := r.createOnDeleteReplacementMachines(ctx, logger, cpms, machineProvider, machines, idx); err != nil {
 			return result, err
 		} else if done {
 			updated = true
@@ -304,18 +312,22 @@ func (r *ControlPlaneMachineSetReconciler) waitForPendingMachines(logger logr.Lo
 	return false
 }
 
-func (r *ControlPlaneMachineSetReconciler) deleteReplacedMachines(ctx context.Context, logger logr.Logger, machineProvider machineproviders.MachineProvider, machines []machineproviders.MachineInfo) (bool, ctrl.Result, error) {
+func (r *ControlPlaneMachineSetReconciler) deleteReplacedMachines(ctx context.Context, logger logr.Logger, machineProvider machineproviders.MachineProvider, machines []machineproviders.MachineInfo, belowQuorum bool) (bool, ctrl.Result, error) {
 	machinesNeedingReplacement := needReplacementMachines(machines)
 	machinesUpdated := updatedMachines(machines)
 	machinesOutdatedNonReady := nonReadyMachines(machinesNeedingReplacement)
 
-	var toDeleteMachine machineproviders.MachineInfo
+	var (
+		toDeleteMachine machineproviders.MachineInfo
+		deletionReason  string
+	)
 
 	if hasAny(machinesNeedingReplacement) && hasAny(machinesUpdated) {
 		// The Outdated Machine still exists for this index,
 		// but an Updated replacement exists for it.
 		// Thus it is safe to trigger its Deletion.
 		toDeleteMachine = machinesNeedingReplacement[0]
+		deletionReason = machineproviders.MachineDeletionReasonReplaced
 	}
 
 	if hasAny(machinesOutdatedNonReady) {
@@ -325,14 +337,23 @@ func (r *ControlPlaneMachineSetReconciler) deleteReplacedMachines(ctx context.Co
 		// but the configuration is broken or the Machine simply never becomes Ready.
 		// This means the Machine should be deleted to make room for a "third generation" replacement machine.
 		toDeleteMachine = machinesOutdatedNonReady[0]
+		deletionReason = machineproviders.MachineDeletionReasonOutdatedNotReady
 	}
 
 	// Check if any Machine was deemed for deletion.
 	if toDeleteMachine.MachineRef != nil {
 		logger := logger.WithValues("index", toDeleteMachine.Index, "namespace", r.Namespace, "name", toDeleteMachine.MachineRef.ObjectMeta.Name)
 
+		if belowQuorum && toDeleteMachine.Ready && !isDeletedMachine(toDeleteMachine) {
+			// Removing a Ready Machine while below quorum risks losing the control plane entirely.
+			// Outdated Machines that are not Ready may still be removed, as they do not contribute to quorum.
+			logger.V(2).Info(notRemovingBelowQuorum)
+
+			return false, ctrl.Result{}, nil
+		}
+
 		if !isDeletedMachine(toDeleteMachine) {
-			result, err := deleteMachine(ctx, logger, machineProvider, toDeleteMachine, r.Namespace)
+			result, err := deleteMachine(ctx, logger, machineProvider, toDeleteMachine, deletionReason, r.Namespace)
 			if err != nil {
 				return false, result, err
 			}
@@ -351,13 +372,13 @@ func (r *ControlPlaneMachineSetReconciler) deleteReplacedMachines(ctx context.Co
 // this function will attempt to create new machines when none are available
 // in the machine info, or when there is a machine that needs an update for
 // which no replacement has been created.
-func (r *ControlPlaneMachineSetReconciler) createOnDeleteReplacementMachines(ctx context.Context, logger logr.Logger, machineProvider machineproviders.MachineProvider, machines []machineproviders.MachineInfo, idx int32) (bool, ctrl.Result, error) {
+func (r *ControlPlaneMachineSetReconciler) createOnDeleteReplacementMachines(ctx context.Context, logger logr.Logger, cpms *machinev1.ControlPlaneMachineSet, machineProvider machineproviders.MachineProvider, machines []machineproviders.MachineInfo, idx int32) (bool, ctrl.Result, error) {
 	if isEmpty(machines) {
 		// No Machines exist for this index.
 		// Trigger a Machine creation.
 		logger := logger.WithValues("index", idx, "namespace", r.Namespace, "name", unknownMachineName)
 
-		result, err := r.createMachine(ctx, logger, machineProvider, idx)
+		result, err := r.createMachine(ctx, logger, cpms, machineProvider, idx)
 		if err != nil {
 			return false, result, err
 		}
@@ -372,7 +393,7 @@ func (r *ControlPlaneMachineSetReconciler) createOnDeleteReplacementMachines(ctx
 
 		if isDeletedMachine(machines[0]) {
 			// if deleted create the replacement
-			result, err := r.createMachine(ctx, logger, machineProvider, idx)
+			result, err := r.createMachine(ctx, logger, cpms, machineProvider, idx)
 			if err != nil {
 				return false, result, err
 			}
@@ -393,7 +414,7 @@ func (r *ControlPlaneMachineSetReconciler) createOnDeleteReplacementMachines(ctx
 // in the machine info, or when there is a machine that needs an update for
 // which no replacement has been created. in all cases it will observe the
 // surge parameters when creating new machines.
-func (r *ControlPlaneMachineSetReconciler) createRollingUpdateReplacementMachines(ctx context.Context, logger logr.Logger, machineProvider machineproviders.MachineProvider, machines []machineproviders.MachineInfo, idx int32, maxSurge int, surgeCount *int) (bool, ctrl.Result, error) {
+func (r *ControlPlaneMachineSetReconciler) createRollingUpdateReplacementMachines(ctx context.Context, logger logr.Logger, cpms *machinev1.ControlPlaneMachineSet, machineProvider machineproviders.MachineProvider, machines []machineproviders.MachineInfo, idx int32, maxSurge int, surgeCount *int) (bool, ctrl.Result, error) {
 	machinesNeedingReplacement := needReplacementMachines(machines)
 	machinesPending := pendingMachines(machines)
 	machinesUpdatedNonDeleted := updatedNonDeletedMachines(machines)
@@ -403,7 +424,7 @@ func (r *ControlPlaneMachineSetReconciler) createRollingUpdateReplacementMachine
 		// Trigger a Machine creation.
 		logger := logger.WithValues("index", idx, "namespace", r.Namespace, "name", unknownMachineName)
 
-		result, err := r.createMachineWithSurge(ctx, logger, machineProvider, idx, maxSurge, surgeCount)
+		result, err := r.createMachineWithSurge(ctx, logger, cpms, machineProvider, idx, maxSurge, surgeCount)
 		if err != nil {
 			return false, result, err
 		}
@@ -419,7 +440,7 @@ func (r *ControlPlaneMachineSetReconciler) createRollingUpdateReplacementMachine
 		outdatedMachine := machinesNeedingReplacement[0]
 		logger := logger.WithValues("index", outdatedMachine.Index, "namespace", r.Namespace, "name", outdatedMachine.MachineRef.ObjectMeta.Name)
 
-		result, err := r.createMachineWithSurge(ctx, logger, machineProvider, outdatedMachine.Index, maxSurge, surgeCount)
+		result, err := r.createMachineWithSurge(ctx, logger, cpms, machineProvider, outdatedMachine.Index, maxSurge, surgeCount)
 		if err != nil {
 			return false, result, err
 		}
@@ -430,9 +451,9 @@ func (r *ControlPlaneMachineSetReconciler) createRollingUpdateReplacementMachine
 	return false, ctrl.Result{}, nil
 }
 
-// deleteMachine deletes the Machine provided.
-func deleteMachine(ctx context.Context, logger logr.Logger, machineProvider machineproviders.MachineProvider, outdatedMachine machineproviders.MachineInfo, namespace string) (ctrl.Result, error) {
-	if err := machineProvider.DeleteMachine(ctx, logger, outdatedMachine.MachineRef); err != nil {
+// deleteMachine deletes the Machine provided, recording the reason for the deletion on the Machine.
+func deleteMachine(ctx context.Context, logger logr.Logger, machineProvider machineproviders.MachineProvider, outdatedMachine machineproviders.MachineInfo, reason, namespace string) (ctrl.Result, error) {
+	if err := machineProvider.DeleteMachine(ctx, logger, outdatedMachine.MachineRef, reason); err != nil {
 		werr := fmt.Errorf("error deleting Machine %s/%s: %w", namespace, outdatedMachine.MachineRef.ObjectMeta.Name, err)
 		logger.Error(werr, errorDeletingMachine)
 
@@ -444,8 +465,8 @@ func deleteMachine(ctx context.Context, logger logr.Logger, machineProvider mach
 	return ctrl.Result{}, nil
 }
 
-// createMachine creates the Machine provided.
-func (r *ControlPlaneMachineSetReconciler) createMachine(ctx context.Context, logger logr.Logger, machineProvider machineproviders.MachineProvider, idx int32) (ctrl.Result, error) {
+// createMachine creates the Machine provided, recording the replacement in the machine replacements metric.
+func (r *ControlPlaneMachineSetReconciler) createMachine(ctx context.Context, logger logr.Logger, cpms *machinev1.ControlPlaneMachineSet, machineProvider machineproviders.MachineProvider, idx int32) (ctrl.Result, error) {
 	// Check if a replacement machine already exists and
 	// was not previously detected due to potential stale cache.
 	exists, err := r.checkForExistingReplacement(ctx, logger, machineProvider, idx)
@@ -468,6 +489,8 @@ func (r *ControlPlaneMachineSetReconciler) createMachine(ctx context.Context, lo
 		return ctrl.Result{}, werr
 	}
 
+	recordMachineReplacement(cpms)
+
 	logger.V(2).Info(createdReplacement)
 
 	return ctrl.Result{}, nil
@@ -476,7 +499,7 @@ func (r *ControlPlaneMachineSetReconciler) createMachine(ctx context.Context, lo
 // createMachineWithSurge creates the Machine provided while observing the surge count.
 // This function will not create machines if the current surgeCount is greater
 // than the maxSurge. If it does create a machine, it will increase the surgeCount.
-func (r *ControlPlaneMachineSetReconciler) createMachineWithSurge(ctx context.Context, logger logr.Logger, machineProvider machineproviders.MachineProvider, idx int32, maxSurge int, surgeCount *int) (ctrl.Result, error) {
+func (r *ControlPlaneMachineSetReconciler) createMachineWithSurge(ctx context.Context, logger logr.Logger, cpms *machinev1.ControlPlaneMachineSet, machineProvider machineproviders.MachineProvider, idx int32, maxSurge int, surgeCount *int) (ctrl.Result, error) {
 	// Check if a surge in Machines is allowed.
 	if *surgeCount >= maxSurge {
 		// No more room to surge
@@ -487,7 +510,7 @@ func (r *ControlPlaneMachineSetReconciler) createMachineWithSurge(ctx context.Co
 
 	// There is still room to surge,
 	// trigger a Replacement Machine creation.
-	result, err := r.createMachine(ctx, logger, machineProvider, idx)
+	result, err := r.createMachine(ctx, logger, cpms, machineProvider, idx)
 	if err != nil {
 		return result, err
 	}
@@ -668,6 +691,39 @@ func deviseExistingSurge(cpms *machinev1.ControlPlaneMachineSet, mis []indexToMa
 	return currentReplicas - desiredReplicas
 }
 
+// controlPlaneQuorum computes the number of indexes with a Ready Machine, the number of desired indexes that have no
+// Machines at all, and the number of indexes with a Ready Machine required to maintain quorum.
+// A missing index never has a Ready Machine, so it counts against quorum.
+func controlPlaneQuorum(cpms *machinev1.ControlPlaneMachineSet, mis []indexToMachineInfos) (int32, int32, int32) {
+	var readyIndexes, presentIndexes int32
+
+	for _, mi := range mis {
+		if isEmpty(mi.machineInfos) {
+			continue
+		}
+
+		presentIndexes++
+
+		if hasAny(readyMachines(mi.machineInfos)) {
+			readyIndexes++
+		}
+	}
+
+	missingIndexes := *cpms.Spec.Replicas - presentIndexes
+	if missingIndexes < 0 {
+		missingIndexes = 0
+	}
+
+	return readyIndexes, missingIndexes, *cpms.Spec.Replicas/2 + 1
+}
+
+// isBelowQuorum checks if fewer indexes have a Ready Machine than are required to maintain quorum.
+func isBelowQuorum(cpms *machinev1.ControlPlaneMachineSet, mis []indexToMachineInfos) bool {
+	readyIndexes, _, quorum := controlPlaneQuorum(cpms, mis)
+
+	return readyIndexes < quorum
+}
+
 // hasAny checks if a MachineInfo slice contains at least 1 element.
 func hasAny(machinesInfo []machineproviders.MachineInfo) bool {
 	return len(machinesInfo) > 0
@@ -56,6 +56,12 @@ const (
 	clusterMachineTypeLabelKey           = "machine.openshift.io/cluster-api-machine-type"
 	clusterMachineLabelValueMaster       = "master"
 	clusterMachineLabelValueControlPlane = "control-plane"
+	// controlPlaneMachineSetOriginAnnotationKey is the annotation used to record
+	// how the ControlPlaneMachineSet came to exist within the cluster.
+	controlPlaneMachineSetOriginAnnotationKey = "machine.openshift.io/control-plane-machine-set-origin"
+	// controlPlaneMachineSetOriginGenerated is the origin annotation value for
+	// ControlPlaneMachineSets created by this generator.
+	controlPlaneMachineSetOriginGenerated = "generated"
 )
 
 const (
@@ -66,6 +72,7 @@ const (
 	controlPlaneMachineSetOutdated              = "Control plane machine set is outdated"
 	controlPlaneMachineSetCreated               = "Created updated control plane machine set"
 	controlPlaneMachineSetDeleted               = "Deleted outdated control plane machine set"
+	controlPlaneMachineSetOriginAnnotated       = "Added origin annotation to control plane machine set"
 	controlPlaneMachineSetReconciling           = "Reconciling control plane machine set"
 	controlPlaneMachineSetReconciliationFinshed = "Finished reconciling control plane machine set"
 )
@@ -204,11 +211,45 @@ func (r *ControlPlaneMachineSetGeneratorReconciler) reconcile(ctx context.Contex
 		return r.recreateControlPlaneMachineSet(ctx, logger, cpms, generatedCPMS)
 	}
 
+	if err := r.ensureOriginAnnotation(ctx, logger, cpms); err != nil {
+		return reconcile.Result{}, fmt.Errorf("unable to ensure control plane machine set origin: %w", err)
+	}
+
 	logger.V(3).Info(controlPlaneMachineSetUpToDate)
 
 	return reconcile.Result{}, nil
 }
 
+// ensureOriginAnnotation adds the origin annotation to an up to date, inactive, ControlPlaneMachineSet that does not
+// have one. ControlPlaneMachineSets generated before the origin annotation was introduced do not carry it.
+// As any inactive ControlPlaneMachineSet that does not match the generated one is recreated by the generator,
+// an up to date, inactive, ControlPlaneMachineSet is treated as generated.
+// An existing origin annotation is never overwritten.
+func (r *ControlPlaneMachineSetGeneratorReconciler) ensureOriginAnnotation(ctx context.Context, logger logr.Logger,
+	cpms *machinev1.ControlPlaneMachineSet) error {
+	if _, ok := cpms.GetAnnotations()[controlPlaneMachineSetOriginAnnotationKey]; ok {
+		return nil
+	}
+
+	patchBase := client.MergeFrom(cpms.DeepCopy())
+
+	annotations := cpms.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[controlPlaneMachineSetOriginAnnotationKey] = controlPlaneMachineSetOriginGenerated
+	cpms.SetAnnotations(annotations)
+
+	if err := r.Patch(ctx, cpms, patchBase); err != nil {
+		return fmt.Errorf("unable to patch control plane machine set: %w", err)
+	}
+
+	logger.V(1).Info(controlPlaneMachineSetOriginAnnotated)
+
+	return nil
+}
+
 // generateControlPlaneMachineSet generates a control plane machine set based on the current cluster state.
 func (r *ControlPlaneMachineSetGeneratorReconciler) generateControlPlaneMachineSet(logger logr.Logger,
 	platformType configv1.PlatformType, machines []machinev1beta1.Machine, machineSets []machinev1beta1.MachineSet) (*machinev1.ControlPlaneMachineSet, error) {
@@ -238,7 +279,9 @@ func (r *ControlPlaneMachineSetGeneratorReconciler) generateControlPlaneMachineS
 		return nil, errUnsupportedPlatform
 	}
 
-	cpmsApplyConfig := machinev1builder.ControlPlaneMachineSet(clusterControlPlaneMachineSetName, r.Namespace).WithSpec(&cpmsSpecApplyConfig)
+	cpmsApplyConfig := machinev1builder.ControlPlaneMachineSet(clusterControlPlaneMachineSetName, r.Namespace).
+		WithAnnotations(map[string]string{controlPlaneMachineSetOriginAnnotationKey: controlPlaneMachineSetOriginGenerated}).
+		WithSpec(&cpmsSpecApplyConfig)
 
 	newCPMS := &machinev1.ControlPlaneMachineSet{}
 	if err := convertViaJSON(*cpmsApplyConfig, newCPMS); err != nil {
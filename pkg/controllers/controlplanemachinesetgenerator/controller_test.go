@@ -413,6 +413,7 @@ var _ = Describe("controlplanemachinesetgenerator controller on AWS", func() {
 					Eventually(komega.Get(cpms)).Should(Succeed())
 					Expect(cpms.Spec.State).To(Equal(machinev1.ControlPlaneMachineSetStateInactive))
 					Expect(*cpms.Spec.Replicas).To(Equal(int32(3)))
+					Expect(cpms.Annotations).To(HaveKeyWithValue(controlPlaneMachineSetOriginAnnotationKey, controlPlaneMachineSetOriginGenerated))
 				})
 
 				It("should create the ControlPlaneMachineSet with the provider spec matching the youngest machine provider spec", func() {
@@ -468,6 +469,7 @@ var _ = Describe("controlplanemachinesetgenerator controller on AWS", func() {
 					Eventually(komega.Get(cpms)).Should(Succeed())
 					Expect(cpms.Spec.State).To(Equal(machinev1.ControlPlaneMachineSetStateInactive))
 					Expect(*cpms.Spec.Replicas).To(Equal(int32(3)))
+					Expect(cpms.Annotations).To(HaveKeyWithValue(controlPlaneMachineSetOriginAnnotationKey, controlPlaneMachineSetOriginGenerated))
 				})
 
 				It("should create the ControlPlaneMachineSet with the provider spec matching the youngest machine provider spec", func() {
@@ -648,6 +650,7 @@ var _ = Describe("controlplanemachinesetgenerator controller on AWS", func() {
 				// Create an Inactive ControlPlaneMachineSet with a Provider Spec that
 				// match the youngest control plane machine (i.e. it's up to date).
 				cpms = cpmsInactive5FDsBuilderAWS.WithNamespace(namespaceName).Build()
+				cpms.SetAnnotations(map[string]string{controlPlaneMachineSetOriginAnnotationKey: controlPlaneMachineSetOriginGenerated})
 				Expect(k8sClient.Create(ctx, cpms)).To(Succeed())
 			})
 
@@ -658,6 +661,27 @@ var _ = Describe("controlplanemachinesetgenerator controller on AWS", func() {
 
 		})
 
+		Context("with state Inactive, up to date and without the origin annotation", func() {
+			BeforeEach(func() {
+				By("Creating an up to date and Inactive Control Plane Machine Set without the origin annotation")
+				// Create an Inactive ControlPlaneMachineSet with a Provider Spec that
+				// match the youngest control plane machine (i.e. it's up to date),
+				// as generated before the origin annotation was introduced.
+				cpms = cpmsInactive5FDsBuilderAWS.WithNamespace(namespaceName).Build()
+				Expect(k8sClient.Create(ctx, cpms)).To(Succeed())
+			})
+
+			It("should add the origin annotation", func() {
+				Eventually(komega.Object(cpms)).Should(HaveField("ObjectMeta.Annotations",
+					HaveKeyWithValue(controlPlaneMachineSetOriginAnnotationKey, controlPlaneMachineSetOriginGenerated)))
+			})
+
+			It("should not recreate the ControlPlaneMachineSet", func() {
+				cpmsUID := cpms.ObjectMeta.UID
+				Consistently(komega.Object(cpms)).Should(HaveField("ObjectMeta.UID", cpmsUID))
+			})
+		})
+
 		Context("with state Active and outdated", func() {
 			BeforeEach(func() {
 				By("Creating an outdated and Active Control Plane Machine Set")
@@ -987,6 +1011,7 @@ var _ = Describe("controlplanemachinesetgenerator controller on Azure", func() {
 					Eventually(komega.Get(cpms)).Should(Succeed())
 					Expect(cpms.Spec.State).To(Equal(machinev1.ControlPlaneMachineSetStateInactive))
 					Expect(*cpms.Spec.Replicas).To(Equal(int32(3)))
+					Expect(cpms.Annotations).To(HaveKeyWithValue(controlPlaneMachineSetOriginAnnotationKey, controlPlaneMachineSetOriginGenerated))
 				})
 
 				It("should create the ControlPlaneMachineSet with the provider spec matching the youngest machine provider spec", func() {
@@ -1041,6 +1066,7 @@ var _ = Describe("controlplanemachinesetgenerator controller on Azure", func() {
 					Eventually(komega.Get(cpms)).Should(Succeed())
 					Expect(cpms.Spec.State).To(Equal(machinev1.ControlPlaneMachineSetStateInactive))
 					Expect(*cpms.Spec.Replicas).To(Equal(int32(3)))
+					Expect(cpms.Annotations).To(HaveKeyWithValue(controlPlaneMachineSetOriginAnnotationKey, controlPlaneMachineSetOriginGenerated))
 				})
 
 				It("should create the ControlPlaneMachineSet with the provider spec matching the youngest machine provider spec", func() {
@@ -1219,6 +1245,7 @@ var _ = Describe("controlplanemachinesetgenerator controller on Azure", func() {
 				// Create an Inactive ControlPlaneMachineSet with a Provider Spec that
 				// match the youngest control plane machine (i.e. it's up to date).
 				cpms = cpmsInactive5FDsBuilderAzure.WithNamespace(namespaceName).Build()
+				cpms.SetAnnotations(map[string]string{controlPlaneMachineSetOriginAnnotationKey: controlPlaneMachineSetOriginGenerated})
 				Expect(k8sClient.Create(ctx, cpms)).To(Succeed())
 			})
 
@@ -1558,6 +1585,7 @@ var _ = Describe("controlplanemachinesetgenerator controller on GCP", func() {
 					Eventually(komega.Get(cpms)).Should(Succeed())
 					Expect(cpms.Spec.State).To(Equal(machinev1.ControlPlaneMachineSetStateInactive))
 					Expect(*cpms.Spec.Replicas).To(Equal(int32(3)))
+					Expect(cpms.Annotations).To(HaveKeyWithValue(controlPlaneMachineSetOriginAnnotationKey, controlPlaneMachineSetOriginGenerated))
 				})
 
 				It("should create the ControlPlaneMachineSet with the provider spec matching the youngest machine provider spec", func() {
@@ -1612,6 +1640,7 @@ var _ = Describe("controlplanemachinesetgenerator controller on GCP", func() {
 					Eventually(komega.Get(cpms)).Should(Succeed())
 					Expect(cpms.Spec.State).To(Equal(machinev1.ControlPlaneMachineSetStateInactive))
 					Expect(*cpms.Spec.Replicas).To(Equal(int32(3)))
+					Expect(cpms.Annotations).To(HaveKeyWithValue(controlPlaneMachineSetOriginAnnotationKey, controlPlaneMachineSetOriginGenerated))
 				})
 
 				It("should create the ControlPlaneMachineSet with the provider spec matching the youngest machine provider spec", func() {
@@ -1790,6 +1819,7 @@ var _ = Describe("controlplanemachinesetgenerator controller on GCP", func() {
 				// Create an Inactive ControlPlaneMachineSet with a Provider Spec that
 				// match the youngest control plane machine (i.e. it's up to date).
 				cpms = cpmsInactive5FDsBuilderGCP.WithNamespace(namespaceName).Build()
+				cpms.SetAnnotations(map[string]string{controlPlaneMachineSetOriginAnnotationKey: controlPlaneMachineSetOriginGenerated})
 				Expect(k8sClient.Create(ctx, cpms)).To(Succeed())
 			})
 
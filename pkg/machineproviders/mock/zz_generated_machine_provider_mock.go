@@ -52,17 +52,17 @@ func (mr *MockMachineProviderMockRecorder) CreateMachine(arg0, arg1, arg2 interf
 }
 
 // DeleteMachine mocks base method.
-func (m *MockMachineProvider) DeleteMachine(arg0 context.Context, arg1 logr.Logger, arg2 *machineproviders.ObjectRef) error {
+func (m *MockMachineProvider) DeleteMachine(arg0 context.Context, arg1 logr.Logger, arg2 *machineproviders.ObjectRef, arg3 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteMachine", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "DeleteMachine", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteMachine indicates an expected call of DeleteMachine.
-func (mr *MockMachineProviderMockRecorder) DeleteMachine(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockMachineProviderMockRecorder) DeleteMachine(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMachine", reflect.TypeOf((*MockMachineProvider)(nil).DeleteMachine), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMachine", reflect.TypeOf((*MockMachineProvider)(nil).DeleteMachine), arg0, arg1, arg2, arg3)
 }
 
 // GetMachineInfos mocks base method.
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
 	configv1 "github.com/openshift/api/config/v1"
 	machinev1 "github.com/openshift/api/machine/v1"
@@ -189,9 +190,12 @@ func newGCPFailureDomains(failureDomains machinev1.FailureDomains) ([]FailureDom
 }
 
 // NewAWSFailureDomain creates an AWS failure domain from the machinev1.AWSFailureDomain.
+// The availability zone is normalized, see normalizeZone.
 // Note this is exported to allow other packages to construct individual failure domains
 // in tests.
 func NewAWSFailureDomain(fd machinev1.AWSFailureDomain) FailureDomain {
+	fd.Placement.AvailabilityZone = normalizeZone(fd.Placement.AvailabilityZone)
+
 	return &failureDomain{
 		platformType: configv1.AWSPlatformType,
 		aws:          fd,
@@ -199,7 +203,10 @@ func NewAWSFailureDomain(fd machinev1.AWSFailureDomain) FailureDomain {
 }
 
 // NewAzureFailureDomain creates an Azure failure domain from the machinev1.AzureFailureDomain.
+// The zone is normalized, see normalizeZone.
 func NewAzureFailureDomain(fd machinev1.AzureFailureDomain) FailureDomain {
+	fd.Zone = normalizeZone(fd.Zone)
+
 	return &failureDomain{
 		platformType: configv1.AzurePlatformType,
 		azure:        fd,
@@ -207,7 +214,10 @@ func NewAzureFailureDomain(fd machinev1.AzureFailureDomain) FailureDomain {
 }
 
 // NewGCPFailureDomain creates a GCP failure domain from the machinev1.GCPFailureDomain.
+// The zone is normalized, see normalizeZone.
 func NewGCPFailureDomain(fd machinev1.GCPFailureDomain) FailureDomain {
+	fd.Zone = normalizeZone(fd.Zone)
+
 	return &failureDomain{
 		platformType: configv1.GCPPlatformType,
 		gcp:          fd,
@@ -219,6 +229,15 @@ func NewGenericFailureDomain() FailureDomain {
 	return failureDomain{}
 }
 
+// normalizeZone removes surrounding whitespace from the zone and lower cases it.
+// Zone names are lower case on all supported platforms, so a zone copied into the
+// failure domains with stray whitespace, or in a different case, still refers to the
+// same zone. Normalizing the zone means it compares equal to the zone of existing
+// machines, and that the normalized zone is injected into new machines.
+func normalizeZone(zone string) string {
+	return strings.ToLower(strings.TrimSpace(zone))
+}
+
 // azString formats AvailabilityZone for awsFailureDomainToString function.
 func azString(az string) string {
 	if az == "" {
@@ -417,4 +417,30 @@ var _ = Describe("FailureDomains", func() {
 
 	})
 
+	Context("with zones that differ in case and whitespace", func() {
+		It("returns true for Equal between AWS failure domains", func() {
+			fd1 := NewAWSFailureDomain(resourcebuilder.AWSFailureDomain().WithAvailabilityZone(" US-East-1a\n").Build())
+			fd2 := NewAWSFailureDomain(resourcebuilder.AWSFailureDomain().WithAvailabilityZone("us-east-1a").Build())
+
+			Expect(fd1.Equal(fd2)).To(BeTrue())
+			Expect(fd1.AWS().Placement.AvailabilityZone).To(Equal("us-east-1a"))
+		})
+
+		It("returns true for Equal between Azure failure domains", func() {
+			fd1 := NewAzureFailureDomain(resourcebuilder.AzureFailureDomain().WithZone("1 ").Build())
+			fd2 := NewAzureFailureDomain(resourcebuilder.AzureFailureDomain().WithZone("1").Build())
+
+			Expect(fd1.Equal(fd2)).To(BeTrue())
+			Expect(fd1.String()).To(Equal("AzureFailureDomain{Zone:1}"))
+		})
+
+		It("returns true for Equal between GCP failure domains", func() {
+			fd1 := NewGCPFailureDomain(resourcebuilder.GCPFailureDomain().WithZone("\tUS-Central1-a ").Build())
+			fd2 := NewGCPFailureDomain(resourcebuilder.GCPFailureDomain().WithZone("us-central1-a").Build())
+
+			Expect(fd1.Equal(fd2)).To(BeTrue())
+			Expect(fd1.GCP().Zone).To(Equal("us-central1-a"))
+		})
+	})
+
 })
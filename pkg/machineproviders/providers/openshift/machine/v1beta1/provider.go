@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -30,6 +31,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/utils/pointer"
 
@@ -51,6 +53,11 @@ const (
 	// openshiftMachineRoleLabel is the OpenShift Machine API machine role label.
 	// This must be present on all OpenShift Machine API Machine templates.
 	openshiftMachineRoleLabel = "machine.openshift.io/cluster-api-machine-role"
+
+	// maxMachineNameLength is the maximum length of a generated machine name.
+	// Machine names are used as the instance name, and the hostname, of the machine on several platforms,
+	// which are limited to the length of a DNS label.
+	maxMachineNameLength = 63
 )
 
 var (
@@ -435,6 +442,7 @@ func (m *openshiftMachineProvider) CreateMachine(ctx context.Context, logger log
 }
 
 // getMachineName generates a machine name based on the index.
+// Names longer than maxMachineNameLength are truncated, dropping the end of the cluster ID and role.
 func (m *openshiftMachineProvider) getMachineName(index int32) (string, error) {
 	clusterID, ok := m.machineTemplate.ObjectMeta.Labels[machinev1beta1.MachineClusterIDLabel]
 	if !ok {
@@ -446,7 +454,15 @@ func (m *openshiftMachineProvider) getMachineName(index int32) (string, error) {
 		return "", errMissingMachineRoleLabel
 	}
 
-	return fmt.Sprintf("%s-%s-%s-%d", clusterID, machineRole, rand.String(5), index), nil
+	prefix := fmt.Sprintf("%s-%s", clusterID, machineRole)
+	suffix := fmt.Sprintf("-%s-%d", rand.String(5), index)
+
+	// Truncate the prefix rather than the suffix, so that the index can still be determined from the name.
+	if len(prefix)+len(suffix) > maxMachineNameLength {
+		prefix = strings.TrimRight(prefix[:maxMachineNameLength-len(suffix)], "-.")
+	}
+
+	return prefix + suffix, nil
 }
 
 // getProviderConfigForIndex returns the appropriate provider configuration for the index based on the failure domain
@@ -466,7 +482,8 @@ func (m *openshiftMachineProvider) getProviderConfigForIndex(index int32) (provi
 }
 
 // DeleteMachine deletes the Machine references in the machineRef provided.
-func (m *openshiftMachineProvider) DeleteMachine(ctx context.Context, logger logr.Logger, machineRef *machineproviders.ObjectRef) error {
+// Before the Machine is deleted, it is annotated with the reason for its deletion.
+func (m *openshiftMachineProvider) DeleteMachine(ctx context.Context, logger logr.Logger, machineRef *machineproviders.ObjectRef, reason string) error {
 	machinesGVR := machinev1beta1.GroupVersion.WithResource("machines")
 
 	if machineRef.GroupVersionResource != machinesGVR {
@@ -483,7 +500,12 @@ func (m *openshiftMachineProvider) DeleteMachine(ctx context.Context, logger log
 		ObjectMeta: machineRef.ObjectMeta,
 	}
 
-	if err := m.client.Delete(ctx, &machine); err != nil {
+	err := annotateDeletionReason(ctx, m.client, &machine, reason)
+	if err == nil {
+		err = m.client.Delete(ctx, &machine)
+	}
+
+	if err != nil {
 		if apierrors.IsNotFound(err) {
 			logger.V(2).Info(
 				"Machine not found",
@@ -517,3 +539,23 @@ func (m *openshiftMachineProvider) DeleteMachine(ctx context.Context, logger log
 
 	return nil
 }
+
+// annotateDeletionReason records the reason for the deletion of the Machine on the Machine's annotations.
+func annotateDeletionReason(ctx context.Context, cl client.Client, machine *machinev1beta1.Machine, reason string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				machineproviders.MachineDeletionReasonAnnotationKey: reason,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("could not marshal deletion reason patch: %w", err)
+	}
+
+	if err := cl.Patch(ctx, machine, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		return fmt.Errorf("could not annotate machine with deletion reason: %w", err)
+	}
+
+	return nil
+}
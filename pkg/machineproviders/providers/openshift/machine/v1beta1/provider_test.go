@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"fmt"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -34,6 +35,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/utils/pointer"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -1382,6 +1384,34 @@ var _ = Describe("MachineProvider", func() {
 			assertCreatesMachine(1, providerConfigBuilder.WithAvailabilityZone("us-east-1b").WithSubnet(usEast1bSubnetbeta1), "cpms-aws-cluster-id", "us-east-1b")
 			assertCreatesMachine(2, providerConfigBuilder.WithAvailabilityZone("us-east-1c").WithSubnet(usEast1cSubnetbeta1), "cpms-aws-cluster-id", "us-east-1c")
 
+			Context("if the Machine template has a cluster ID label of the maximum length", func() {
+				var err error
+
+				BeforeEach(func() {
+					p, ok := provider.(*openshiftMachineProvider)
+					Expect(ok).To(BeTrue())
+
+					p.machineTemplate.ObjectMeta.Labels[machinev1beta1.MachineClusterIDLabel] = strings.Repeat("a", validation.LabelValueMaxLength)
+
+					err = provider.CreateMachine(ctx, logger.Logger(), 1)
+				})
+
+				It("does not error", func() {
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("creates a machine with a valid, truncated name", func() {
+					machineList := &machinev1beta1.MachineList{}
+					Eventually(komega.ObjectList(machineList, client.InNamespace(namespaceName))).Should(HaveField("Items", ConsistOf(
+						HaveField("ObjectMeta.Name", SatisfyAll(
+							HaveLen(maxMachineNameLength),
+							MatchRegexp("^a+-[a-z0-9]{5}-1$"),
+							WithTransform(validation.IsDNS1123Label, BeEmpty()),
+						)),
+					)))
+				})
+			})
+
 			Context("if the Machine template is missing the cluster ID label", func() {
 				var err error
 
@@ -1502,7 +1532,7 @@ var _ = Describe("MachineProvider", func() {
 					machineRef.ObjectMeta.Name = machineName
 					machineRef.ObjectMeta.Namespace = namespaceName
 
-					err = machineProvider.DeleteMachine(ctx, logger.Logger(), machineRef)
+					err = machineProvider.DeleteMachine(ctx, logger.Logger(), machineRef, machineproviders.MachineDeletionReasonReplaced)
 				})
 
 				It("deletes the Machine", func() {
@@ -1536,6 +1566,45 @@ var _ = Describe("MachineProvider", func() {
 				})
 			})
 
+			Context("with an existing machine with a finalizer", func() {
+				var err error
+
+				BeforeEach(func() {
+					machine := resourcebuilder.Machine().
+						WithNamespace(namespaceName).
+						WithName(machineName).
+						Build()
+
+					Eventually(komega.Update(machine, func() {
+						machine.SetFinalizers([]string{"machine.openshift.io/e2e"})
+					})).Should(Succeed())
+
+					machineRef.ObjectMeta.Name = machineName
+					machineRef.ObjectMeta.Namespace = namespaceName
+
+					err = machineProvider.DeleteMachine(ctx, logger.Logger(), machineRef, machineproviders.MachineDeletionReasonOutdatedNotReady)
+				})
+
+				It("does not error", func() {
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("marks the Machine for deletion and annotates it with the deletion reason", func() {
+					machine := resourcebuilder.Machine().
+						WithNamespace(namespaceName).
+						WithName(machineName).
+						Build()
+
+					Eventually(komega.Object(machine)).Should(SatisfyAll(
+						HaveField("ObjectMeta.DeletionTimestamp", Not(BeNil())),
+						HaveField("ObjectMeta.Annotations", HaveKeyWithValue(
+							machineproviders.MachineDeletionReasonAnnotationKey,
+							machineproviders.MachineDeletionReasonOutdatedNotReady,
+						)),
+					))
+				})
+			})
+
 			Context("with an non-existent machine", func() {
 				var err error
 				const unknown = "unknown"
@@ -1544,7 +1613,7 @@ var _ = Describe("MachineProvider", func() {
 					machineRef.ObjectMeta.Name = unknown
 					machineRef.ObjectMeta.Namespace = namespaceName
 
-					err = machineProvider.DeleteMachine(ctx, logger.Logger(), machineRef)
+					err = machineProvider.DeleteMachine(ctx, logger.Logger(), machineRef, machineproviders.MachineDeletionReasonReplaced)
 				})
 
 				It("does not delete the existing Machine", func() {
@@ -1588,7 +1657,7 @@ var _ = Describe("MachineProvider", func() {
 					},
 				}
 
-				err = machineProvider.DeleteMachine(ctx, logger.Logger(), machineRef)
+				err = machineProvider.DeleteMachine(ctx, logger.Logger(), machineRef, machineproviders.MachineDeletionReasonReplaced)
 			})
 
 			It("returns an error", func() {
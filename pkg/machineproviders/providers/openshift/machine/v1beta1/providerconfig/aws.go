@@ -19,10 +19,12 @@ package providerconfig
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	configv1 "github.com/openshift/api/config/v1"
 	machinev1 "github.com/openshift/api/machine/v1"
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -60,6 +62,29 @@ func (a AWSProviderConfig) Config() machinev1beta1.AWSMachineProviderConfig {
 	return a.providerConfig
 }
 
+// normalizedConfig returns a copy of the stored AWSMachineProviderConfig with the tags sorted
+// and the embedded object metadata cleared.
+// The order of the tags has no effect on the resulting instance, and the metadata may be set
+// differently per machine, so neither should affect comparisons between provider configs.
+func (a AWSProviderConfig) normalizedConfig() machinev1beta1.AWSMachineProviderConfig {
+	config := a.providerConfig
+	config.ObjectMeta = metav1.ObjectMeta{}
+
+	if config.Tags != nil {
+		config.Tags = append([]machinev1beta1.TagSpecification{}, config.Tags...)
+
+		sort.SliceStable(config.Tags, func(i, j int) bool {
+			if config.Tags[i].Name != config.Tags[j].Name {
+				return config.Tags[i].Name < config.Tags[j].Name
+			}
+
+			return config.Tags[i].Value < config.Tags[j].Value
+		})
+	}
+
+	return config
+}
+
 // newAWSProviderConfig creates an AWS type ProviderConfig from the raw extension.
 // It should return an error if the provided RawExtension does not represent
 // an AWSMachineProviderConfig.
@@ -23,6 +23,7 @@ import (
 	v1 "github.com/openshift/api/config/v1"
 	machinev1 "github.com/openshift/api/machine/v1"
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
 )
@@ -57,6 +58,16 @@ func (a AzureProviderConfig) Config() machinev1beta1.AzureMachineProviderSpec {
 	return a.providerConfig
 }
 
+// normalizedConfig returns a copy of the stored AzureMachineProviderSpec with the embedded object metadata cleared.
+// The metadata has no effect on the resulting instance and may be set differently per machine,
+// so it should not affect comparisons between provider configs.
+func (a AzureProviderConfig) normalizedConfig() machinev1beta1.AzureMachineProviderSpec {
+	config := a.providerConfig
+	config.ObjectMeta = metav1.ObjectMeta{}
+
+	return config
+}
+
 // newAzureProviderConfig creates an Azure type ProviderConfig from the raw extension.
 // It should return an error if the provided RawExtension does not represent
 // an AzureMachineProviderConfig.
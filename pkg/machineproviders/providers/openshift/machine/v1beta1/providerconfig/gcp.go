@@ -23,6 +23,7 @@ import (
 	v1 "github.com/openshift/api/config/v1"
 	machinev1 "github.com/openshift/api/machine/v1"
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -55,6 +56,16 @@ func (g GCPProviderConfig) Config() machinev1beta1.GCPMachineProviderSpec {
 	return g.providerConfig
 }
 
+// normalizedConfig returns a copy of the stored GCPMachineProviderSpec with the embedded object metadata cleared.
+// The metadata has no effect on the resulting instance and may be set differently per machine,
+// so it should not affect comparisons between provider configs.
+func (g GCPProviderConfig) normalizedConfig() machinev1beta1.GCPMachineProviderSpec {
+	config := g.providerConfig
+	config.ObjectMeta = metav1.ObjectMeta{}
+
+	return config
+}
+
 // newGCPProviderConfig creates a GCP type ProviderConfig from the raw extension.
 // It should return an error if the provided RawExtension does not represent a GCPProviderConfig.
 func newGCPProviderConfig(raw *runtime.RawExtension) (ProviderConfig, error) {
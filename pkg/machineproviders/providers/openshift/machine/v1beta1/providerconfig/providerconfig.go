@@ -184,11 +184,11 @@ func (p providerConfig) Diff(other ProviderConfig) ([]string, error) {
 
 	switch p.platformType {
 	case configv1.AWSPlatformType:
-		return deep.Equal(p.aws.providerConfig, other.AWS().providerConfig), nil
+		return deep.Equal(p.aws.normalizedConfig(), other.AWS().normalizedConfig()), nil
 	case configv1.AzurePlatformType:
-		return deep.Equal(p.azure.providerConfig, other.Azure().providerConfig), nil
+		return deep.Equal(p.azure.normalizedConfig(), other.Azure().normalizedConfig()), nil
 	case configv1.GCPPlatformType:
-		return deep.Equal(p.gcp.providerConfig, other.GCP().providerConfig), nil
+		return deep.Equal(p.gcp.normalizedConfig(), other.GCP().normalizedConfig()), nil
 	case configv1.NonePlatformType:
 		return nil, errUnsupportedPlatformType
 	default:
@@ -208,11 +208,11 @@ func (p providerConfig) Equal(other ProviderConfig) (bool, error) {
 
 	switch p.platformType {
 	case configv1.AWSPlatformType:
-		return reflect.DeepEqual(p.aws.providerConfig, other.AWS().providerConfig), nil
+		return reflect.DeepEqual(p.aws.normalizedConfig(), other.AWS().normalizedConfig()), nil
 	case configv1.AzurePlatformType:
-		return reflect.DeepEqual(p.azure.providerConfig, other.Azure().providerConfig), nil
+		return reflect.DeepEqual(p.azure.normalizedConfig(), other.Azure().normalizedConfig()), nil
 	case configv1.GCPPlatformType:
-		return reflect.DeepEqual(p.gcp.providerConfig, other.GCP().providerConfig), nil
+		return reflect.DeepEqual(p.gcp.normalizedConfig(), other.GCP().normalizedConfig()), nil
 	case configv1.NonePlatformType:
 		return false, errUnsupportedPlatformType
 	default:
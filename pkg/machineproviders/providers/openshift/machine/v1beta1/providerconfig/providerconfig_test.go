@@ -33,6 +33,30 @@ func stringPtr(s string) *string {
 	return &s
 }
 
+// awsProviderSpecWithMetadataName returns a default AWS provider spec with the embedded metadata name set.
+func awsProviderSpecWithMetadataName(name string) machinev1beta1.AWSMachineProviderConfig {
+	spec := *resourcebuilder.AWSProviderSpec().Build()
+	spec.Name = name
+
+	return spec
+}
+
+// azureProviderSpecWithMetadataName returns a default Azure provider spec with the embedded metadata name set.
+func azureProviderSpecWithMetadataName(name string) machinev1beta1.AzureMachineProviderSpec {
+	spec := *resourcebuilder.AzureProviderSpec().Build()
+	spec.Name = name
+
+	return spec
+}
+
+// gcpProviderSpecWithMetadataName returns a default GCP provider spec with the embedded metadata name set.
+func gcpProviderSpecWithMetadataName(name string) machinev1beta1.GCPMachineProviderSpec {
+	spec := *resourcebuilder.GCPProviderSpec().Build()
+	spec.Name = name
+
+	return spec
+}
+
 var _ = Describe("Provider Config", func() {
 	Context("NewProviderConfigFromMachineTemplate", func() {
 		type providerConfigTableInput struct {
@@ -494,6 +518,61 @@ var _ = Describe("Provider Config", func() {
 				},
 				expectedEqual: false,
 			}),
+			Entry("with AWS configs with reordered tags", equalTableInput{
+				basePC: &providerConfig{
+					platformType: configv1.AWSPlatformType,
+					aws: AWSProviderConfig{
+						providerConfig: *resourcebuilder.AWSProviderSpec().WithTags([]machinev1beta1.TagSpecification{
+							{Name: "a", Value: "1"},
+							{Name: "b", Value: "2"},
+						}).Build(),
+					},
+				},
+				comparePC: &providerConfig{
+					platformType: configv1.AWSPlatformType,
+					aws: AWSProviderConfig{
+						providerConfig: *resourcebuilder.AWSProviderSpec().WithTags([]machinev1beta1.TagSpecification{
+							{Name: "b", Value: "2"},
+							{Name: "a", Value: "1"},
+						}).Build(),
+					},
+				},
+				expectedEqual: true,
+			}),
+			Entry("with AWS configs with different tags", equalTableInput{
+				basePC: &providerConfig{
+					platformType: configv1.AWSPlatformType,
+					aws: AWSProviderConfig{
+						providerConfig: *resourcebuilder.AWSProviderSpec().WithTags([]machinev1beta1.TagSpecification{
+							{Name: "a", Value: "1"},
+						}).Build(),
+					},
+				},
+				comparePC: &providerConfig{
+					platformType: configv1.AWSPlatformType,
+					aws: AWSProviderConfig{
+						providerConfig: *resourcebuilder.AWSProviderSpec().WithTags([]machinev1beta1.TagSpecification{
+							{Name: "a", Value: "2"},
+						}).Build(),
+					},
+				},
+				expectedEqual: false,
+			}),
+			Entry("with AWS configs with different metadata names", equalTableInput{
+				basePC: &providerConfig{
+					platformType: configv1.AWSPlatformType,
+					aws: AWSProviderConfig{
+						providerConfig: awsProviderSpecWithMetadataName("machine-0"),
+					},
+				},
+				comparePC: &providerConfig{
+					platformType: configv1.AWSPlatformType,
+					aws: AWSProviderConfig{
+						providerConfig: awsProviderSpecWithMetadataName("machine-1"),
+					},
+				},
+				expectedEqual: true,
+			}),
 			Entry("with matching Azure configs", equalTableInput{
 				basePC: &providerConfig{
 					platformType: configv1.AzurePlatformType,
@@ -524,6 +603,21 @@ var _ = Describe("Provider Config", func() {
 				},
 				expectedEqual: false,
 			}),
+			Entry("with Azure configs with different metadata names", equalTableInput{
+				basePC: &providerConfig{
+					platformType: configv1.AzurePlatformType,
+					azure: AzureProviderConfig{
+						providerConfig: azureProviderSpecWithMetadataName("machine-0"),
+					},
+				},
+				comparePC: &providerConfig{
+					platformType: configv1.AzurePlatformType,
+					azure: AzureProviderConfig{
+						providerConfig: azureProviderSpecWithMetadataName("machine-1"),
+					},
+				},
+				expectedEqual: true,
+			}),
 			Entry("with matching GCP configs", equalTableInput{
 				basePC: &providerConfig{
 					platformType: configv1.GCPPlatformType,
@@ -554,6 +648,21 @@ var _ = Describe("Provider Config", func() {
 				},
 				expectedEqual: false,
 			}),
+			Entry("with GCP configs with different metadata names", equalTableInput{
+				basePC: &providerConfig{
+					platformType: configv1.GCPPlatformType,
+					gcp: GCPProviderConfig{
+						providerConfig: gcpProviderSpecWithMetadataName("machine-0"),
+					},
+				},
+				comparePC: &providerConfig{
+					platformType: configv1.GCPPlatformType,
+					gcp: GCPProviderConfig{
+						providerConfig: gcpProviderSpecWithMetadataName("machine-1"),
+					},
+				},
+				expectedEqual: true,
+			}),
 			Entry("with matching Generic configs", equalTableInput{
 				basePC: &providerConfig{
 					platformType: configv1.VSpherePlatformType,
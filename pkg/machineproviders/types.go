@@ -25,6 +25,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// MachineDeletionReasonAnnotationKey is the annotation set by the Machine Provider on a Machine it deletes
+	// to record why the ControlPlaneMachineSet controller deleted the Machine.
+	MachineDeletionReasonAnnotationKey = "machine.openshift.io/control-plane-machine-set-deletion-reason"
+
+	// MachineDeletionReasonReplaced denotes that the Machine was deleted because it was outdated
+	// and an updated replacement Machine exists for its index.
+	MachineDeletionReasonReplaced = "Replaced"
+
+	// MachineDeletionReasonOutdatedNotReady denotes that the Machine was deleted because it was outdated
+	// and not ready, to make room for a new replacement Machine in its index.
+	MachineDeletionReasonOutdatedNotReady = "OutdatedNotReady"
+)
+
 // MachineInfo collates information about a Control Plane Machine and Node.
 // This is used by the core of the ControlPlaneMachineSet controller to determine
 // actions required to be taken on the Machines within its control.
@@ -84,6 +98,7 @@ type MachineProvider interface {
 
 	// DeleteMachine is used to instruct the Machine Provider to delete a particular Machine. This is used by the
 	// RollingUpdate strategy of the ControlPlaneMachineSet so that it can remove old Machines once they have been
-	// replaced.
-	DeleteMachine(context.Context, logr.Logger, *ObjectRef) error
+	// replaced. The reason is recorded on the Machine, prior to deletion, with the MachineDeletionReasonAnnotationKey
+	// annotation.
+	DeleteMachine(context.Context, logr.Logger, *ObjectRef, string) error
 }
@@ -30,6 +30,7 @@ func AWSProviderSpec() AWSProviderSpecBuilder {
 	return AWSProviderSpecBuilder{
 		availabilityZone: "us-east-1a",
 		instanceType:     "m6i.xlarge",
+		rootVolumeType:   "gp3",
 		securityGroups: []machinev1beta1.AWSResourceReference{
 			{
 				Filters: []machinev1beta1.Filter{
@@ -59,8 +60,12 @@ func AWSProviderSpec() AWSProviderSpecBuilder {
 type AWSProviderSpecBuilder struct {
 	availabilityZone string
 	instanceType     string
+	rootVolumeIOPS   *int64
+	rootVolumeType   string
 	securityGroups   []machinev1beta1.AWSResourceReference
 	subnet           machinev1beta1.AWSResourceReference
+	tags             []machinev1beta1.TagSpecification
+	tenancy          machinev1beta1.InstanceTenancy
 }
 
 // Build builds a new AWS machine config based on the configuration provided.
@@ -77,8 +82,9 @@ func (m AWSProviderSpecBuilder) Build() *machinev1beta1.AWSMachineProviderConfig
 			{
 				EBS: &machinev1beta1.EBSBlockDeviceSpec{
 					Encrypted:  boolPtr(true),
+					Iops:       m.rootVolumeIOPS,
 					VolumeSize: int64Ptr(120),
-					VolumeType: stringPtr("gp3"),
+					VolumeType: stringPtr(m.rootVolumeType),
 				},
 			},
 		},
@@ -102,9 +108,11 @@ func (m AWSProviderSpecBuilder) Build() *machinev1beta1.AWSMachineProviderConfig
 		Placement: machinev1beta1.Placement{
 			Region:           "us-east-1",
 			AvailabilityZone: m.availabilityZone,
+			Tenancy:          m.tenancy,
 		},
 		SecurityGroups: m.securityGroups,
 		Subnet:         m.subnet,
+		Tags:           m.tags,
 		UserDataSecret: &corev1.LocalObjectReference{
 			Name: "aws-user-data-12345678",
 		},
@@ -138,6 +146,18 @@ func (m AWSProviderSpecBuilder) WithInstanceType(instanceType string) AWSProvide
 	return m
 }
 
+// WithRootVolumeIOPS sets the IOPS of the root volume for the AWS machine config builder.
+func (m AWSProviderSpecBuilder) WithRootVolumeIOPS(iops int64) AWSProviderSpecBuilder {
+	m.rootVolumeIOPS = int64Ptr(iops)
+	return m
+}
+
+// WithRootVolumeType sets the volume type of the root volume for the AWS machine config builder.
+func (m AWSProviderSpecBuilder) WithRootVolumeType(volumeType string) AWSProviderSpecBuilder {
+	m.rootVolumeType = volumeType
+	return m
+}
+
 // WithSecurityGroups sets the securityGroups for the AWS machine config builder.
 func (m AWSProviderSpecBuilder) WithSecurityGroups(sgs []machinev1beta1.AWSResourceReference) AWSProviderSpecBuilder {
 	m.securityGroups = sgs
@@ -149,3 +169,15 @@ func (m AWSProviderSpecBuilder) WithSubnet(subnet machinev1beta1.AWSResourceRefe
 	m.subnet = subnet
 	return m
 }
+
+// WithTags sets the tags for the AWS machine config builder.
+func (m AWSProviderSpecBuilder) WithTags(tags []machinev1beta1.TagSpecification) AWSProviderSpecBuilder {
+	m.tags = tags
+	return m
+}
+
+// WithTenancy sets the placement tenancy for the AWS machine config builder.
+func (m AWSProviderSpecBuilder) WithTenancy(tenancy machinev1beta1.InstanceTenancy) AWSProviderSpecBuilder {
+	m.tenancy = tenancy
+	return m
+}
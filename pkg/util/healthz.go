@@ -0,0 +1,39 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"net/http"
+
+	machinev1 "github.com/openshift/api/machine/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// NewAPIServerReadyCheck returns a readiness check that fails when the API server cannot be reached.
+// The client passed should be uncached, so that each check makes a request to the API server.
+// Without the API server the operator cannot reconcile, so it should not report itself as ready.
+func NewAPIServerReadyCheck(c client.Reader) healthz.Checker {
+	return func(req *http.Request) error {
+		if err := c.List(req.Context(), &machinev1.ControlPlaneMachineSetList{}, client.Limit(1)); err != nil {
+			return fmt.Errorf("unable to reach the API server: %w", err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	machinev1 "github.com/openshift/api/machine/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// errAPIServerUnreachable is returned by the fake reader to simulate an API server that cannot be reached.
+var errAPIServerUnreachable = errors.New("dial tcp 172.30.0.1:443: connect: connection refused")
+
+// fakeReader is a client.Reader that returns the configured error from List, and records the list calls made.
+type fakeReader struct {
+	client.Reader
+
+	listErr   error
+	listCalls []client.ObjectList
+}
+
+// List records the list call and returns the configured error.
+func (f *fakeReader) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	f.listCalls = append(f.listCalls, list)
+
+	return f.listErr
+}
+
+var _ = Describe("Healthz", func() {
+	Context("NewAPIServerReadyCheck", func() {
+		var reader *fakeReader
+
+		BeforeEach(func() {
+			reader = &fakeReader{}
+		})
+
+		It("should pass when the API server can be reached", func() {
+			check := NewAPIServerReadyCheck(reader)
+
+			Expect(check(httptest.NewRequest("GET", "/readyz", nil))).To(Succeed())
+			Expect(reader.listCalls).To(ConsistOf(BeAssignableToTypeOf(&machinev1.ControlPlaneMachineSetList{})))
+		})
+
+		It("should fail when the API server cannot be reached", func() {
+			reader.listErr = errAPIServerUnreachable
+			check := NewAPIServerReadyCheck(reader)
+
+			err := check(httptest.NewRequest("GET", "/readyz", nil))
+			Expect(err).To(MatchError(errAPIServerUnreachable))
+			Expect(err).To(MatchError(ContainSubstring("unable to reach the API server")))
+		})
+	})
+})
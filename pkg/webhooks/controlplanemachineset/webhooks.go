@@ -31,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -113,6 +114,11 @@ func (r *ControlPlaneMachineSetWebhook) ValidateUpdate(ctx context.Context, oldO
 		return errUpdateNilCPMS
 	}
 
+	oldCPMS, ok := oldObj.(*machinev1.ControlPlaneMachineSet)
+	if !ok {
+		return errObjNotCPMS
+	}
+
 	cpms, ok := newObj.(*machinev1.ControlPlaneMachineSet)
 	if !ok {
 		return errObjNotCPMS
@@ -120,6 +126,7 @@ func (r *ControlPlaneMachineSetWebhook) ValidateUpdate(ctx context.Context, oldO
 
 	errs = append(errs, validateMetadata(field.NewPath("metadata"), cpms.ObjectMeta)...)
 	errs = append(errs, validateSpec(field.NewPath("spec"), cpms)...)
+	errs = append(errs, validateSpecOnUpdate(field.NewPath("spec"), oldCPMS, cpms)...)
 
 	if len(errs) > 0 {
 		return utilerrors.NewAggregate(errs)
@@ -158,6 +165,21 @@ func (r *ControlPlaneMachineSetWebhook) validateSpecOnCreate(ctx context.Context
 	return errs
 }
 
+// validateSpecOnUpdate runs the update time validations on the ControlPlaneMachineSet spec.
+func validateSpecOnUpdate(parentPath *field.Path, oldCPMS, cpms *machinev1.ControlPlaneMachineSet) []error {
+	templatePath := parentPath.Child("template")
+	template := cpms.Spec.Template
+
+	if template.MachineType != machinev1.OpenShiftMachineV1Beta1MachineType || template.OpenShiftMachineV1Beta1Machine == nil {
+		// The machine type and template are checked by validateTemplate.
+		return []error{}
+	}
+
+	openshiftMachineTemplatePath := templatePath.Child(string(machinev1.OpenShiftMachineV1Beta1MachineType))
+
+	return validateOpenShiftProviderConfigChanges(openshiftMachineTemplatePath, *template.OpenShiftMachineV1Beta1Machine, oldCPMS.Spec.Template.OpenShiftMachineV1Beta1Machine)
+}
+
 // validateMetadata validates the metadata of the ControlPlaneMachineSet resource.
 func validateMetadata(parentPath *field.Path, metadata metav1.ObjectMeta) []error {
 	errs := []error{}
@@ -220,11 +242,27 @@ func validateOpenShiftMachineV1BetaTemplate(parentPath *field.Path, template mac
 	errs := []error{}
 
 	errs = append(errs, validateTemplateLabels(parentPath.Child("metadata", "labels"), template.ObjectMeta.Labels, selector)...)
+	errs = append(errs, validateFailureDomainsPlatform(parentPath.Child("failureDomains", "platform"), template.FailureDomains.Platform)...)
 	errs = append(errs, validateOpenShiftProviderConfig(parentPath, template)...)
 
 	return errs
 }
 
+// validateFailureDomainsPlatform checks that the failure domains platform is one the control plane machine set
+// supports failure domains for. An empty platform means no failure domains are configured.
+func validateFailureDomainsPlatform(platformPath *field.Path, platform configv1.PlatformType) []error {
+	switch platform {
+	case "", configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType:
+		return []error{}
+	default:
+		return []error{field.NotSupported(platformPath, platform, []string{
+			string(configv1.AWSPlatformType),
+			string(configv1.AzurePlatformType),
+			string(configv1.GCPPlatformType),
+		})}
+	}
+}
+
 // validateOpenShiftMachineV1BetaTemplateOnCreate validates the failure domains in the provided template match up with those
 // present in the Machines provided.
 func validateOpenShiftMachineV1BetaTemplateOnCreate(parentPath *field.Path, template machinev1.OpenShiftMachineV1Beta1MachineTemplate, machines []machinev1beta1.Machine) []error {
@@ -236,6 +274,8 @@ func validateOpenShiftMachineV1BetaTemplateOnCreate(parentPath *field.Path, temp
 		errs = append(errs, checkOpenShiftFailureDomainsMatchMachines(parentPath.Child("failureDomains"), template.FailureDomains, machines)...)
 	}
 
+	errs = append(errs, validateOpenShiftProviderConfigChanges(parentPath, template, nil)...)
+
 	return errs
 }
 
@@ -277,6 +317,62 @@ func validateOpenShiftProviderConfig(parentPath *field.Path, template machinev1.
 	return []error{}
 }
 
+// validateOpenShiftProviderConfigChanges checks the parts of the provider config on the ControlPlaneMachineSet that
+// are only validated when they are set or changed, so that an existing ControlPlaneMachineSet is not blocked from
+// unrelated updates. The old template is nil on create.
+func validateOpenShiftProviderConfigChanges(parentPath *field.Path, template machinev1.OpenShiftMachineV1Beta1MachineTemplate, oldTemplate *machinev1.OpenShiftMachineV1Beta1MachineTemplate) []error {
+	providerConfig, err := providerconfig.NewProviderConfigFromMachineTemplate(template)
+	if err != nil {
+		// The provider config errors are reported by validateOpenShiftProviderConfig.
+		return []error{}
+	}
+
+	if providerConfig.Type() != configv1.AWSPlatformType {
+		return []error{}
+	}
+
+	var oldBlockDevices []machinev1beta1.BlockDeviceMappingSpec
+
+	if oldTemplate != nil {
+		oldProviderConfig, err := providerconfig.NewProviderConfigFromMachineTemplate(*oldTemplate)
+		if err == nil && oldProviderConfig.Type() == configv1.AWSPlatformType {
+			oldBlockDevices = oldProviderConfig.AWS().Config().BlockDevices
+		}
+	}
+
+	blockDevicesPath := parentPath.Child("spec", "providerSpec", "value", "blockDevices")
+
+	return validateOpenShiftAWSBlockDeviceIOPS(blockDevicesPath, providerConfig.AWS().Config().BlockDevices, oldBlockDevices)
+}
+
+// validateOpenShiftAWSBlockDeviceIOPS checks that IOPS are only set on EBS volume types that support them.
+// Block devices whose volume type and IOPS match the old block device in the same position are not checked.
+// When the volume type is not set, AWS chooses the volume type, so the IOPS are not checked.
+func validateOpenShiftAWSBlockDeviceIOPS(blockDevicesPath *field.Path, blockDevices, oldBlockDevices []machinev1beta1.BlockDeviceMappingSpec) []error {
+	errs := []error{}
+
+	for i, blockDevice := range blockDevices {
+		ebs := blockDevice.EBS
+		if ebs == nil || pointer.Int64Deref(ebs.Iops, 0) == 0 || pointer.StringDeref(ebs.VolumeType, "") == "" {
+			continue
+		}
+
+		if i < len(oldBlockDevices) && oldBlockDevices[i].EBS != nil &&
+			pointer.StringDeref(oldBlockDevices[i].EBS.VolumeType, "") == *ebs.VolumeType &&
+			pointer.Int64Deref(oldBlockDevices[i].EBS.Iops, 0) == *ebs.Iops {
+			continue
+		}
+
+		switch *ebs.VolumeType {
+		case "io1", "io2", "gp3":
+		default:
+			errs = append(errs, field.Invalid(blockDevicesPath.Index(i).Child("ebs", "iops"), *ebs.Iops, fmt.Sprintf("iops is only supported for io1, io2 and gp3 volumes, volume type is %s", *ebs.VolumeType)))
+		}
+	}
+
+	return errs
+}
+
 // validateOpenShiftAzureProviderConfig runs Azure specific checks on the provider config on the ControlPlaneMachineSet.
 // This ensure that the ControlPlaneMachineSet can safely replace Azure control plane machines.
 func validateOpenShiftAzureProviderConfig(parentPath *field.Path, providerConfig providerconfig.AzureProviderConfig) []error {
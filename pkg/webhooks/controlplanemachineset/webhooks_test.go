@@ -119,6 +119,14 @@ var _ = Describe("Webhooks", func() {
 				Expect(k8sClient.Create(ctx, cpms)).To(Succeed())
 			})
 
+			It("with root volume IOPS on a volume type without IOPS support", func() {
+				templateProviderSpec := resourcebuilder.AWSProviderSpec().WithAvailabilityZone("us-east-1").WithRootVolumeType("gp2").WithRootVolumeIOPS(3000)
+				templateBuilder := resourcebuilder.OpenShiftMachineV1Beta1Template().WithProviderSpecBuilder(templateProviderSpec)
+				cpms := builder.WithMachineTemplateBuilder(templateBuilder).Build()
+
+				Expect(k8sClient.Create(ctx, cpms)).To(MatchError(ContainSubstring("spec.template.machines_v1beta1_machine_openshift_io.spec.providerSpec.value.blockDevices[0].ebs.iops: Invalid value: 3000: iops is only supported for io1, io2 and gp3 volumes, volume type is gp2")))
+			})
+
 			It("with a disallowed name", func() {
 				cpms := builder.WithName("disallowed").Build()
 				Expect(apierrors.ReasonForError(k8sClient.Create(ctx, cpms))).To(BeEquivalentTo("metadata.name: Invalid value: \"disallowed\": control plane machine set name must be cluster"))
@@ -729,6 +737,51 @@ var _ = Describe("Webhooks", func() {
 				})()).Should(Succeed())
 			})
 
+			DescribeTable("with an update to the placement tenancy", func(tenancy machinev1beta1.InstanceTenancy) {
+				rawProviderSpec := resourcebuilder.AWSProviderSpec().WithAvailabilityZone("us-east-1").WithTenancy(tenancy).BuildRawExtension()
+
+				Expect(komega.Update(cpms, func() {
+					cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value = rawProviderSpec
+				})()).Should(Succeed())
+			},
+				Entry("with default tenancy", machinev1beta1.DefaultTenancy),
+				Entry("with dedicated tenancy", machinev1beta1.DedicatedTenancy),
+				Entry("with host tenancy", machinev1beta1.HostTenancy),
+			)
+
+			DescribeTable("with an update to the root volume IOPS", func(volumeType string, iops int64) {
+				rawProviderSpec := resourcebuilder.AWSProviderSpec().WithAvailabilityZone("us-east-1").WithRootVolumeType(volumeType).WithRootVolumeIOPS(iops).BuildRawExtension()
+
+				Expect(komega.Update(cpms, func() {
+					cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value = rawProviderSpec
+				})()).Should(Succeed())
+			},
+				Entry("with a gp3 volume", "gp3", int64(6000)),
+				Entry("with an io2 volume", "io2", int64(10000)),
+				Entry("with a volume without a volume type", "", int64(3000)),
+			)
+
+			DescribeTable("with an update to the root volume IOPS on a volume type without IOPS support", func(volumeType string) {
+				rawProviderSpec := resourcebuilder.AWSProviderSpec().WithAvailabilityZone("us-east-1").WithRootVolumeType(volumeType).WithRootVolumeIOPS(3000).BuildRawExtension()
+
+				Expect(komega.Update(cpms, func() {
+					cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value = rawProviderSpec
+				})()).Should(MatchError(ContainSubstring("spec.template.machines_v1beta1_machine_openshift_io.spec.providerSpec.value.blockDevices[0].ebs.iops: Invalid value: 3000: iops is only supported for io1, io2 and gp3 volumes, volume type is %s", volumeType)))
+			},
+				Entry("with a gp2 volume", "gp2"),
+				Entry("with a standard volume", "standard"),
+			)
+
+			DescribeTable("with an unsupported strategy", func(strategy machinev1.ControlPlaneMachineSetStrategyType) {
+				// This is an openapi validation but it makes sense to include it here as well
+				Expect(komega.Update(cpms, func() {
+					cpms.Spec.Strategy.Type = strategy
+				})()).Should(MatchError(ContainSubstring("Unsupported value: %q: supported values: \"RollingUpdate\", \"OnDelete\"", strategy)))
+			},
+				Entry("with the Recreate strategy", machinev1.Recreate),
+				Entry("with an invalid strategy", machinev1.ControlPlaneMachineSetStrategyType("Invalid")),
+			)
+
 			It("with 4 replicas", func() {
 				// This is an openapi validation but it makes sense to include it here as well
 				Expect(komega.Update(cpms, func() {
@@ -737,6 +790,14 @@ var _ = Describe("Webhooks", func() {
 				})()).Should(MatchError(ContainSubstring("Unsupported value: 4: supported values: \"3\", \"5\"")))
 			})
 
+			It("with 0 replicas", func() {
+				// A control plane cannot operate without any machines, this is an openapi validation.
+				Expect(komega.Update(cpms, func() {
+					zero := int32(0)
+					cpms.Spec.Replicas = &zero
+				})()).Should(MatchError(ContainSubstring("Unsupported value: 0: supported values: \"3\", \"5\"")))
+			})
+
 			It("with 5 replicas", func() {
 				// Five replicas is a valid value but the existing CPMS has three replicas
 				Expect(komega.Update(cpms, func() {
@@ -745,6 +806,14 @@ var _ = Describe("Webhooks", func() {
 				})()).Should(MatchError(ContainSubstring("ControlPlaneMachineSet.machine.openshift.io \"cluster\" is invalid: spec.replicas: Invalid value: \"integer\": replicas is immutable")), "Replicas should be immutable")
 			})
 
+			It("with an unsupported failure domains platform", func() {
+				Expect(komega.Update(cpms, func() {
+					cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains = machinev1.FailureDomains{
+						Platform: configv1.BareMetalPlatformType,
+					}
+				})()).Should(MatchError(ContainSubstring("failureDomains.platform: Unsupported value: \"BareMetal\": supported values: \"AWS\", \"Azure\", \"GCP\"")))
+			})
+
 			It("when modifying the machine labels and the selector still matches", func() {
 				Expect(komega.Update(cpms, func() {
 					cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.ObjectMeta.Labels["new"] = dummyValue
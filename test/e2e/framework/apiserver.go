@@ -0,0 +1,100 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// kubeAPIServerNamespace is the namespace in which the kube-apiserver static pods run.
+	kubeAPIServerNamespace = "openshift-kube-apiserver"
+
+	// kubeAPIServerLeaderLeaseName is the name of the lease elected between the kube-apiserver static pods.
+	// The kube-apiserver itself does not elect a leader, this lease is held by the cert regeneration
+	// controller, which runs alongside the kube-apiserver within each kube-apiserver static pod.
+	kubeAPIServerLeaderLeaseName = "cert-regeneration-controller-lock"
+)
+
+// ErrAPIServerLeaderNotObservable is returned when the kube-apiserver leader lease does not exist, or is not held.
+// Checks on the API server leader should be skipped when the leader cannot be observed.
+var ErrAPIServerLeaderNotObservable = errors.New("kube-apiserver leader is not observable")
+
+// IsMachineHostingAPIServerLeader returns whether the node of the machine hosts the kube-apiserver static pod that
+// currently holds the kube-apiserver leader lease.
+// The lease holder identity is of the form <hostname>_<uuid>, where the hostname is that of the node, as the
+// kube-apiserver static pods run on the host network.
+func (f *framework) IsMachineHostingAPIServerLeader(machine *machinev1beta1.Machine) (bool, error) {
+	if machine == nil {
+		return false, errNilMachine
+	}
+
+	if machine.Status.NodeRef == nil || machine.Status.NodeRef.Name == "" {
+		return false, fmt.Errorf("%w: %s", errNoNodeRef, machine.Name)
+	}
+
+	lease := &coordinationv1.Lease{}
+	key := runtimeclient.ObjectKey{Namespace: kubeAPIServerNamespace, Name: kubeAPIServerLeaderLeaseName}
+
+	err := f.client.Get(f.GetContext(), key, lease)
+
+	switch {
+	case apierrors.IsNotFound(err):
+		return false, fmt.Errorf("%w: lease %s not found", ErrAPIServerLeaderNotObservable, key)
+	case err != nil:
+		return false, fmt.Errorf("failed to get lease %s: %w", key, err)
+	}
+
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" {
+		return false, fmt.Errorf("%w: lease %s has no holder", ErrAPIServerLeaderNotObservable, key)
+	}
+
+	return hostnameMatchesNodeName(leaseHolderHostname(*lease.Spec.HolderIdentity), machine.Status.NodeRef.Name), nil
+}
+
+// leaseHolderHostname returns the hostname from a leader election identity of the form <hostname>_<uuid>.
+// Hostnames cannot contain underscores, so the identity is split on the last underscore.
+func leaseHolderHostname(identity string) string {
+	if idx := strings.LastIndex(identity, "_"); idx > 0 {
+		return identity[:idx]
+	}
+
+	return identity
+}
+
+// hostnameMatchesNodeName returns whether the hostname is that of the node.
+// Depending on the platform, either the hostname or the node name may be fully qualified, so the
+// short names are compared when only one of them is.
+func hostnameMatchesNodeName(hostname, nodeName string) bool {
+	if hostname == "" || nodeName == "" {
+		return false
+	}
+
+	if hostname == nodeName {
+		return true
+	}
+
+	return strings.HasPrefix(nodeName, hostname+".") || strings.HasPrefix(hostname, nodeName+".")
+}
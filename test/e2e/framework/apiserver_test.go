@@ -0,0 +1,49 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("APIServer", func() {
+	Context("leaseHolderHostname", func() {
+		DescribeTable("should return the hostname of the lease holder",
+			func(identity, expected string) {
+				Expect(leaseHolderHostname(identity)).To(Equal(expected))
+			},
+			Entry("with a hostname and UUID", "ip-10-0-1-2_5c7a6f2e-0d4b-4f1e-9f7a-2b1c3d4e5f60", "ip-10-0-1-2"),
+			Entry("with a fully qualified hostname", "ip-10-0-1-2.ec2.internal_5c7a6f2e", "ip-10-0-1-2.ec2.internal"),
+			Entry("without a UUID", "master-0", "master-0"),
+		)
+	})
+
+	Context("hostnameMatchesNodeName", func() {
+		DescribeTable("should compare the hostname with the node name",
+			func(hostname, nodeName string, expected bool) {
+				Expect(hostnameMatchesNodeName(hostname, nodeName)).To(Equal(expected))
+			},
+			Entry("with identical names", "master-0", "master-0", true),
+			Entry("with a fully qualified node name", "ip-10-0-1-2", "ip-10-0-1-2.ec2.internal", true),
+			Entry("with a fully qualified hostname", "master-0.example.com", "master-0", true),
+			Entry("with a different node", "master-0", "master-1", false),
+			Entry("with a node name sharing a prefix", "master-1", "master-10", false),
+			Entry("with an empty hostname", "", "master-0", false),
+		)
+	})
+})
@@ -0,0 +1,190 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// etcdNamespace is the namespace in which the etcd static pods run.
+	etcdNamespace = "openshift-etcd"
+
+	// etcdctlContainerName is the name of the container within the etcd pods
+	// that is preconfigured to run etcdctl against the etcd cluster.
+	etcdctlContainerName = "etcdctl"
+)
+
+var (
+	// errNoRESTConfig is returned when the framework was not initialised with a REST config.
+	errNoRESTConfig = errors.New("framework has no REST config")
+
+	// errNoEtcdPods is returned when no running etcd pods could be found.
+	errNoEtcdPods = errors.New("no running etcd pods found")
+
+	// errNoEtcdLeader is returned when none of the etcd endpoints report being the leader.
+	errNoEtcdLeader = errors.New("no etcd endpoint reported being the leader")
+
+	// errNoNodeForAddress is returned when no node has the given internal IP address.
+	errNoNodeForAddress = errors.New("no node found with internal IP address")
+)
+
+// etcdEndpointStatus is the subset of the etcdctl endpoint status JSON output
+// required to determine which endpoint is the current leader.
+// The field names are defined by etcdctl and so do not follow our conventions.
+type etcdEndpointStatus struct {
+	Endpoint string `json:"Endpoint"` //nolint:tagliatelle
+	Status   struct {
+		Header struct {
+			MemberID uint64 `json:"member_id"` //nolint:tagliatelle
+		} `json:"header"`
+		Leader uint64 `json:"leader"`
+	} `json:"Status"` //nolint:tagliatelle
+}
+
+// selectLeaderEndpoint returns the endpoint of the etcd member that each
+// member agrees is the leader.
+func selectLeaderEndpoint(statuses []etcdEndpointStatus) (string, error) {
+	for _, status := range statuses {
+		if status.Status.Leader != 0 && status.Status.Header.MemberID == status.Status.Leader {
+			return status.Endpoint, nil
+		}
+	}
+
+	return "", errNoEtcdLeader
+}
+
+// EtcdLeaderNodeName returns the name of the node hosting the current etcd leader.
+// It executes etcdctl within one of the running etcd pods to determine the leader
+// endpoint, and then maps the endpoint address back to the node with that internal IP.
+func (f *framework) EtcdLeaderNodeName() (string, error) {
+	ctx := f.GetContext()
+
+	if f.config == nil {
+		return "", errNoRESTConfig
+	}
+
+	podList := &corev1.PodList{}
+	if err := f.client.List(ctx, podList, runtimeclient.InNamespace(etcdNamespace), runtimeclient.MatchingLabels{"app": "etcd"}); err != nil {
+		return "", fmt.Errorf("failed to list etcd pods: %w", err)
+	}
+
+	var pod *corev1.Pod
+
+	for i := range podList.Items {
+		if podList.Items[i].Status.Phase == corev1.PodRunning {
+			pod = &podList.Items[i]
+			break
+		}
+	}
+
+	if pod == nil {
+		return "", errNoEtcdPods
+	}
+
+	stdout, err := f.execInPod(pod, etcdctlContainerName, "etcdctl", "endpoint", "status", "--write-out=json")
+	if err != nil {
+		return "", fmt.Errorf("failed to get etcd endpoint status: %w", err)
+	}
+
+	statuses := []etcdEndpointStatus{}
+	if err := json.Unmarshal(stdout, &statuses); err != nil {
+		return "", fmt.Errorf("failed to unmarshal etcd endpoint status: %w", err)
+	}
+
+	leaderEndpoint, err := selectLeaderEndpoint(statuses)
+	if err != nil {
+		return "", err
+	}
+
+	leaderURL, err := url.Parse(leaderEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse etcd leader endpoint %q: %w", leaderEndpoint, err)
+	}
+
+	return f.nodeNameForInternalIP(leaderURL.Hostname())
+}
+
+// nodeNameForInternalIP returns the name of the node with the given internal IP address.
+func (f *framework) nodeNameForInternalIP(address string) (string, error) {
+	nodeList := &corev1.NodeList{}
+	if err := f.client.List(f.GetContext(), nodeList); err != nil {
+		return "", fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	for _, node := range nodeList.Items {
+		for _, nodeAddress := range node.Status.Addresses {
+			if nodeAddress.Type == corev1.NodeInternalIP && net.ParseIP(nodeAddress.Address).Equal(net.ParseIP(address)) {
+				return node.Name, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", errNoNodeForAddress, address)
+}
+
+// execInPod executes the command within the given container of the pod and returns the stdout.
+func (f *framework) execInPod(pod *corev1.Pod, container string, command ...string) ([]byte, error) {
+	if f.config == nil {
+		return nil, errNoRESTConfig
+	}
+
+	clientset, err := kubernetes.NewForConfig(f.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+	}
+
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(f.config, "POST", req.URL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pod executor: %w", err)
+	}
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	if err := executor.Stream(remotecommand.StreamOptions{
+		Stdout: stdout,
+		Stderr: stderr,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to execute command in pod %s/%s: %w: %s", pod.Namespace, pod.Name, err, stderr.String())
+	}
+
+	return stdout.Bytes(), nil
+}
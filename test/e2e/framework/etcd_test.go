@@ -0,0 +1,68 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Etcd", func() {
+	Context("selectLeaderEndpoint", func() {
+		type selectLeaderEndpointTableInput struct {
+			endpointStatus   string
+			expectedEndpoint string
+			expectedError    error
+		}
+
+		DescribeTable("should return the leader endpoint", func(in selectLeaderEndpointTableInput) {
+			statuses := []etcdEndpointStatus{}
+			Expect(json.Unmarshal([]byte(in.endpointStatus), &statuses)).To(Succeed())
+
+			endpoint, err := selectLeaderEndpoint(statuses)
+			if in.expectedError != nil {
+				Expect(err).To(MatchError(in.expectedError))
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			Expect(endpoint).To(Equal(in.expectedEndpoint))
+		},
+			Entry("when the second member is the leader", selectLeaderEndpointTableInput{
+				endpointStatus: `[
+					{"Endpoint":"https://10.0.0.1:2379","Status":{"header":{"member_id":1},"leader":2}},
+					{"Endpoint":"https://10.0.0.2:2379","Status":{"header":{"member_id":2},"leader":2}},
+					{"Endpoint":"https://10.0.0.3:2379","Status":{"header":{"member_id":3},"leader":2}}
+				]`,
+				expectedEndpoint: "https://10.0.0.2:2379",
+			}),
+			Entry("when no member is the leader", selectLeaderEndpointTableInput{
+				endpointStatus: `[
+					{"Endpoint":"https://10.0.0.1:2379","Status":{"header":{"member_id":1},"leader":0}},
+					{"Endpoint":"https://10.0.0.2:2379","Status":{"header":{"member_id":2},"leader":0}}
+				]`,
+				expectedError: errNoEtcdLeader,
+			}),
+			Entry("when there are no endpoints", selectLeaderEndpointTableInput{
+				endpointStatus: `[]`,
+				expectedError:  errNoEtcdLeader,
+			}),
+		)
+	})
+})
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"time"
 
 	configv1 "github.com/openshift/api/config/v1"
 	machinev1 "github.com/openshift/api/machine/v1"
@@ -34,6 +35,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
@@ -88,6 +90,47 @@ type Framework interface {
 	// ConvertToControlPlaneMachineSetProviderSpec converts a control plane machine provider spec
 	// to a control plane machine set suitable provider spec.
 	ConvertToControlPlaneMachineSetProviderSpec(providerSpec machinev1beta1.ProviderSpec) (*runtime.RawExtension, error)
+
+	// EtcdLeaderNodeName returns the name of the node hosting the current etcd leader.
+	EtcdLeaderNodeName() (string, error)
+
+	// IsControlPlaneMachineSetGenerated returns whether the control plane machine set
+	// was generated by the operator, rather than created by a user.
+	IsControlPlaneMachineSetGenerated() (bool, error)
+
+	// DelayNodeReadiness holds the Ready condition of the node as False for the given duration.
+	DelayNodeReadiness(nodeName string, d time.Duration) error
+
+	// TriggerCPMSReconcile bumps an annotation on the control plane machine set to force the
+	// operator to reconcile it.
+	TriggerCPMSReconcile() error
+
+	// ControlPlaneMachineIndexes returns the sorted indexes of the control plane machines.
+	ControlPlaneMachineIndexes() ([]int, error)
+
+	// OperatorMetricValue returns the value of the metric with the given name and labels, as exposed
+	// by the control plane machine set operator.
+	OperatorMetricValue(name string, labels map[string]string) (float64, error)
+
+	// NodeKubeletVersion returns the kubelet version reported by the node of the machine.
+	NodeKubeletVersion(machine *machinev1beta1.Machine) (string, error)
+
+	// NodeInternalIP returns the internal IP address reported by the node of the machine.
+	NodeInternalIP(machine *machinev1beta1.Machine) (string, error)
+
+	// APIServerVersion returns the Kubernetes version reported by the API server.
+	APIServerVersion() (string, error)
+
+	// IsMachineHostingAPIServerLeader returns whether the node of the machine hosts the current
+	// kube-apiserver leader.
+	IsMachineHostingAPIServerLeader(machine *machinev1beta1.Machine) (bool, error)
+
+	// MachinesBeingDeleted returns the control plane machines with a deletion timestamp.
+	MachinesBeingDeleted() ([]machinev1beta1.Machine, error)
+
+	// OperatorLeaderIdentity returns the identity of the control plane machine set operator
+	// replica holding the leader election lease.
+	OperatorLeaderIdentity() (string, error)
 }
 
 // PlatformSupportLevel is used to identify which tests should run
@@ -111,6 +154,7 @@ const (
 // test cases.
 type framework struct {
 	client       runtimeclient.Client
+	config       *rest.Config
 	platform     configv1.PlatformType
 	supportLevel PlatformSupportLevel
 	scheme       *runtime.Scheme
@@ -124,7 +168,12 @@ func NewFramework() (Framework, error) {
 		return nil, err
 	}
 
-	client, err := loadClient(sch)
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes config: %w", err)
+	}
+
+	client, err := loadClient(cfg, sch)
 	if err != nil {
 		return nil, err
 	}
@@ -136,6 +185,7 @@ func NewFramework() (Framework, error) {
 
 	return &framework{
 		client:       client,
+		config:       cfg,
 		platform:     platform,
 		supportLevel: supportLevel,
 		scheme:       sch,
@@ -145,6 +195,9 @@ func NewFramework() (Framework, error) {
 
 // NewFrameworkWith initialises a new test framework for the E2E suite
 // using the existing scheme, client, platform and support level provided.
+// Frameworks created this way have no REST config, so functionality that
+// requires direct access to the API server, such as executing commands within
+// pods, is not available.
 func NewFrameworkWith(sch *runtime.Scheme, client runtimeclient.Client, platform configv1.PlatformType, supportLevel PlatformSupportLevel, namespace string) Framework {
 	return &framework{
 		client:       client,
@@ -200,6 +253,51 @@ func (f *framework) NewEmptyControlPlaneMachineSet() *machinev1.ControlPlaneMach
 	}
 }
 
+// IsControlPlaneMachineSetGenerated returns whether the control plane machine set
+// was generated by the operator, based on the origin annotation.
+func (f *framework) IsControlPlaneMachineSetGenerated() (bool, error) {
+	cpms := f.NewEmptyControlPlaneMachineSet()
+
+	if err := f.client.Get(f.GetContext(), f.ControlPlaneMachineSetKey(), cpms); err != nil {
+		return false, fmt.Errorf("failed to get control plane machine set: %w", err)
+	}
+
+	return isControlPlaneMachineSetGenerated(cpms), nil
+}
+
+// TriggerCPMSReconcile bumps the ReconcileTriggerAnnotationKey annotation on the control plane machine set.
+// Any update to the control plane machine set causes the operator to reconcile it, and as the annotation
+// is not part of the spec, it is ignored when determining whether machines need to be updated.
+func (f *framework) TriggerCPMSReconcile() error {
+	cpms := f.NewEmptyControlPlaneMachineSet()
+
+	if err := f.client.Get(f.GetContext(), f.ControlPlaneMachineSetKey(), cpms); err != nil {
+		return fmt.Errorf("failed to get control plane machine set: %w", err)
+	}
+
+	patchBase := runtimeclient.MergeFrom(cpms.DeepCopy())
+
+	annotations := cpms.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[ReconcileTriggerAnnotationKey] = time.Now().Format(time.RFC3339Nano)
+	cpms.SetAnnotations(annotations)
+
+	if err := f.client.Patch(f.GetContext(), cpms, patchBase); err != nil {
+		return fmt.Errorf("failed to patch control plane machine set: %w", err)
+	}
+
+	return nil
+}
+
+// isControlPlaneMachineSetGenerated returns whether the control plane machine set carries
+// the origin annotation set by the control plane machine set generator.
+func isControlPlaneMachineSetGenerated(cpms *machinev1.ControlPlaneMachineSet) bool {
+	return cpms.GetAnnotations()[ControlPlaneMachineSetOriginAnnotationKey] == ControlPlaneMachineSetOriginGenerated
+}
+
 // IncreaseProviderSpecInstanceSize increases the instance size of the instance on the providerSpec
 // that is passed.
 func (f *framework) IncreaseProviderSpecInstanceSize(rawProviderSpec *runtime.RawExtension) error {
@@ -296,12 +394,7 @@ func convertAzureProviderConfigToControlPlaneMachineSetProviderSpec(providerConf
 }
 
 // loadClient returns a new controller-runtime client.
-func loadClient(sch *runtime.Scheme) (runtimeclient.Client, error) {
-	cfg, err := config.GetConfig()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get Kubernetes config: %w", err)
-	}
-
+func loadClient(cfg *rest.Config, sch *runtime.Scheme) (runtimeclient.Client, error) {
 	client, err := runtimeclient.New(cfg, runtimeclient.Options{
 		Scheme: sch,
 	})
@@ -21,6 +21,10 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+
+	machinev1 "github.com/openshift/api/machine/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var _ = Describe("Framwork", func() {
@@ -202,4 +206,29 @@ var _ = Describe("Framwork", func() {
 			})
 		})
 	})
+
+	Context("isControlPlaneMachineSetGenerated", func() {
+		DescribeTable("should report whether the control plane machine set was generated", func(annotations map[string]string, expected bool) {
+			cpms := &machinev1.ControlPlaneMachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        ControlPlaneMachineSetName,
+					Namespace:   MachineAPINamespace,
+					Annotations: annotations,
+				},
+			}
+
+			Expect(isControlPlaneMachineSetGenerated(cpms)).To(Equal(expected))
+		},
+			Entry("when the origin annotation is generated", map[string]string{
+				ControlPlaneMachineSetOriginAnnotationKey: ControlPlaneMachineSetOriginGenerated,
+			}, true),
+			Entry("when the origin annotation has another value", map[string]string{
+				ControlPlaneMachineSetOriginAnnotationKey: "user",
+			}, false),
+			Entry("when the origin annotation is not present", map[string]string{
+				"foo": "bar",
+			}, false),
+			Entry("when there are no annotations", nil, false),
+		)
+	})
 })
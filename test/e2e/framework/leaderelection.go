@@ -0,0 +1,66 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"errors"
+	"fmt"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// operatorLeaderLeaseName is the name of the lease elected between the control plane machine set operator replicas.
+const operatorLeaderLeaseName = "control-plane-machine-set-leader"
+
+// ErrOperatorLeaderNotObservable is returned when the operator leader lease does not exist, or is not held.
+// Checks on the operator leader should be skipped when the leader cannot be observed.
+var ErrOperatorLeaderNotObservable = errors.New("control plane machine set operator leader is not observable")
+
+// OperatorLeaderIdentity returns the identity of the control plane machine set operator replica that currently
+// holds the leader election lease.
+// The identity is of the form <pod name>_<uuid>, where the UUID is generated each time the operator starts.
+func (f *framework) OperatorLeaderIdentity() (string, error) {
+	lease := &coordinationv1.Lease{}
+	key := runtimeclient.ObjectKey{Namespace: MachineAPINamespace, Name: operatorLeaderLeaseName}
+
+	err := f.client.Get(f.GetContext(), key, lease)
+
+	switch {
+	case apierrors.IsNotFound(err):
+		return "", fmt.Errorf("%w: lease %s not found", ErrOperatorLeaderNotObservable, key)
+	case err != nil:
+		return "", fmt.Errorf("failed to get lease %s: %w", key, err)
+	}
+
+	identity, ok := leaseHolderIdentity(lease)
+	if !ok {
+		return "", fmt.Errorf("%w: lease %s has no holder", ErrOperatorLeaderNotObservable, key)
+	}
+
+	return identity, nil
+}
+
+// leaseHolderIdentity returns the identity of the holder of the lease, and whether the lease is held.
+func leaseHolderIdentity(lease *coordinationv1.Lease) (string, bool) {
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" {
+		return "", false
+	}
+
+	return *lease.Spec.HolderIdentity, true
+}
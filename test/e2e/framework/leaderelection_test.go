@@ -0,0 +1,46 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("LeaderElection", func() {
+	Context("leaseHolderIdentity", func() {
+		DescribeTable("should return the identity of the lease holder",
+			func(holderIdentity *string, expectedIdentity string, expectedHeld bool) {
+				lease := &coordinationv1.Lease{
+					Spec: coordinationv1.LeaseSpec{
+						HolderIdentity: holderIdentity,
+					},
+				}
+
+				identity, held := leaseHolderIdentity(lease)
+				Expect(identity).To(Equal(expectedIdentity))
+				Expect(held).To(Equal(expectedHeld))
+			},
+			Entry("with a holder", pointer.String("control-plane-machine-set-operator-5d8f7c9b6-abcde_5c7a6f2e"), "control-plane-machine-set-operator-5d8f7c9b6-abcde_5c7a6f2e", true),
+			Entry("with an empty holder", pointer.String(""), "", false),
+			Entry("without a holder", nil, "", false),
+		)
+	})
+})
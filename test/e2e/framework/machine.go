@@ -0,0 +1,232 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/onsi/ginkgo/v2"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// errNilMachine is returned when the machine passed is nil.
+	errNilMachine = errors.New("machine is nil")
+
+	// errMachineNameWithoutIndex is returned when the machine name does not end with an index.
+	errMachineNameWithoutIndex = errors.New("machine name does not end with an index")
+
+	// errNoProviderID is returned when the machine does not have a provider ID.
+	errNoProviderID = errors.New("machine has no provider ID")
+
+	// errInvalidProviderID is returned when the provider ID does not match the format expected for the platform.
+	errInvalidProviderID = errors.New("provider ID does not match the expected format")
+
+	// machineIndexRegexp matches the index suffix of a control plane machine name.
+	machineIndexRegexp = regexp.MustCompile(`^.*-([0-9]+)$`)
+
+	// providerIDRegexps match the provider IDs set on machines, per platform.
+	// AWS provider IDs are of the form aws:///<availability-zone>/<instance-id>.
+	// Azure provider IDs are of the form azure:///subscriptions/<subscription>/resourceGroups/<resource-group>/providers/Microsoft.Compute/virtualMachines/<name>.
+	// GCP provider IDs are of the form gce://<project>/<zone>/<instance-name>.
+	providerIDRegexps = map[configv1.PlatformType]*regexp.Regexp{
+		configv1.AWSPlatformType:   regexp.MustCompile(`^aws:///[a-z0-9-]+/i-[0-9a-f]+$`),
+		configv1.AzurePlatformType: regexp.MustCompile(`(?i)^azure:///subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Compute/virtualMachines/[^/]+$`),
+		configv1.GCPPlatformType:   regexp.MustCompile(`^gce://[^/]+/[a-z0-9-]+/[a-z0-9-]+$`),
+	}
+)
+
+// MachineLifecycleHooks returns the lifecycle hooks configured on the machine.
+// When no hooks are set, empty lists of pre-drain and pre-terminate hooks are returned.
+func MachineLifecycleHooks(machine *machinev1beta1.Machine) (machinev1beta1.LifecycleHooks, error) {
+	if machine == nil {
+		return machinev1beta1.LifecycleHooks{}, errNilMachine
+	}
+
+	hooks := machinev1beta1.LifecycleHooks{
+		PreDrain:     []machinev1beta1.LifecycleHook{},
+		PreTerminate: []machinev1beta1.LifecycleHook{},
+	}
+
+	hooks.PreDrain = append(hooks.PreDrain, machine.Spec.LifecycleHooks.PreDrain...)
+	hooks.PreTerminate = append(hooks.PreTerminate, machine.Spec.LifecycleHooks.PreTerminate...)
+
+	return hooks, nil
+}
+
+// MachineSpecTaints returns the taints the machine applies to its node.
+// When no taints are set, an empty list is returned.
+func MachineSpecTaints(machine *machinev1beta1.Machine) ([]corev1.Taint, error) {
+	if machine == nil {
+		return nil, errNilMachine
+	}
+
+	return append([]corev1.Taint{}, machine.Spec.Taints...), nil
+}
+
+// SetMachineSpecTaints sets the taints the machine applies to its node.
+func SetMachineSpecTaints(machine *machinev1beta1.Machine, taints []corev1.Taint) error {
+	if machine == nil {
+		return errNilMachine
+	}
+
+	machine.Spec.Taints = append([]corev1.Taint{}, taints...)
+
+	return nil
+}
+
+// ValidateProviderIDFormat checks that the provider ID of the machine matches the format expected for the platform
+// of the machine's provider spec. A malformed provider ID breaks the link between the machine and its node.
+func ValidateProviderIDFormat(machine *machinev1beta1.Machine) error {
+	if machine == nil {
+		return errNilMachine
+	}
+
+	if machine.Spec.ProviderID == nil || *machine.Spec.ProviderID == "" {
+		return fmt.Errorf("%w: %s", errNoProviderID, machine.Name)
+	}
+
+	providerConfig, err := providerConfigFromRawExtension(machine.Spec.ProviderSpec.Value)
+	if err != nil {
+		return err
+	}
+
+	re, ok := providerIDRegexps[providerConfig.Type()]
+	if !ok {
+		return fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	if !re.MatchString(*machine.Spec.ProviderID) {
+		return fmt.Errorf("%w for platform %s: %s", errInvalidProviderID, providerConfig.Type(), *machine.Spec.ProviderID)
+	}
+
+	return nil
+}
+
+// MachineAuthoritativeAPI returns the authoritative API of the machine, as set in spec.authoritativeAPI.
+// An empty string is returned when the field is not set, for example on clusters without the Machine API
+// migration feature, or when the Machine type in use predates the field.
+func MachineAuthoritativeAPI(machine *machinev1beta1.Machine) (string, error) {
+	if machine == nil {
+		return "", errNilMachine
+	}
+
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(machine)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert machine to unstructured: %w", err)
+	}
+
+	authoritativeAPI, _, err := unstructured.NestedString(object, "spec", "authoritativeAPI")
+	if err != nil {
+		return "", fmt.Errorf("failed to read the authoritative API: %w", err)
+	}
+
+	return authoritativeAPI, nil
+}
+
+// ControlPlaneMachineIndexes returns the sorted indexes of the control plane machines.
+// The index of each machine is parsed from the suffix of its name.
+// An index that is shared by multiple machines, for example while the machine in the index is being
+// replaced, is returned for each machine, and a warning is written to the test output.
+func (f *framework) ControlPlaneMachineIndexes() ([]int, error) {
+	machineList := &machinev1beta1.MachineList{}
+	if err := f.client.List(f.GetContext(), machineList, runtimeclient.MatchingLabels(ControlPlaneMachineSetSelectorLabels())); err != nil {
+		return nil, fmt.Errorf("failed to list control plane machines: %w", err)
+	}
+
+	indexes, duplicates, err := machineIndexes(machineList.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(duplicates) > 0 {
+		fmt.Fprintf(ginkgo.GinkgoWriter, "WARNING: found multiple control plane machines in indexes %v\n", duplicates)
+	}
+
+	return indexes, nil
+}
+
+// MachinesBeingDeleted returns the control plane machines with a deletion timestamp, sorted by name.
+// A machine remains in this list from the point it is deleted until its finalizers are removed, which, for
+// control plane machines, includes draining the node and waiting for the etcd member to be removed.
+func (f *framework) MachinesBeingDeleted() ([]machinev1beta1.Machine, error) {
+	machineList := &machinev1beta1.MachineList{}
+	if err := f.client.List(f.GetContext(), machineList, runtimeclient.MatchingLabels(ControlPlaneMachineSetSelectorLabels())); err != nil {
+		return nil, fmt.Errorf("failed to list control plane machines: %w", err)
+	}
+
+	return deletingMachines(machineList.Items), nil
+}
+
+// deletingMachines returns the machines with a deletion timestamp, sorted by name.
+func deletingMachines(machines []machinev1beta1.Machine) []machinev1beta1.Machine {
+	deleting := []machinev1beta1.Machine{}
+
+	for _, machine := range machines {
+		if machine.DeletionTimestamp != nil {
+			deleting = append(deleting, machine)
+		}
+	}
+
+	sort.Slice(deleting, func(i, j int) bool {
+		return deleting[i].Name < deleting[j].Name
+	})
+
+	return deleting
+}
+
+// machineIndexes returns the sorted indexes of the machines, and the sorted indexes that are shared by
+// more than one machine.
+func machineIndexes(machines []machinev1beta1.Machine) ([]int, []int, error) {
+	indexes := []int{}
+	duplicates := []int{}
+	seen := map[int]int{}
+
+	for _, machine := range machines {
+		matches := machineIndexRegexp.FindStringSubmatch(machine.Name)
+		if len(matches) != 2 {
+			return nil, nil, fmt.Errorf("%w: %s", errMachineNameWithoutIndex, machine.Name)
+		}
+
+		index, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse machine name suffix: %w", err)
+		}
+
+		if seen[index]++; seen[index] == 2 {
+			duplicates = append(duplicates, index)
+		}
+
+		indexes = append(indexes, index)
+	}
+
+	sort.Ints(indexes)
+	sort.Ints(duplicates)
+
+	return indexes, duplicates, nil
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/cluster-control-plane-machine-set-operator/pkg/test/resourcebuilder"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Machine", func() {
+	Context("MachineLifecycleHooks", func() {
+		It("should return the lifecycle hooks of the machine", func() {
+			machine := resourcebuilder.Machine().Build()
+			machine.Spec.LifecycleHooks = machinev1beta1.LifecycleHooks{
+				PreDrain: []machinev1beta1.LifecycleHook{
+					{Name: "EtcdQuorumOperator", Owner: "clusteroperator/etcd"},
+				},
+			}
+
+			hooks, err := MachineLifecycleHooks(machine)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hooks.PreDrain).To(ConsistOf(machinev1beta1.LifecycleHook{Name: "EtcdQuorumOperator", Owner: "clusteroperator/etcd"}))
+			Expect(hooks.PreTerminate).To(BeEmpty())
+		})
+
+		It("should return empty hooks when none are set", func() {
+			hooks, err := MachineLifecycleHooks(resourcebuilder.Machine().Build())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hooks.PreDrain).ToNot(BeNil())
+			Expect(hooks.PreDrain).To(BeEmpty())
+			Expect(hooks.PreTerminate).ToNot(BeNil())
+			Expect(hooks.PreTerminate).To(BeEmpty())
+		})
+
+		It("should return an error when the machine is nil", func() {
+			_, err := MachineLifecycleHooks(nil)
+			Expect(err).To(MatchError(errNilMachine))
+		})
+	})
+
+	Context("MachineSpecTaints", func() {
+		controlPlaneTaint := corev1.Taint{Key: "node-role.kubernetes.io/master", Effect: corev1.TaintEffectNoSchedule}
+
+		It("should return the taints set on the machine", func() {
+			machine := resourcebuilder.Machine().Build()
+			Expect(SetMachineSpecTaints(machine, []corev1.Taint{controlPlaneTaint})).To(Succeed())
+
+			taints, err := MachineSpecTaints(machine)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(taints).To(ConsistOf(controlPlaneTaint))
+		})
+
+		It("should return empty taints when none are set", func() {
+			taints, err := MachineSpecTaints(resourcebuilder.Machine().Build())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(taints).ToNot(BeNil())
+			Expect(taints).To(BeEmpty())
+		})
+
+		It("should return an error when the machine is nil", func() {
+			_, err := MachineSpecTaints(nil)
+			Expect(err).To(MatchError(errNilMachine))
+			Expect(SetMachineSpecTaints(nil, nil)).To(MatchError(errNilMachine))
+		})
+	})
+
+	Context("machineIndexes", func() {
+		It("should return the sorted indexes of the machines", func() {
+			indexes, duplicates, err := machineIndexes([]machinev1beta1.Machine{
+				*resourcebuilder.Machine().WithName("cluster-master-2").Build(),
+				*resourcebuilder.Machine().WithName("cluster-master-0").Build(),
+				*resourcebuilder.Machine().WithName("cluster-master-1").Build(),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(indexes).To(Equal([]int{0, 1, 2}))
+			Expect(duplicates).To(BeEmpty())
+		})
+
+		It("should return duplicate indexes", func() {
+			indexes, duplicates, err := machineIndexes([]machinev1beta1.Machine{
+				*resourcebuilder.Machine().WithName("cluster-master-0").Build(),
+				*resourcebuilder.Machine().WithName("cluster-master-abcde-0").Build(),
+				*resourcebuilder.Machine().WithName("cluster-master-1").Build(),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(indexes).To(Equal([]int{0, 0, 1}))
+			Expect(duplicates).To(Equal([]int{0}))
+		})
+
+		It("should return an error when a machine name does not end with an index", func() {
+			_, _, err := machineIndexes([]machinev1beta1.Machine{
+				*resourcebuilder.Machine().WithName("cluster-master").Build(),
+			})
+			Expect(err).To(MatchError(errMachineNameWithoutIndex))
+		})
+	})
+
+	Context("deletingMachines", func() {
+		It("should return the machines with a deletion timestamp, sorted by name", func() {
+			deletionTimestamp := metav1.Now()
+
+			deletingMachine := func(name string) machinev1beta1.Machine {
+				machine := resourcebuilder.Machine().WithName(name).Build()
+				machine.DeletionTimestamp = &deletionTimestamp
+
+				return *machine
+			}
+
+			deleting := deletingMachines([]machinev1beta1.Machine{
+				deletingMachine("cluster-master-2"),
+				*resourcebuilder.Machine().WithName("cluster-master-1").Build(),
+				deletingMachine("cluster-master-0"),
+			})
+			Expect(deleting).To(HaveLen(2))
+			Expect(deleting[0].Name).To(Equal("cluster-master-0"))
+			Expect(deleting[1].Name).To(Equal("cluster-master-2"))
+		})
+
+		It("should return an empty list when no machine is being deleted", func() {
+			Expect(deletingMachines([]machinev1beta1.Machine{
+				*resourcebuilder.Machine().WithName("cluster-master-0").Build(),
+			})).To(BeEmpty())
+		})
+	})
+
+	Context("ValidateProviderIDFormat", func() {
+		machineWithProviderID := func(builder resourcebuilder.RawExtensionBuilder, providerID string) *machinev1beta1.Machine {
+			machine := resourcebuilder.Machine().WithName("cluster-master-0").WithProviderSpecBuilder(builder).Build()
+			machine.Spec.ProviderID = &providerID
+
+			return machine
+		}
+
+		DescribeTable("should accept well-formed provider IDs",
+			func(builder resourcebuilder.RawExtensionBuilder, providerID string) {
+				Expect(ValidateProviderIDFormat(machineWithProviderID(builder, providerID))).To(Succeed())
+			},
+			Entry("on AWS", resourcebuilder.AWSProviderSpec(), "aws:///us-east-1a/i-0123456789abcdef0"),
+			Entry("on Azure", resourcebuilder.AzureProviderSpec(), "azure:///subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/cluster-rg/providers/Microsoft.Compute/virtualMachines/cluster-master-0"),
+			Entry("on Azure with a lower case resource group segment", resourcebuilder.AzureProviderSpec(), "azure:///subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/cluster-rg/providers/Microsoft.Compute/virtualMachines/cluster-master-0"),
+			Entry("on GCP", resourcebuilder.GCPProviderSpec(), "gce://openshift-project/us-central1-a/cluster-master-0"),
+		)
+
+		DescribeTable("should reject malformed provider IDs",
+			func(builder resourcebuilder.RawExtensionBuilder, providerID string) {
+				Expect(ValidateProviderIDFormat(machineWithProviderID(builder, providerID))).To(MatchError(errInvalidProviderID))
+			},
+			Entry("on AWS without an availability zone", resourcebuilder.AWSProviderSpec(), "aws:///i-0123456789abcdef0"),
+			Entry("on AWS with the wrong scheme", resourcebuilder.AWSProviderSpec(), "gce:///us-east-1a/i-0123456789abcdef0"),
+			Entry("on Azure without a virtual machine name", resourcebuilder.AzureProviderSpec(), "azure:///subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/cluster-rg/providers/Microsoft.Compute/virtualMachines/"),
+			Entry("on GCP without a zone", resourcebuilder.GCPProviderSpec(), "gce://openshift-project/cluster-master-0"),
+			Entry("on GCP with an AWS provider ID", resourcebuilder.GCPProviderSpec(), "aws:///us-east-1a/i-0123456789abcdef0"),
+		)
+
+		It("should return an error when the provider ID is not set", func() {
+			machine := resourcebuilder.Machine().WithProviderSpecBuilder(resourcebuilder.AWSProviderSpec()).Build()
+			Expect(ValidateProviderIDFormat(machine)).To(MatchError(errNoProviderID))
+
+			Expect(ValidateProviderIDFormat(machineWithProviderID(resourcebuilder.AWSProviderSpec(), ""))).To(MatchError(errNoProviderID))
+		})
+
+		It("should return an error for vSphere", func() {
+			providerID := "vsphere://42000000-0000-0000-0000-000000000000"
+			machine := resourcebuilder.Machine().Build()
+			machine.Spec.ProviderID = &providerID
+			machine.Spec.ProviderSpec.Value = &runtime.RawExtension{
+				Raw: []byte(`{"kind":"VSphereMachineProviderSpec","apiVersion":"machine.openshift.io/v1beta1"}`),
+			}
+
+			Expect(ValidateProviderIDFormat(machine)).To(MatchError(errUnsupportedPlatform))
+		})
+
+		It("should return an error when the machine is nil", func() {
+			Expect(ValidateProviderIDFormat(nil)).To(MatchError(errNilMachine))
+		})
+	})
+
+	Context("MachineAuthoritativeAPI", func() {
+		It("should return an empty authoritative API when none is set", func() {
+			authoritativeAPI, err := MachineAuthoritativeAPI(&machinev1beta1.Machine{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(authoritativeAPI).To(BeEmpty())
+		})
+
+		It("should return an error when the machine is nil", func() {
+			_, err := MachineAuthoritativeAPI(nil)
+			Expect(err).To(MatchError(errNilMachine))
+		})
+	})
+})
@@ -0,0 +1,167 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// operatorAppLabel is the value of the k8s-app label on the control plane machine set operator pods.
+	operatorAppLabel = "control-plane-machine-set-operator"
+
+	// operatorMetricsPort is the port on which the control plane machine set operator serves metrics.
+	operatorMetricsPort = "8080"
+)
+
+var (
+	// errNoOperatorPods is returned when no running operator pods could be found.
+	errNoOperatorPods = errors.New("no running control plane machine set operator pods found")
+
+	// errMetricNotFound is returned when no series of the metric matches the given labels.
+	errMetricNotFound = errors.New("metric not found")
+)
+
+// OperatorMetricValue returns the value of the metric with the given name and labels, as exposed on the metrics
+// endpoint of the control plane machine set operator. The metrics of each running operator pod are scraped through
+// the API server pod proxy, and the values of all matching series are summed.
+func (f *framework) OperatorMetricValue(name string, labels map[string]string) (float64, error) {
+	ctx := f.GetContext()
+
+	if f.config == nil {
+		return 0, errNoRESTConfig
+	}
+
+	podList := &corev1.PodList{}
+	if err := f.client.List(ctx, podList, runtimeclient.InNamespace(MachineAPINamespace), runtimeclient.MatchingLabels{"k8s-app": operatorAppLabel}); err != nil {
+		return 0, fmt.Errorf("failed to list operator pods: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(f.config)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+	}
+
+	var (
+		total float64
+		found bool
+	)
+
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+
+		exposition, err := clientset.CoreV1().Pods(pod.Namespace).ProxyGet("http", pod.Name, operatorMetricsPort, "/metrics", nil).DoRaw(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to scrape metrics from pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+
+		value, err := metricValue(exposition, name, labels)
+		if errors.Is(err, errMetricNotFound) {
+			continue
+		} else if err != nil {
+			return 0, fmt.Errorf("failed to read metrics from pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+
+		total += value
+		found = true
+	}
+
+	if !found {
+		if len(podList.Items) == 0 {
+			return 0, errNoOperatorPods
+		}
+
+		return 0, fmt.Errorf("%w: %s%v", errMetricNotFound, name, labels)
+	}
+
+	return total, nil
+}
+
+// metricValue parses the Prometheus text exposition and returns the summed value of the series of the named
+// metric that have all of the given labels.
+func metricValue(exposition []byte, name string, labels map[string]string) (float64, error) {
+	parser := expfmt.TextParser{}
+
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(exposition))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse metrics: %w", err)
+	}
+
+	family, ok := families[name]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", errMetricNotFound, name)
+	}
+
+	var (
+		total float64
+		found bool
+	)
+
+	for _, metric := range family.GetMetric() {
+		if !metricHasLabels(metric, labels) {
+			continue
+		}
+
+		switch {
+		case metric.GetCounter() != nil:
+			total += metric.GetCounter().GetValue()
+		case metric.GetGauge() != nil:
+			total += metric.GetGauge().GetValue()
+		case metric.GetUntyped() != nil:
+			total += metric.GetUntyped().GetValue()
+		default:
+			continue
+		}
+
+		found = true
+	}
+
+	if !found {
+		return 0, fmt.Errorf("%w: %s%v", errMetricNotFound, name, labels)
+	}
+
+	return total, nil
+}
+
+// metricHasLabels returns true when the metric has each of the given labels with the given value.
+func metricHasLabels(metric *dto.Metric, labels map[string]string) bool {
+	metricLabels := map[string]string{}
+
+	for _, label := range metric.GetLabel() {
+		metricLabels[label.GetName()] = label.GetValue()
+	}
+
+	for name, value := range labels {
+		if metricLabels[name] != value {
+			return false
+		}
+	}
+
+	return true
+}
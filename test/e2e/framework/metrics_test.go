@@ -0,0 +1,83 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metrics", func() {
+	Context("metricValue", func() {
+		const exposition = `# HELP control_plane_machine_set_machine_replacements_total Number of control plane machines created.
+# TYPE control_plane_machine_set_machine_replacements_total counter
+control_plane_machine_set_machine_replacements_total{platform="AWS",strategy="RollingUpdate"} 2
+control_plane_machine_set_machine_replacements_total{platform="AWS",strategy="OnDelete"} 1
+# HELP workqueue_depth Current depth of workqueue
+# TYPE workqueue_depth gauge
+workqueue_depth{name="controlplanemachineset"} 0
+`
+
+		type metricValueTableInput struct {
+			name          string
+			labels        map[string]string
+			expectedValue float64
+			expectedError error
+		}
+
+		DescribeTable("should return the value of the metric", func(in metricValueTableInput) {
+			value, err := metricValue([]byte(exposition), in.name, in.labels)
+			if in.expectedError != nil {
+				Expect(err).To(MatchError(in.expectedError))
+				return
+			}
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(value).To(Equal(in.expectedValue))
+		},
+			Entry("with matching labels", metricValueTableInput{
+				name:          "control_plane_machine_set_machine_replacements_total",
+				labels:        map[string]string{"platform": "AWS", "strategy": "RollingUpdate"},
+				expectedValue: 2,
+			}),
+			Entry("summing the series matching a subset of labels", metricValueTableInput{
+				name:          "control_plane_machine_set_machine_replacements_total",
+				labels:        map[string]string{"platform": "AWS"},
+				expectedValue: 3,
+			}),
+			Entry("with a gauge", metricValueTableInput{
+				name:          "workqueue_depth",
+				labels:        map[string]string{"name": "controlplanemachineset"},
+				expectedValue: 0,
+			}),
+			Entry("when no series matches the labels", metricValueTableInput{
+				name:          "control_plane_machine_set_machine_replacements_total",
+				labels:        map[string]string{"platform": "GCP"},
+				expectedError: errMetricNotFound,
+			}),
+			Entry("when the metric is not exposed", metricValueTableInput{
+				name:          "e2e_missing_metric_total",
+				expectedError: errMetricNotFound,
+			}),
+		)
+
+		It("should return an error when the exposition cannot be parsed", func() {
+			_, err := metricValue([]byte("not a metric {"), "control_plane_machine_set_machine_replacements_total", nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,239 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// delayedReadinessReason is the reason set on the node Ready condition while
+	// the readiness of the node is being delayed.
+	delayedReadinessReason = "E2EDelayedReadiness"
+
+	// delayedReadinessInterval is the interval at which the node Ready condition is
+	// re-applied while the readiness of the node is being delayed.
+	// The kubelet reports the real node status periodically, so this needs to be
+	// shorter than the kubelet node status update frequency.
+	delayedReadinessInterval = time.Second
+)
+
+var (
+	// errNoNodeRef is returned when the machine does not yet reference a node.
+	errNoNodeRef = errors.New("machine has no node reference")
+
+	// errInvalidKubernetesVersion is returned when a version string is not a Kubernetes version.
+	errInvalidKubernetesVersion = errors.New("invalid Kubernetes version")
+
+	// kubernetesVersionRegexp matches the major and minor components of a Kubernetes version, such as v1.25.2+5533733.
+	kubernetesVersionRegexp = regexp.MustCompile(`^v?([0-9]+)\.([0-9]+)(\.[0-9]+)?([-+].*)?$`)
+)
+
+// DelayNodeReadiness holds the Ready condition of the node as False for the given duration.
+// A node is considered Ready when its NodeReady condition has status True. The kubelet
+// will restore the real condition once the delay has elapsed.
+// This function blocks until the delay has elapsed.
+func (f *framework) DelayNodeReadiness(nodeName string, d time.Duration) error {
+	ctx, cancel := context.WithTimeout(f.GetContext(), d)
+	defer cancel()
+
+	err := wait.PollImmediateUntilWithContext(ctx, delayedReadinessInterval, func(ctx context.Context) (bool, error) {
+		node := &corev1.Node{}
+		if err := f.client.Get(ctx, runtimeclient.ObjectKey{Name: nodeName}, node); err != nil {
+			return false, fmt.Errorf("failed to get node %s: %w", nodeName, err)
+		}
+
+		if !setNodeNotReady(node) {
+			return false, nil
+		}
+
+		// Conflicts are expected as the kubelet also updates the node status.
+		if err := f.client.Status().Update(ctx, node); err != nil && !apierrors.IsConflict(err) {
+			return false, fmt.Errorf("failed to update node %s status: %w", nodeName, err)
+		}
+
+		return false, nil
+	})
+	if err != nil && !errors.Is(err, wait.ErrWaitTimeout) {
+		return fmt.Errorf("failed to delay readiness of node %s: %w", nodeName, err)
+	}
+
+	return nil
+}
+
+// setNodeNotReady sets the Ready condition of the node to False.
+// It returns whether the node status was changed.
+func setNodeNotReady(node *corev1.Node) bool {
+	for i := range node.Status.Conditions {
+		condition := &node.Status.Conditions[i]
+
+		if condition.Type != corev1.NodeReady {
+			continue
+		}
+
+		if condition.Status == corev1.ConditionFalse && condition.Reason == delayedReadinessReason {
+			return false
+		}
+
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = delayedReadinessReason
+		condition.Message = "Node readiness is being delayed by the e2e test suite"
+		condition.LastTransitionTime = metav1.Now()
+
+		return true
+	}
+
+	node.Status.Conditions = append(node.Status.Conditions, corev1.NodeCondition{
+		Type:               corev1.NodeReady,
+		Status:             corev1.ConditionFalse,
+		Reason:             delayedReadinessReason,
+		Message:            "Node readiness is being delayed by the e2e test suite",
+		LastTransitionTime: metav1.Now(),
+	})
+
+	return true
+}
+
+// IsNodeReady returns whether the node has a Ready condition with status True.
+func IsNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// NodeKubeletVersion returns the kubelet version reported by the node of the machine.
+// An empty version is returned when the node has not yet reported its node info.
+func (f *framework) NodeKubeletVersion(machine *machinev1beta1.Machine) (string, error) {
+	if machine == nil {
+		return "", errNilMachine
+	}
+
+	if machine.Status.NodeRef == nil || machine.Status.NodeRef.Name == "" {
+		return "", fmt.Errorf("%w: %s", errNoNodeRef, machine.Name)
+	}
+
+	node := &corev1.Node{}
+	if err := f.client.Get(f.GetContext(), runtimeclient.ObjectKey{Name: machine.Status.NodeRef.Name}, node); err != nil {
+		return "", fmt.Errorf("failed to get node %s: %w", machine.Status.NodeRef.Name, err)
+	}
+
+	return node.Status.NodeInfo.KubeletVersion, nil
+}
+
+// NodeInternalIP returns the internal IP address reported by the node of the machine.
+// An empty address is returned when the node has not yet reported an internal IP address.
+func (f *framework) NodeInternalIP(machine *machinev1beta1.Machine) (string, error) {
+	if machine == nil {
+		return "", errNilMachine
+	}
+
+	if machine.Status.NodeRef == nil || machine.Status.NodeRef.Name == "" {
+		return "", fmt.Errorf("%w: %s", errNoNodeRef, machine.Name)
+	}
+
+	node := &corev1.Node{}
+	if err := f.client.Get(f.GetContext(), runtimeclient.ObjectKey{Name: machine.Status.NodeRef.Name}, node); err != nil {
+		return "", fmt.Errorf("failed to get node %s: %w", machine.Status.NodeRef.Name, err)
+	}
+
+	return nodeInternalIP(node), nil
+}
+
+// nodeInternalIP returns the first internal IP address of the node, or an empty string if it has none.
+func nodeInternalIP(node *corev1.Node) string {
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP && address.Address != "" {
+			return address.Address
+		}
+	}
+
+	return ""
+}
+
+// APIServerVersion returns the Kubernetes version reported by the API server.
+func (f *framework) APIServerVersion() (string, error) {
+	if f.config == nil {
+		return "", errNoRESTConfig
+	}
+
+	clientset, err := kubernetes.NewForConfig(f.config)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+	}
+
+	serverVersion, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		return "", fmt.Errorf("failed to get API server version: %w", err)
+	}
+
+	return serverVersion.GitVersion, nil
+}
+
+// KubeletVersionMatchesClusterVersion returns whether the kubelet version has the same major and minor version
+// as the cluster version, as reported by the API server.
+// Patch versions and build metadata are ignored as these may legitimately differ between components.
+func KubeletVersionMatchesClusterVersion(kubeletVersion, clusterVersion string) (bool, error) {
+	kubeletMajor, kubeletMinor, err := parseKubernetesMajorMinor(kubeletVersion)
+	if err != nil {
+		return false, err
+	}
+
+	clusterMajor, clusterMinor, err := parseKubernetesMajorMinor(clusterVersion)
+	if err != nil {
+		return false, err
+	}
+
+	return kubeletMajor == clusterMajor && kubeletMinor == clusterMinor, nil
+}
+
+// parseKubernetesMajorMinor returns the major and minor components of the Kubernetes version.
+func parseKubernetesMajorMinor(version string) (int, int, error) {
+	matches := kubernetesVersionRegexp.FindStringSubmatch(version)
+	if matches == nil {
+		return 0, 0, fmt.Errorf("%w: %q", errInvalidKubernetesVersion, version)
+	}
+
+	major, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %q", errInvalidKubernetesVersion, version)
+	}
+
+	minor, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %q", errInvalidKubernetesVersion, version)
+	}
+
+	return major, minor, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Node", func() {
+	Context("setNodeNotReady", func() {
+		It("should set an existing Ready condition to False", func() {
+			node := &corev1.Node{
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{
+						{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+						{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+					},
+				},
+			}
+
+			Expect(IsNodeReady(node)).To(BeTrue())
+			Expect(setNodeNotReady(node)).To(BeTrue())
+			Expect(IsNodeReady(node)).To(BeFalse())
+			Expect(node.Status.Conditions).To(HaveLen(2))
+			Expect(node.Status.Conditions[1].Reason).To(Equal(delayedReadinessReason))
+		})
+
+		It("should not change a node whose readiness is already being delayed", func() {
+			node := &corev1.Node{}
+
+			Expect(setNodeNotReady(node)).To(BeTrue())
+			Expect(setNodeNotReady(node)).To(BeFalse())
+			Expect(node.Status.Conditions).To(HaveLen(1))
+			Expect(IsNodeReady(node)).To(BeFalse())
+		})
+	})
+
+	Context("nodeInternalIP", func() {
+		It("should return the internal IP address of the node", func() {
+			node := &corev1.Node{
+				Status: corev1.NodeStatus{
+					Addresses: []corev1.NodeAddress{
+						{Type: corev1.NodeHostName, Address: "master-0"},
+						{Type: corev1.NodeInternalIP, Address: "10.0.0.10"},
+						{Type: corev1.NodeInternalIP, Address: "10.0.0.11"},
+					},
+				},
+			}
+
+			Expect(nodeInternalIP(node)).To(Equal("10.0.0.10"))
+		})
+
+		It("should return an empty address when the node has no internal IP address", func() {
+			node := &corev1.Node{
+				Status: corev1.NodeStatus{
+					Addresses: []corev1.NodeAddress{
+						{Type: corev1.NodeExternalIP, Address: "203.0.113.10"},
+						{Type: corev1.NodeInternalIP, Address: ""},
+					},
+				},
+			}
+
+			Expect(nodeInternalIP(node)).To(BeEmpty())
+		})
+	})
+
+	Context("KubeletVersionMatchesClusterVersion", func() {
+		DescribeTable("should compare the major and minor versions",
+			func(kubeletVersion, clusterVersion string, expected bool) {
+				matches, err := KubeletVersionMatchesClusterVersion(kubeletVersion, clusterVersion)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(matches).To(Equal(expected))
+			},
+			Entry("with identical versions", "v1.25.2+5533733", "v1.25.2+5533733", true),
+			Entry("with different patch versions", "v1.25.0+3ef6ef3", "v1.25.2+5533733", true),
+			Entry("with versions without a prefix or patch", "1.25", "v1.25.2", true),
+			Entry("with different minor versions", "v1.24.6+5658434", "v1.25.2+5533733", false),
+			Entry("with different major versions", "v2.25.2", "v1.25.2", false),
+		)
+
+		DescribeTable("should return an error for invalid versions",
+			func(kubeletVersion, clusterVersion string) {
+				_, err := KubeletVersionMatchesClusterVersion(kubeletVersion, clusterVersion)
+				Expect(err).To(MatchError(errInvalidKubernetesVersion))
+			},
+			Entry("with an empty kubelet version", "", "v1.25.2"),
+			Entry("with an invalid kubelet version", "kubelet", "v1.25.2"),
+			Entry("with an invalid cluster version", "v1.25.2", "v1"),
+		)
+	})
+})
@@ -0,0 +1,1514 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/cluster-control-plane-machine-set-operator/pkg/machineproviders/providers/openshift/machine/v1beta1/providerconfig"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var (
+	// errNilProviderSpec is returned when the provider spec passed is nil.
+	errNilProviderSpec = errors.New("provider spec is nil")
+
+	// errNoBootDisk is returned when the provider spec does not have a boot disk.
+	errNoBootDisk = errors.New("provider spec has no boot disk")
+
+	// errNoRootDiskSize is returned when the provider spec does not set the size of the root disk.
+	errNoRootDiskSize = errors.New("provider spec does not set the root disk size")
+
+	// errInvalidGCPKMSKey is returned when a GCP KMS key is not the full resource name of a key.
+	errInvalidGCPKMSKey = errors.New("GCP KMS key must be of the form projects/<project>/locations/<location>/keyRings/<key ring>/cryptoKeys/<key>")
+
+	// errNoNetworkInterface is returned when the provider spec does not have a network interface.
+	errNoNetworkInterface = errors.New("provider spec has no network interface")
+
+	// ErrNoProviderSpecMetadata is returned when the provider spec does not carry a metadata block.
+	ErrNoProviderSpecMetadata = errors.New("provider spec does not carry a metadata block")
+
+	// ErrAcceleratorsNotSupported is returned when the platform of the provider spec does not support attaching
+	// accelerators to machines.
+	ErrAcceleratorsNotSupported = errors.New("accelerators are not supported on this platform")
+
+	// ErrCloudIdentityNotSupported is returned when the platform of the provider spec does not support attaching
+	// a cloud identity to machines.
+	ErrCloudIdentityNotSupported = errors.New("cloud identities are not supported on this platform")
+)
+
+// providerConfigFromRawExtension parses the raw provider spec into a provider config.
+// The raw provider spec may hold either the raw bytes, or an object, as set by setProviderSpecValue.
+func providerConfigFromRawExtension(rawProviderSpec *runtime.RawExtension) (providerconfig.ProviderConfig, error) {
+	if rawProviderSpec == nil {
+		return nil, errNilProviderSpec
+	}
+
+	raw := rawProviderSpec.DeepCopy()
+
+	if raw.Raw == nil && raw.Object != nil {
+		rawBytes, err := json.Marshal(raw.Object)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling provider spec object: %w", err)
+		}
+
+		raw.Raw = rawBytes
+	}
+
+	providerConfig, err := providerconfig.NewProviderConfigFromMachineSpec(machinev1beta1.MachineSpec{
+		ProviderSpec: machinev1beta1.ProviderSpec{
+			Value: raw,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider config: %w", err)
+	}
+
+	return providerConfig, nil
+}
+
+// GetProviderSpecLoadBalancers returns the names of the load balancers that the provider spec
+// attaches machines to.
+// On AWS these are the load balancer names, on Azure the internal and public load balancers,
+// and on GCP the target pools.
+func GetProviderSpecLoadBalancers(rawProviderSpec *runtime.RawExtension) ([]string, error) {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	loadBalancers := []string{}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		for _, lb := range providerConfig.AWS().Config().LoadBalancers {
+			loadBalancers = append(loadBalancers, lb.Name)
+		}
+	case configv1.AzurePlatformType:
+		cfg := providerConfig.Azure().Config()
+
+		for _, lb := range []string{cfg.InternalLoadBalancer, cfg.PublicLoadBalancer} {
+			if lb != "" {
+				loadBalancers = append(loadBalancers, lb)
+			}
+		}
+	case configv1.GCPPlatformType:
+		loadBalancers = append(loadBalancers, providerConfig.GCP().Config().TargetPools...)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	return loadBalancers, nil
+}
+
+// AddProviderSpecLoadBalancer adds the named load balancer to the provider spec.
+// On AWS the load balancer is added as an additional network load balancer,
+// and on GCP as an additional target pool.
+// On Azure, where machines are attached to a single internal load balancer,
+// the internal load balancer is replaced.
+func AddProviderSpecLoadBalancer(rawProviderSpec *runtime.RawExtension, name string) error {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		cfg := providerConfig.AWS().Config()
+		cfg.LoadBalancers = append(cfg.LoadBalancers, machinev1beta1.LoadBalancerReference{
+			Name: name,
+			Type: machinev1beta1.NetworkLoadBalancerType,
+		})
+		value = cfg
+	case configv1.AzurePlatformType:
+		cfg := providerConfig.Azure().Config()
+		cfg.InternalLoadBalancer = name
+		value = cfg
+	case configv1.GCPPlatformType:
+		cfg := providerConfig.GCP().Config()
+		cfg.TargetPools = append(cfg.TargetPools, name)
+		value = cfg
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	if err := setProviderSpecValue(rawProviderSpec, value); err != nil {
+		return fmt.Errorf("failed to set provider spec value: %w", err)
+	}
+
+	return nil
+}
+
+// SetProviderSpecUserDataSecret sets the name of the secret holding the user data that machines are booted with.
+func SetProviderSpecUserDataSecret(rawProviderSpec *runtime.RawExtension, name string) error {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		cfg := providerConfig.AWS().Config()
+		cfg.UserDataSecret = &corev1.LocalObjectReference{Name: name}
+		value = cfg
+	case configv1.AzurePlatformType:
+		cfg := providerConfig.Azure().Config()
+
+		namespace := MachineAPINamespace
+		if cfg.UserDataSecret != nil && cfg.UserDataSecret.Namespace != "" {
+			namespace = cfg.UserDataSecret.Namespace
+		}
+
+		cfg.UserDataSecret = &corev1.SecretReference{Name: name, Namespace: namespace}
+		value = cfg
+	case configv1.GCPPlatformType:
+		cfg := providerConfig.GCP().Config()
+		cfg.UserDataSecret = &corev1.LocalObjectReference{Name: name}
+		value = cfg
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	if err := setProviderSpecValue(rawProviderSpec, value); err != nil {
+		return fmt.Errorf("failed to set provider spec value: %w", err)
+	}
+
+	return nil
+}
+
+// GetProviderSpecUserDataSecret returns the name of the secret holding the user data that machines are booted with.
+func GetProviderSpecUserDataSecret(rawProviderSpec *runtime.RawExtension) (string, error) {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return "", err
+	}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		if secret := providerConfig.AWS().Config().UserDataSecret; secret != nil {
+			return secret.Name, nil
+		}
+	case configv1.AzurePlatformType:
+		if secret := providerConfig.Azure().Config().UserDataSecret; secret != nil {
+			return secret.Name, nil
+		}
+	case configv1.GCPPlatformType:
+		if secret := providerConfig.GCP().Config().UserDataSecret; secret != nil {
+			return secret.Name, nil
+		}
+	default:
+		return "", fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	return "", nil
+}
+
+// GetProviderSpecCredentialsSecret returns the name of the secret holding the cloud credentials used by machines.
+func GetProviderSpecCredentialsSecret(rawProviderSpec *runtime.RawExtension) (string, error) {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return "", err
+	}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		if secret := providerConfig.AWS().Config().CredentialsSecret; secret != nil {
+			return secret.Name, nil
+		}
+	case configv1.AzurePlatformType:
+		if secret := providerConfig.Azure().Config().CredentialsSecret; secret != nil {
+			return secret.Name, nil
+		}
+	case configv1.GCPPlatformType:
+		if secret := providerConfig.GCP().Config().CredentialsSecret; secret != nil {
+			return secret.Name, nil
+		}
+	default:
+		return "", fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	return "", nil
+}
+
+// ClearProviderSpecCredentialsSecret removes the reference to the cloud credentials secret from the provider spec,
+// leaving it to be defaulted by the Machine API when machines are created.
+func ClearProviderSpecCredentialsSecret(rawProviderSpec *runtime.RawExtension) error {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		cfg := providerConfig.AWS().Config()
+		cfg.CredentialsSecret = nil
+		value = cfg
+	case configv1.AzurePlatformType:
+		cfg := providerConfig.Azure().Config()
+		cfg.CredentialsSecret = nil
+		value = cfg
+	case configv1.GCPPlatformType:
+		cfg := providerConfig.GCP().Config()
+		cfg.CredentialsSecret = nil
+		value = cfg
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	if err := setProviderSpecValue(rawProviderSpec, value); err != nil {
+		return fmt.Errorf("failed to set provider spec value: %w", err)
+	}
+
+	return nil
+}
+
+// awsProviderConfigFromRawExtension parses the raw provider spec into an AWS provider config.
+// It returns an error if the provider spec is not an AWS provider spec.
+func awsProviderConfigFromRawExtension(rawProviderSpec *runtime.RawExtension) (machinev1beta1.AWSMachineProviderConfig, error) {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return machinev1beta1.AWSMachineProviderConfig{}, err
+	}
+
+	if providerConfig.Type() != configv1.AWSPlatformType {
+		return machinev1beta1.AWSMachineProviderConfig{}, fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	return providerConfig.AWS().Config(), nil
+}
+
+// SetAWSProviderSpecTenancy sets the placement tenancy of the AWS provider spec.
+func SetAWSProviderSpecTenancy(rawProviderSpec *runtime.RawExtension, tenancy string) error {
+	cfg, err := awsProviderConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	cfg.Placement.Tenancy = machinev1beta1.InstanceTenancy(tenancy)
+
+	if err := setProviderSpecValue(rawProviderSpec, cfg); err != nil {
+		return fmt.Errorf("failed to set provider spec value: %w", err)
+	}
+
+	return nil
+}
+
+// GetAWSProviderSpecTenancy returns the placement tenancy of the AWS provider spec.
+func GetAWSProviderSpecTenancy(rawProviderSpec *runtime.RawExtension) (string, error) {
+	cfg, err := awsProviderConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return "", err
+	}
+
+	return string(cfg.Placement.Tenancy), nil
+}
+
+// rawProviderSpecFields returns the top level fields of the provider spec, including any fields
+// not known to the vendored provider config types.
+func rawProviderSpecFields(rawProviderSpec *runtime.RawExtension) (map[string]interface{}, error) {
+	if rawProviderSpec == nil {
+		return nil, errNilProviderSpec
+	}
+
+	raw := rawProviderSpec.Raw
+
+	if raw == nil && rawProviderSpec.Object != nil {
+		rawBytes, err := json.Marshal(rawProviderSpec.Object)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling provider spec object: %w", err)
+		}
+
+		raw = rawBytes
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("error unmarshalling provider spec: %w", err)
+	}
+
+	return fields, nil
+}
+
+// setRawProviderSpecFields sets the raw provider spec to the given fields.
+func setRawProviderSpecFields(rawProviderSpec *runtime.RawExtension, fields map[string]interface{}) error {
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("error marshalling provider spec: %w", err)
+	}
+
+	rawProviderSpec.Raw = raw
+	rawProviderSpec.Object = nil
+
+	return nil
+}
+
+// compatibleProviderSpecAPIVersions are the provider spec API versions, for each platform, that the Machine API
+// provider and the operator decode interchangeably. The first API version is the current API version, the others
+// are legacy API versions that remain accepted for clusters installed by earlier releases.
+//
+//nolint:gochecknoglobals
+var compatibleProviderSpecAPIVersions = map[configv1.PlatformType][]string{
+	configv1.AWSPlatformType:   {"machine.openshift.io/v1beta1", "awsproviderconfig.openshift.io/v1beta1"},
+	configv1.AzurePlatformType: {"machine.openshift.io/v1beta1", "azureproviderconfig.openshift.io/v1beta1"},
+	configv1.GCPPlatformType:   {"machine.openshift.io/v1beta1", "gcpprovider.openshift.io/v1beta1"},
+}
+
+// CompatibleProviderSpecAPIVersions returns the provider spec API versions supported for the platform, starting with
+// the current API version. Platforms with a single provider spec API version return no API versions.
+func CompatibleProviderSpecAPIVersions(platform configv1.PlatformType) []string {
+	return append([]string{}, compatibleProviderSpecAPIVersions[platform]...)
+}
+
+// ProviderSpecAPIVersion returns the API version of the provider spec.
+func ProviderSpecAPIVersion(rawProviderSpec *runtime.RawExtension) (string, error) {
+	fields, err := rawProviderSpecFields(rawProviderSpec)
+	if err != nil {
+		return "", err
+	}
+
+	apiVersion, _ := fields["apiVersion"].(string)
+
+	return apiVersion, nil
+}
+
+// SetProviderSpecAPIVersion sets the API version of the provider spec, leaving the rest of the provider spec
+// untouched.
+func SetProviderSpecAPIVersion(rawProviderSpec *runtime.RawExtension, apiVersion string) error {
+	fields, err := rawProviderSpecFields(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	fields["apiVersion"] = apiVersion
+
+	return setRawProviderSpecFields(rawProviderSpec, fields)
+}
+
+// ProviderSpecMetadataName returns the name within the metadata block embedded in the provider spec.
+// An error is returned if the provider spec does not carry a metadata block.
+func ProviderSpecMetadataName(rawProviderSpec *runtime.RawExtension) (string, error) {
+	fields, err := rawProviderSpecFields(rawProviderSpec)
+	if err != nil {
+		return "", err
+	}
+
+	metadata, ok := fields["metadata"].(map[string]interface{})
+	if !ok {
+		return "", ErrNoProviderSpecMetadata
+	}
+
+	name, _ := metadata["name"].(string)
+
+	return name, nil
+}
+
+// SetProviderSpecMetadataName sets the name within the metadata block embedded in the provider spec,
+// leaving the rest of the provider spec untouched. An empty name removes the name from the metadata block.
+// An error is returned if the provider spec does not carry a metadata block.
+func SetProviderSpecMetadataName(rawProviderSpec *runtime.RawExtension, name string) error {
+	fields, err := rawProviderSpecFields(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	metadata, ok := fields["metadata"].(map[string]interface{})
+	if !ok {
+		return ErrNoProviderSpecMetadata
+	}
+
+	if name == "" {
+		delete(metadata, "name")
+	} else {
+		metadata["name"] = name
+	}
+
+	return setRawProviderSpecFields(rawProviderSpec, fields)
+}
+
+// SetProviderSpecMetadataCreationTimestamp sets the creation timestamp within the metadata block embedded in the
+// provider spec, leaving the rest of the provider spec untouched. The timestamp is stored in RFC3339 format, in UTC.
+// An error is returned if the provider spec does not carry a metadata block.
+func SetProviderSpecMetadataCreationTimestamp(rawProviderSpec *runtime.RawExtension, timestamp time.Time) error {
+	fields, err := rawProviderSpecFields(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	metadata, ok := fields["metadata"].(map[string]interface{})
+	if !ok {
+		return ErrNoProviderSpecMetadata
+	}
+
+	metadata["creationTimestamp"] = timestamp.UTC().Format(time.RFC3339)
+
+	return setRawProviderSpecFields(rawProviderSpec, fields)
+}
+
+// SetProviderSpecInstanceType sets the instance type of the provider spec.
+// On AWS this is the instance type, on Azure the VM size, and on GCP the machine type.
+func SetProviderSpecInstanceType(rawProviderSpec *runtime.RawExtension, instanceType string) error {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		cfg := providerConfig.AWS().Config()
+		cfg.InstanceType = instanceType
+		value = cfg
+	case configv1.AzurePlatformType:
+		cfg := providerConfig.Azure().Config()
+		cfg.VMSize = instanceType
+		value = cfg
+	case configv1.GCPPlatformType:
+		cfg := providerConfig.GCP().Config()
+		cfg.MachineType = instanceType
+		value = cfg
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	if err := setProviderSpecValue(rawProviderSpec, value); err != nil {
+		return fmt.Errorf("failed to set provider spec value: %w", err)
+	}
+
+	return nil
+}
+
+// ProviderSpecInstanceType returns the instance type of the provider spec.
+// On AWS this is the instance type, on Azure the VM size, and on GCP the machine type.
+// GCP machine types may be given as a partial URL, so only the final path segment is returned.
+func ProviderSpecInstanceType(rawProviderSpec *runtime.RawExtension) (string, error) {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return "", err
+	}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		return providerConfig.AWS().Config().InstanceType, nil
+	case configv1.AzurePlatformType:
+		return providerConfig.Azure().Config().VMSize, nil
+	case configv1.GCPPlatformType:
+		return path.Base(providerConfig.GCP().Config().MachineType), nil
+	default:
+		return "", fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+}
+
+// ProviderSpecImage returns the boot image of the provider spec.
+// On AWS this is the AMI ID, on Azure the image resource ID, and on GCP the image of the boot disk.
+// An empty string is returned when the provider spec does not reference the image directly, for example
+// when AWS AMIs are selected by filters, or when Azure images are referenced from the marketplace.
+func ProviderSpecImage(rawProviderSpec *runtime.RawExtension) (string, error) {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return "", err
+	}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		if id := providerConfig.AWS().Config().AMI.ID; id != nil {
+			return *id, nil
+		}
+	case configv1.AzurePlatformType:
+		return providerConfig.Azure().Config().Image.ResourceID, nil
+	case configv1.GCPPlatformType:
+		for _, disk := range providerConfig.GCP().Config().Disks {
+			if disk != nil && disk.Boot {
+				return disk.Image, nil
+			}
+		}
+
+		return "", errNoBootDisk
+	default:
+		return "", fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	return "", nil
+}
+
+// UpdateProviderSpecImage sets the boot image of the provider spec.
+// On AWS this sets the AMI ID, on Azure the image resource ID, and on GCP the image of the boot disk.
+// Any other means of selecting the image, such as AWS AMI filters or Azure marketplace images, are removed.
+func UpdateProviderSpecImage(rawProviderSpec *runtime.RawExtension, image string) error {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		cfg := providerConfig.AWS().Config()
+		cfg.AMI = machinev1beta1.AWSResourceReference{ID: &image}
+		value = cfg
+	case configv1.AzurePlatformType:
+		cfg := providerConfig.Azure().Config()
+		cfg.Image = machinev1beta1.Image{ResourceID: image}
+		value = cfg
+	case configv1.GCPPlatformType:
+		cfg := providerConfig.GCP().Config()
+
+		bootDisk := false
+
+		for _, disk := range cfg.Disks {
+			if disk != nil && disk.Boot {
+				disk.Image = image
+				bootDisk = true
+			}
+		}
+
+		if !bootDisk {
+			return errNoBootDisk
+		}
+
+		value = cfg
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	if err := setProviderSpecValue(rawProviderSpec, value); err != nil {
+		return fmt.Errorf("failed to set provider spec value: %w", err)
+	}
+
+	return nil
+}
+
+// SetAWSProviderSpecAvailabilityZone sets the placement availability zone of the AWS provider spec.
+func SetAWSProviderSpecAvailabilityZone(rawProviderSpec *runtime.RawExtension, zone string) error {
+	cfg, err := awsProviderConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	cfg.Placement.AvailabilityZone = zone
+
+	if err := setProviderSpecValue(rawProviderSpec, cfg); err != nil {
+		return fmt.Errorf("failed to set provider spec value: %w", err)
+	}
+
+	return nil
+}
+
+// GetAWSProviderSpecAvailabilityZone returns the placement availability zone of the AWS provider spec.
+func GetAWSProviderSpecAvailabilityZone(rawProviderSpec *runtime.RawExtension) (string, error) {
+	cfg, err := awsProviderConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return "", err
+	}
+
+	return cfg.Placement.AvailabilityZone, nil
+}
+
+// GetAWSProviderSpecTags returns the tags applied to instances by the AWS provider spec.
+func GetAWSProviderSpecTags(rawProviderSpec *runtime.RawExtension) ([]machinev1beta1.TagSpecification, error) {
+	cfg, err := awsProviderConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.Tags, nil
+}
+
+// ReverseAWSProviderSpecTags reverses the order of the tags within the AWS provider spec.
+// The set of tags applied to instances is unchanged.
+func ReverseAWSProviderSpecTags(rawProviderSpec *runtime.RawExtension) error {
+	cfg, err := awsProviderConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	for i, j := 0, len(cfg.Tags)-1; i < j; i, j = i+1, j-1 {
+		cfg.Tags[i], cfg.Tags[j] = cfg.Tags[j], cfg.Tags[i]
+	}
+
+	if err := setProviderSpecValue(rawProviderSpec, cfg); err != nil {
+		return fmt.Errorf("failed to set provider spec value: %w", err)
+	}
+
+	return nil
+}
+
+// AddAWSProviderSpecTags appends the given tags to the tags applied to instances by the AWS provider spec.
+func AddAWSProviderSpecTags(rawProviderSpec *runtime.RawExtension, tags ...machinev1beta1.TagSpecification) error {
+	cfg, err := awsProviderConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	cfg.Tags = append(cfg.Tags, tags...)
+
+	if err := setProviderSpecValue(rawProviderSpec, cfg); err != nil {
+		return fmt.Errorf("failed to set provider spec value: %w", err)
+	}
+
+	return nil
+}
+
+// ProviderSpecHasClusterIDTag returns whether the provider spec tags instances as belonging to the cluster with the
+// given infrastructure name.
+// On AWS this is the kubernetes.io/cluster/<id> tag with the value owned, used by the cloud provider to discover
+// cluster resources. On GCP this is the <id>-master network tag, used by the firewall rules for the control plane.
+// On Azure the cluster tag is added by the Machine API provider when the instance is created, rather than being set
+// within the provider spec, so an unsupported platform error is returned.
+func ProviderSpecHasClusterIDTag(rawProviderSpec *runtime.RawExtension, clusterID string) (bool, error) {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return false, err
+	}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		for _, tag := range providerConfig.AWS().Config().Tags {
+			if tag.Name == fmt.Sprintf("kubernetes.io/cluster/%s", clusterID) && tag.Value == "owned" {
+				return true, nil
+			}
+		}
+	case configv1.GCPPlatformType:
+		for _, tag := range providerConfig.GCP().Config().Tags {
+			if tag == fmt.Sprintf("%s-master", clusterID) {
+				return true, nil
+			}
+		}
+	default:
+		return false, fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	return false, nil
+}
+
+// gcpProviderConfigFromRawExtension parses the raw provider spec into a GCP provider config.
+// It returns an error if the provider spec is not a GCP provider spec.
+func gcpProviderConfigFromRawExtension(rawProviderSpec *runtime.RawExtension) (machinev1beta1.GCPMachineProviderSpec, error) {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return machinev1beta1.GCPMachineProviderSpec{}, err
+	}
+
+	if providerConfig.Type() != configv1.GCPPlatformType {
+		return machinev1beta1.GCPMachineProviderSpec{}, fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	return providerConfig.GCP().Config(), nil
+}
+
+// UpdateGCPProviderSpecServiceAccount replaces the service accounts attached to machines by the GCP provider spec
+// with the service account with the given email.
+// The scopes are a comma separated list of the scopes to assign to the service account.
+func UpdateGCPProviderSpecServiceAccount(rawProviderSpec *runtime.RawExtension, email, scopes string) error {
+	cfg, err := gcpProviderConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	serviceAccount := machinev1beta1.GCPServiceAccount{
+		Email:  email,
+		Scopes: []string{},
+	}
+
+	for _, scope := range strings.Split(scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			serviceAccount.Scopes = append(serviceAccount.Scopes, scope)
+		}
+	}
+
+	cfg.ServiceAccounts = []machinev1beta1.GCPServiceAccount{serviceAccount}
+
+	if err := setProviderSpecValue(rawProviderSpec, cfg); err != nil {
+		return fmt.Errorf("failed to set provider spec value: %w", err)
+	}
+
+	return nil
+}
+
+// GetGCPProviderSpecServiceAccounts returns the service accounts attached to machines by the GCP provider spec.
+func GetGCPProviderSpecServiceAccounts(rawProviderSpec *runtime.RawExtension) ([]machinev1beta1.GCPServiceAccount, error) {
+	cfg, err := gcpProviderConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.ServiceAccounts, nil
+}
+
+// ProviderSpecAccelerators returns the accelerators attached to machines by the provider spec.
+// On GCP these are the GPUs, each formatted as type:count, for example nvidia-tesla-t4:1.
+// Other platforms select accelerators through the instance type, so ErrAcceleratorsNotSupported is returned.
+func ProviderSpecAccelerators(rawProviderSpec *runtime.RawExtension) ([]string, error) {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	if providerConfig.Type() != configv1.GCPPlatformType {
+		return nil, fmt.Errorf("%w: %s", ErrAcceleratorsNotSupported, providerConfig.Type())
+	}
+
+	accelerators := []string{}
+
+	for _, gpu := range providerConfig.GCP().Config().GPUs {
+		accelerators = append(accelerators, fmt.Sprintf("%s:%d", gpu.Type, gpu.Count))
+	}
+
+	return accelerators, nil
+}
+
+// ProviderSpecCloudIdentity returns the cloud identity attached to machines by the provider spec.
+// On AWS this is the ID, or failing that the ARN, of the IAM instance profile, on Azure the managed identity and on
+// GCP the email of the first service account. An empty identity is returned when the provider spec attaches none.
+// Other platforms have no cloud identity, so ErrCloudIdentityNotSupported is returned.
+func ProviderSpecCloudIdentity(rawProviderSpec *runtime.RawExtension) (string, error) {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return "", err
+	}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		profile := providerConfig.AWS().Config().IAMInstanceProfile
+		if profile == nil {
+			return "", nil
+		}
+
+		if profile.ID != nil && *profile.ID != "" {
+			return *profile.ID, nil
+		}
+
+		if profile.ARN != nil {
+			return *profile.ARN, nil
+		}
+
+		return "", nil
+	case configv1.AzurePlatformType:
+		return providerConfig.Azure().Config().ManagedIdentity, nil
+	case configv1.GCPPlatformType:
+		serviceAccounts := providerConfig.GCP().Config().ServiceAccounts
+		if len(serviceAccounts) == 0 {
+			return "", nil
+		}
+
+		return serviceAccounts[0].Email, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrCloudIdentityNotSupported, providerConfig.Type())
+	}
+}
+
+// azureProviderConfigFromRawExtension parses the raw provider spec into an Azure provider config.
+// It returns an error if the provider spec is not an Azure provider spec.
+func azureProviderConfigFromRawExtension(rawProviderSpec *runtime.RawExtension) (machinev1beta1.AzureMachineProviderSpec, error) {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return machinev1beta1.AzureMachineProviderSpec{}, err
+	}
+
+	if providerConfig.Type() != configv1.AzurePlatformType {
+		return machinev1beta1.AzureMachineProviderSpec{}, fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	return providerConfig.Azure().Config(), nil
+}
+
+// azurePlatformFaultDomainField is the field of the Azure provider spec holding the fault domain, within the
+// availability set, in which the virtual machine is placed. This field is not known to the vendored provider
+// config types, so it is handled as a raw field.
+const azurePlatformFaultDomainField = "platformFaultDomain"
+
+// ErrNoAzureFaultDomain is returned when the Azure provider spec does not report a fault domain.
+// Checks on the fault domain spread should be skipped when the fault domain is not reported.
+var ErrNoAzureFaultDomain = errors.New("azure provider spec does not report a fault domain")
+
+// GetAzureProviderSpecFaultDomain returns the fault domain, within the availability set, in which the Azure virtual
+// machine is placed.
+func GetAzureProviderSpecFaultDomain(rawProviderSpec *runtime.RawExtension) (int, error) {
+	if _, err := azureProviderConfigFromRawExtension(rawProviderSpec); err != nil {
+		return 0, err
+	}
+
+	fields, err := rawProviderSpecFields(rawProviderSpec)
+	if err != nil {
+		return 0, err
+	}
+
+	// JSON numbers are unmarshalled as float64 values.
+	faultDomain, ok := fields[azurePlatformFaultDomainField].(float64)
+	if !ok {
+		return 0, ErrNoAzureFaultDomain
+	}
+
+	return int(faultDomain), nil
+}
+
+// GetAzureProviderSpecAvailabilitySet returns the availability set in which the Azure virtual machine is created.
+func GetAzureProviderSpecAvailabilitySet(rawProviderSpec *runtime.RawExtension) (string, error) {
+	cfg, err := azureProviderConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return "", err
+	}
+
+	return cfg.AvailabilitySet, nil
+}
+
+// ProviderSpecEncryptionSettings returns whether the root disk of the provider spec is encrypted, and the
+// customer managed key used to encrypt it, if any.
+// On AWS this is the EBS encryption and KMS key of the root block device, on Azure the disk encryption set of
+// the OS disk, and on GCP the KMS key of the boot disk.
+// Azure encrypts managed disks with platform managed keys regardless, so the OS disk is only reported as
+// encrypted when a disk encryption set, or encryption at host, is configured.
+func ProviderSpecEncryptionSettings(rawProviderSpec *runtime.RawExtension) (bool, string, error) {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return false, "", err
+	}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		return awsRootBlockDeviceEncryption(providerConfig.AWS().Config())
+	case configv1.AzurePlatformType:
+		cfg := providerConfig.Azure().Config()
+
+		if des := cfg.OSDisk.ManagedDisk.DiskEncryptionSet; des != nil && des.ID != "" {
+			return true, des.ID, nil
+		}
+
+		if cfg.SecurityProfile != nil && cfg.SecurityProfile.EncryptionAtHost != nil {
+			return *cfg.SecurityProfile.EncryptionAtHost, "", nil
+		}
+
+		return false, "", nil
+	case configv1.GCPPlatformType:
+		return gcpBootDiskEncryption(providerConfig.GCP().Config())
+	default:
+		return false, "", fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+}
+
+// awsRootBlockDevice returns the EBS volume of the root block device, or nil when there is none.
+// The root block device is the block device without a device name.
+func awsRootBlockDevice(cfg machinev1beta1.AWSMachineProviderConfig) *machinev1beta1.EBSBlockDeviceSpec {
+	for _, blockDevice := range cfg.BlockDevices {
+		if blockDevice.DeviceName == nil && blockDevice.EBS != nil {
+			return blockDevice.EBS
+		}
+	}
+
+	return nil
+}
+
+// awsRootBlockDeviceEncryption returns the encryption settings of the root block device.
+func awsRootBlockDeviceEncryption(cfg machinev1beta1.AWSMachineProviderConfig) (bool, string, error) {
+	ebs := awsRootBlockDevice(cfg)
+	if ebs == nil {
+		return false, "", nil
+	}
+
+	kmsKey := ""
+
+	switch {
+	case ebs.KMSKey.ID != nil:
+		kmsKey = *ebs.KMSKey.ID
+	case ebs.KMSKey.ARN != nil:
+		kmsKey = *ebs.KMSKey.ARN
+	}
+
+	encrypted := ebs.Encrypted != nil && *ebs.Encrypted
+
+	return encrypted, kmsKey, nil
+}
+
+// gcpBootDiskEncryption returns the encryption settings of the boot disk.
+// The KMS key is returned as the full resource name of the key.
+func gcpBootDiskEncryption(cfg machinev1beta1.GCPMachineProviderSpec) (bool, string, error) {
+	for _, disk := range cfg.Disks {
+		if disk == nil || !disk.Boot {
+			continue
+		}
+
+		if disk.EncryptionKey == nil || disk.EncryptionKey.KMSKey == nil {
+			return false, "", nil
+		}
+
+		kmsKey := disk.EncryptionKey.KMSKey
+
+		projectID := kmsKey.ProjectID
+		if projectID == "" {
+			// The key ring defaults to the project of the VM.
+			projectID = cfg.ProjectID
+		}
+
+		return true, fmt.Sprintf("projects/%s/locations/%s/keyRings/%s/cryptoKeys/%s", projectID, kmsKey.Location, kmsKey.KeyRing, kmsKey.Name), nil
+	}
+
+	return false, "", errNoBootDisk
+}
+
+// UpdateProviderSpecKMSKey sets the key used to encrypt the root disk of the provider spec, enabling encryption of
+// the root disk if it is not already enabled.
+// On AWS this is the ARN of the KMS key of the root block device, on Azure the ID of the disk encryption set of the
+// OS disk, and on GCP the full resource name of the KMS key of the boot disk, as returned by
+// ProviderSpecEncryptionSettings.
+func UpdateProviderSpecKMSKey(rawProviderSpec *runtime.RawExtension, keyARN string) error {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		cfg := providerConfig.AWS().Config()
+
+		ebs := awsRootBlockDevice(cfg)
+		if ebs == nil {
+			return errNoBootDisk
+		}
+
+		encrypted := true
+		ebs.Encrypted = &encrypted
+		ebs.KMSKey = machinev1beta1.AWSResourceReference{ARN: &keyARN}
+
+		value = cfg
+	case configv1.AzurePlatformType:
+		cfg := providerConfig.Azure().Config()
+		cfg.OSDisk.ManagedDisk.DiskEncryptionSet = &machinev1beta1.DiskEncryptionSetParameters{ID: keyARN}
+		value = cfg
+	case configv1.GCPPlatformType:
+		cfg := providerConfig.GCP().Config()
+
+		kmsKey, err := gcpKMSKeyReference(keyARN)
+		if err != nil {
+			return err
+		}
+
+		bootDisk := false
+
+		for _, disk := range cfg.Disks {
+			if disk != nil && disk.Boot {
+				disk.EncryptionKey = &machinev1beta1.GCPEncryptionKeyReference{KMSKey: kmsKey}
+				bootDisk = true
+			}
+		}
+
+		if !bootDisk {
+			return errNoBootDisk
+		}
+
+		value = cfg
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	if err := setProviderSpecValue(rawProviderSpec, value); err != nil {
+		return fmt.Errorf("failed to set provider spec value: %w", err)
+	}
+
+	return nil
+}
+
+// gcpKMSKeyReference parses the full resource name of a GCP KMS key into a key reference.
+func gcpKMSKeyReference(name string) (*machinev1beta1.GCPKMSKeyReference, error) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 8 || parts[0] != "projects" || parts[2] != "locations" || parts[4] != "keyRings" || parts[6] != "cryptoKeys" {
+		return nil, fmt.Errorf("%w: %s", errInvalidGCPKMSKey, name)
+	}
+
+	return &machinev1beta1.GCPKMSKeyReference{
+		ProjectID: parts[1],
+		Location:  parts[3],
+		KeyRing:   parts[5],
+		Name:      parts[7],
+	}, nil
+}
+
+// rootDiskSizeIncrementGB is the amount by which IncreaseProviderSpecRootDiskSize grows the root disk.
+const rootDiskSizeIncrementGB = 20
+
+// ProviderSpecRootDiskSize returns the size, in GB, of the root disk of the provider spec.
+// On AWS this is the volume size of the root block device, on Azure the size of the OS disk, and on GCP the
+// size of the boot disk.
+func ProviderSpecRootDiskSize(rawProviderSpec *runtime.RawExtension) (int64, error) {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return 0, err
+	}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		ebs := awsRootBlockDevice(providerConfig.AWS().Config())
+		if ebs == nil {
+			return 0, errNoBootDisk
+		}
+
+		if ebs.VolumeSize == nil {
+			return 0, errNoRootDiskSize
+		}
+
+		return *ebs.VolumeSize, nil
+	case configv1.AzurePlatformType:
+		return int64(providerConfig.Azure().Config().OSDisk.DiskSizeGB), nil
+	case configv1.GCPPlatformType:
+		for _, disk := range providerConfig.GCP().Config().Disks {
+			if disk != nil && disk.Boot {
+				return disk.SizeGB, nil
+			}
+		}
+
+		return 0, errNoBootDisk
+	default:
+		return 0, fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+}
+
+// IncreaseProviderSpecRootDiskSize grows the root disk of the provider spec by 20GB.
+// On AWS this increases the volume size of the root block device, on Azure the size of the OS disk, and on
+// GCP the size of the boot disk.
+// An error is returned when the provider spec does not already set the size of the root disk.
+func IncreaseProviderSpecRootDiskSize(rawProviderSpec *runtime.RawExtension) error {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		cfg := providerConfig.AWS().Config()
+
+		ebs := awsRootBlockDevice(cfg)
+		if ebs == nil {
+			return errNoBootDisk
+		}
+
+		if ebs.VolumeSize == nil {
+			return errNoRootDiskSize
+		}
+
+		*ebs.VolumeSize += rootDiskSizeIncrementGB
+		value = cfg
+	case configv1.AzurePlatformType:
+		cfg := providerConfig.Azure().Config()
+
+		if cfg.OSDisk.DiskSizeGB == 0 {
+			return errNoRootDiskSize
+		}
+
+		cfg.OSDisk.DiskSizeGB += rootDiskSizeIncrementGB
+		value = cfg
+	case configv1.GCPPlatformType:
+		cfg := providerConfig.GCP().Config()
+
+		bootDisk := false
+
+		for _, disk := range cfg.Disks {
+			if disk != nil && disk.Boot {
+				disk.SizeGB += rootDiskSizeIncrementGB
+				bootDisk = true
+			}
+		}
+
+		if !bootDisk {
+			return errNoBootDisk
+		}
+
+		value = cfg
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	if err := setProviderSpecValue(rawProviderSpec, value); err != nil {
+		return fmt.Errorf("failed to set provider spec value: %w", err)
+	}
+
+	return nil
+}
+
+// provisionedIOPSVolumeTypeIOPS is the IOPS set by UpdateProviderSpecDiskType when switching an AWS root volume to a
+// provisioned IOPS volume type that does not already specify its IOPS, as AWS requires IOPS for these volume types.
+const provisionedIOPSVolumeTypeIOPS = 3000
+
+// ProviderSpecDiskType returns the type of the root disk of the provider spec.
+// On AWS this is the volume type of the root block device, on Azure the storage account type of the OS disk, and on
+// GCP the type of the boot disk.
+func ProviderSpecDiskType(rawProviderSpec *runtime.RawExtension) (string, error) {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return "", err
+	}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		ebs := awsRootBlockDevice(providerConfig.AWS().Config())
+		if ebs == nil {
+			return "", errNoBootDisk
+		}
+
+		if ebs.VolumeType == nil {
+			return "", nil
+		}
+
+		return *ebs.VolumeType, nil
+	case configv1.AzurePlatformType:
+		return providerConfig.Azure().Config().OSDisk.ManagedDisk.StorageAccountType, nil
+	case configv1.GCPPlatformType:
+		for _, disk := range providerConfig.GCP().Config().Disks {
+			if disk != nil && disk.Boot {
+				return disk.Type, nil
+			}
+		}
+
+		return "", errNoBootDisk
+	default:
+		return "", fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+}
+
+// UpdateProviderSpecDiskType sets the type of the root disk of the provider spec, for example gp3 or io2 on AWS,
+// Premium_LRS on Azure or pd-ssd on GCP.
+// On AWS, when switching to a provisioned IOPS volume type (io1 or io2), the IOPS are set if not already specified.
+func UpdateProviderSpecDiskType(rawProviderSpec *runtime.RawExtension, diskType string) error {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		cfg := providerConfig.AWS().Config()
+
+		ebs := awsRootBlockDevice(cfg)
+		if ebs == nil {
+			return errNoBootDisk
+		}
+
+		ebs.VolumeType = &diskType
+
+		if (diskType == "io1" || diskType == "io2") && (ebs.Iops == nil || *ebs.Iops == 0) {
+			iops := int64(provisionedIOPSVolumeTypeIOPS)
+			ebs.Iops = &iops
+		}
+
+		value = cfg
+	case configv1.AzurePlatformType:
+		cfg := providerConfig.Azure().Config()
+		cfg.OSDisk.ManagedDisk.StorageAccountType = diskType
+		value = cfg
+	case configv1.GCPPlatformType:
+		cfg := providerConfig.GCP().Config()
+
+		bootDisk := false
+
+		for _, disk := range cfg.Disks {
+			if disk != nil && disk.Boot {
+				disk.Type = diskType
+				bootDisk = true
+			}
+		}
+
+		if !bootDisk {
+			return errNoBootDisk
+		}
+
+		value = cfg
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	if err := setProviderSpecValue(rawProviderSpec, value); err != nil {
+		return fmt.Errorf("failed to set provider spec value: %w", err)
+	}
+
+	return nil
+}
+
+// SetAWSProviderSpecRootVolumeIOPS sets the IOPS of the root volume of the AWS provider spec.
+// AWS only honours the IOPS of provisioned IOPS (io1 and io2) and gp3 volumes, so the volume type should be one of
+// these, see UpdateProviderSpecDiskType.
+func SetAWSProviderSpecRootVolumeIOPS(rawProviderSpec *runtime.RawExtension, iops int64) error {
+	cfg, err := awsProviderConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	ebs := awsRootBlockDevice(cfg)
+	if ebs == nil {
+		return errNoBootDisk
+	}
+
+	ebs.Iops = &iops
+
+	if err := setProviderSpecValue(rawProviderSpec, cfg); err != nil {
+		return fmt.Errorf("failed to set provider spec value: %w", err)
+	}
+
+	return nil
+}
+
+// GetAWSProviderSpecRootVolumeIOPS returns the IOPS of the root volume of the AWS provider spec, or 0 when the IOPS
+// are not set.
+func GetAWSProviderSpecRootVolumeIOPS(rawProviderSpec *runtime.RawExtension) (int64, error) {
+	cfg, err := awsProviderConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return 0, err
+	}
+
+	ebs := awsRootBlockDevice(cfg)
+	if ebs == nil {
+		return 0, errNoBootDisk
+	}
+
+	if ebs.Iops == nil {
+		return 0, nil
+	}
+
+	return *ebs.Iops, nil
+}
+
+// ProviderSpecSubnet returns the subnet of the provider spec.
+// On AWS the subnet reference is returned as its ID, ARN, or as a list of filters in the form
+// name=value1,value2;name=value, whichever is set. On Azure this is the subnet, and on GCP the subnetwork of the first
+// network interface.
+func ProviderSpecSubnet(rawProviderSpec *runtime.RawExtension) (string, error) {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return "", err
+	}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		return awsResourceReferenceString(providerConfig.AWS().Config().Subnet), nil
+	case configv1.AzurePlatformType:
+		return providerConfig.Azure().Config().Subnet, nil
+	case configv1.GCPPlatformType:
+		if networkInterface := firstGCPNetworkInterface(providerConfig.GCP().Config()); networkInterface != nil {
+			return networkInterface.Subnetwork, nil
+		}
+
+		return "", nil
+	default:
+		return "", fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+}
+
+// SetProviderSpecSubnet sets the subnet of the provider spec.
+// On AWS the subnet is referenced by its ID, replacing any existing subnet reference. On Azure this is the subnet,
+// and on GCP the subnetwork of the first network interface.
+func SetProviderSpecSubnet(rawProviderSpec *runtime.RawExtension, subnet string) error {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		cfg := providerConfig.AWS().Config()
+		cfg.Subnet = machinev1beta1.AWSResourceReference{ID: &subnet}
+		value = cfg
+	case configv1.AzurePlatformType:
+		cfg := providerConfig.Azure().Config()
+		cfg.Subnet = subnet
+		value = cfg
+	case configv1.GCPPlatformType:
+		cfg := providerConfig.GCP().Config()
+
+		networkInterface := firstGCPNetworkInterface(cfg)
+		if networkInterface == nil {
+			return errNoNetworkInterface
+		}
+
+		networkInterface.Subnetwork = subnet
+		value = cfg
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	if err := setProviderSpecValue(rawProviderSpec, value); err != nil {
+		return fmt.Errorf("failed to set provider spec value: %w", err)
+	}
+
+	return nil
+}
+
+// firstGCPNetworkInterface returns the first network interface of the GCP provider spec, or nil if there is none.
+func firstGCPNetworkInterface(cfg machinev1beta1.GCPMachineProviderSpec) *machinev1beta1.GCPNetworkInterface {
+	for _, networkInterface := range cfg.NetworkInterfaces {
+		if networkInterface != nil {
+			return networkInterface
+		}
+	}
+
+	return nil
+}
+
+// ProviderSpecSecurityGroups returns the security groups applied to instances by the provider spec.
+// On AWS these are the security group references, in the same form as ProviderSpecSubnet. On Azure this is the
+// network security group, followed by the application security groups, and on GCP the network tags, which the
+// firewall rules of the cluster target.
+func ProviderSpecSecurityGroups(rawProviderSpec *runtime.RawExtension) ([]string, error) {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	securityGroups := []string{}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		for _, securityGroup := range providerConfig.AWS().Config().SecurityGroups {
+			securityGroups = append(securityGroups, awsResourceReferenceString(securityGroup))
+		}
+	case configv1.AzurePlatformType:
+		cfg := providerConfig.Azure().Config()
+
+		if cfg.SecurityGroup != "" {
+			securityGroups = append(securityGroups, cfg.SecurityGroup)
+		}
+
+		securityGroups = append(securityGroups, cfg.ApplicationSecurityGroups...)
+	case configv1.GCPPlatformType:
+		securityGroups = append(securityGroups, providerConfig.GCP().Config().Tags...)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	return securityGroups, nil
+}
+
+// AddProviderSpecSecurityGroup adds the security group to those applied to instances by the provider spec.
+// On AWS the security group is referenced by ID. On Azure, where an instance has a single network security group,
+// it is added as an application security group, and on GCP it is added as a network tag.
+func AddProviderSpecSecurityGroup(rawProviderSpec *runtime.RawExtension, securityGroup string) error {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		cfg := providerConfig.AWS().Config()
+		cfg.SecurityGroups = append(cfg.SecurityGroups, machinev1beta1.AWSResourceReference{ID: &securityGroup})
+		value = cfg
+	case configv1.AzurePlatformType:
+		cfg := providerConfig.Azure().Config()
+		cfg.ApplicationSecurityGroups = append(cfg.ApplicationSecurityGroups, securityGroup)
+		value = cfg
+	case configv1.GCPPlatformType:
+		cfg := providerConfig.GCP().Config()
+		cfg.Tags = append(cfg.Tags, securityGroup)
+		value = cfg
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+
+	if err := setProviderSpecValue(rawProviderSpec, value); err != nil {
+		return fmt.Errorf("failed to set provider spec value: %w", err)
+	}
+
+	return nil
+}
+
+// ProviderSpecRegion returns the region of the provider spec.
+// On AWS this is the placement region, on Azure the location, and on GCP the region.
+// An empty string is returned when the provider spec does not set the region.
+func ProviderSpecRegion(rawProviderSpec *runtime.RawExtension) (string, error) {
+	providerConfig, err := providerConfigFromRawExtension(rawProviderSpec)
+	if err != nil {
+		return "", err
+	}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType:
+		return providerConfig.AWS().Config().Placement.Region, nil
+	case configv1.AzurePlatformType:
+		return providerConfig.Azure().Config().Location, nil
+	case configv1.GCPPlatformType:
+		return providerConfig.GCP().Config().Region, nil
+	default:
+		return "", fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+}
+
+// AWSFailureDomainSubnet returns the subnet of the AWS failure domain, in the same form as ProviderSpecSubnet.
+// An empty string is returned when the failure domain does not set a subnet.
+func AWSFailureDomainSubnet(failureDomain machinev1.AWSFailureDomain) string {
+	if failureDomain.Subnet == nil {
+		return ""
+	}
+
+	reference := machinev1beta1.AWSResourceReference{}
+
+	switch failureDomain.Subnet.Type {
+	case machinev1.AWSIDReferenceType:
+		reference.ID = failureDomain.Subnet.ID
+	case machinev1.AWSARNReferenceType:
+		reference.ARN = failureDomain.Subnet.ARN
+	case machinev1.AWSFiltersReferenceType:
+		if failureDomain.Subnet.Filters != nil {
+			for _, filter := range *failureDomain.Subnet.Filters {
+				reference.Filters = append(reference.Filters, machinev1beta1.Filter{Name: filter.Name, Values: filter.Values})
+			}
+		}
+	}
+
+	return awsResourceReferenceString(reference)
+}
+
+// awsResourceReferenceString returns the ID, ARN or filters of the AWS resource reference, whichever is set.
+// Filters are formatted as name=value1,value2;name=value.
+func awsResourceReferenceString(reference machinev1beta1.AWSResourceReference) string {
+	switch {
+	case reference.ID != nil:
+		return *reference.ID
+	case reference.ARN != nil:
+		return *reference.ARN
+	}
+
+	filters := []string{}
+
+	for _, filter := range reference.Filters {
+		filters = append(filters, fmt.Sprintf("%s=%s", filter.Name, strings.Join(filter.Values, ",")))
+	}
+
+	return strings.Join(filters, ";")
+}
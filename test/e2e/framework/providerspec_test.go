@@ -0,0 +1,850 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/cluster-control-plane-machine-set-operator/pkg/test/resourcebuilder"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("ProviderSpec", func() {
+	Context("AddProviderSpecLoadBalancer", func() {
+		type loadBalancerTableInput struct {
+			providerSpec          *runtime.RawExtension
+			loadBalancer          string
+			expectedLoadBalancers []string
+		}
+
+		DescribeTable("should add the load balancer to the provider spec", func(in loadBalancerTableInput) {
+			Expect(AddProviderSpecLoadBalancer(in.providerSpec, in.loadBalancer)).To(Succeed())
+
+			loadBalancers, err := GetProviderSpecLoadBalancers(in.providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(loadBalancers).To(Equal(in.expectedLoadBalancers))
+		},
+			Entry("on AWS", loadBalancerTableInput{
+				providerSpec:          resourcebuilder.AWSProviderSpec().BuildRawExtension(),
+				loadBalancer:          "aws-nlb-new",
+				expectedLoadBalancers: []string{"aws-nlb-int", "aws-nlb-ext", "aws-nlb-new"},
+			}),
+			Entry("on Azure", loadBalancerTableInput{
+				providerSpec:          resourcebuilder.AzureProviderSpec().WithInternalLoadBalancer("azure-lb-int").BuildRawExtension(),
+				loadBalancer:          "azure-lb-new",
+				expectedLoadBalancers: []string{"azure-lb-new", "public-load-balancer-12345678"},
+			}),
+			Entry("on GCP", loadBalancerTableInput{
+				providerSpec:          resourcebuilder.GCPProviderSpec().WithTargetPools([]string{"gcp-tp-1"}).BuildRawExtension(),
+				loadBalancer:          "gcp-tp-new",
+				expectedLoadBalancers: []string{"gcp-tp-1", "gcp-tp-new"},
+			}),
+		)
+
+		It("should return an error when the provider spec is nil", func() {
+			Expect(AddProviderSpecLoadBalancer(nil, "lb")).To(MatchError(errNilProviderSpec))
+		})
+	})
+
+	Context("SetProviderSpecUserDataSecret", func() {
+		DescribeTable("should set the user data secret on the provider spec", func(providerSpec *runtime.RawExtension) {
+			Expect(SetProviderSpecUserDataSecret(providerSpec, "user-data-e2e")).To(Succeed())
+
+			secretName, err := GetProviderSpecUserDataSecret(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secretName).To(Equal("user-data-e2e"))
+		},
+			Entry("on AWS", resourcebuilder.AWSProviderSpec().BuildRawExtension()),
+			Entry("on Azure", resourcebuilder.AzureProviderSpec().BuildRawExtension()),
+			Entry("on GCP", resourcebuilder.GCPProviderSpec().BuildRawExtension()),
+		)
+	})
+
+	Context("ClearProviderSpecCredentialsSecret", func() {
+		DescribeTable("should remove the credentials secret from the provider spec", func(providerSpec *runtime.RawExtension, expectedSecret string) {
+			secretName, err := GetProviderSpecCredentialsSecret(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secretName).To(Equal(expectedSecret))
+
+			Expect(ClearProviderSpecCredentialsSecret(providerSpec)).To(Succeed())
+
+			secretName, err = GetProviderSpecCredentialsSecret(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secretName).To(BeEmpty())
+		},
+			Entry("on AWS", resourcebuilder.AWSProviderSpec().BuildRawExtension(), "aws-cloud-credentials"),
+			Entry("on Azure", resourcebuilder.AzureProviderSpec().BuildRawExtension(), "azure-cloud-credentials"),
+			Entry("on GCP", resourcebuilder.GCPProviderSpec().BuildRawExtension(), "gcp-cloud-credentials"),
+		)
+	})
+
+	Context("SetProviderSpecAPIVersion", func() {
+		It("should change only the API version of the provider spec", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().WithInstanceType("m6i.xlarge").BuildRawExtension()
+
+			Expect(SetProviderSpecAPIVersion(providerSpec, "machine.openshift.io/v1beta1")).To(Succeed())
+
+			apiVersion, err := ProviderSpecAPIVersion(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(apiVersion).To(Equal("machine.openshift.io/v1beta1"))
+
+			instanceType, err := ProviderSpecInstanceType(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instanceType).To(Equal("m6i.xlarge"), "the instance type should be preserved")
+		})
+
+		It("should report the current API version first", func() {
+			Expect(CompatibleProviderSpecAPIVersions(configv1.AWSPlatformType)).To(Equal([]string{"machine.openshift.io/v1beta1", "awsproviderconfig.openshift.io/v1beta1"}))
+			Expect(CompatibleProviderSpecAPIVersions(configv1.VSpherePlatformType)).To(BeEmpty())
+		})
+	})
+
+	Context("SetProviderSpecMetadataName", func() {
+		It("should change only the metadata name of the provider spec", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().WithInstanceType("m6i.xlarge").BuildRawExtension()
+
+			Expect(SetProviderSpecMetadataName(providerSpec, "machine-0")).To(Succeed())
+
+			name, err := ProviderSpecMetadataName(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(name).To(Equal("machine-0"))
+
+			instanceType, err := ProviderSpecInstanceType(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instanceType).To(Equal("m6i.xlarge"), "the instance type should be preserved")
+		})
+
+		It("should remove the metadata name when the name is empty", func() {
+			providerSpec := resourcebuilder.GCPProviderSpec().BuildRawExtension()
+
+			Expect(SetProviderSpecMetadataName(providerSpec, "machine-0")).To(Succeed())
+			Expect(SetProviderSpecMetadataName(providerSpec, "")).To(Succeed())
+
+			name, err := ProviderSpecMetadataName(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(name).To(BeEmpty())
+		})
+
+		It("should return an error when the provider spec has no metadata block", func() {
+			providerSpec := &runtime.RawExtension{
+				Raw: []byte(`{"kind":"AWSMachineProviderConfig","apiVersion":"machine.openshift.io/v1beta1"}`),
+			}
+
+			Expect(SetProviderSpecMetadataName(providerSpec, "machine-0")).To(MatchError(ErrNoProviderSpecMetadata))
+		})
+	})
+
+	Context("SetProviderSpecMetadataCreationTimestamp", func() {
+		It("should change only the metadata creation timestamp of the provider spec", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().WithInstanceType("m6i.xlarge").BuildRawExtension()
+			timestamp := time.Date(2022, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+			Expect(SetProviderSpecMetadataCreationTimestamp(providerSpec, timestamp)).To(Succeed())
+
+			fields, err := rawProviderSpecFields(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fields).To(HaveKeyWithValue("metadata", HaveKeyWithValue("creationTimestamp", "2022-06-01T12:00:00Z")))
+
+			instanceType, err := ProviderSpecInstanceType(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instanceType).To(Equal("m6i.xlarge"), "the instance type should be preserved")
+		})
+
+		It("should return an error when the provider spec has no metadata block", func() {
+			providerSpec := &runtime.RawExtension{
+				Raw: []byte(`{"kind":"AWSMachineProviderConfig","apiVersion":"machine.openshift.io/v1beta1"}`),
+			}
+
+			Expect(SetProviderSpecMetadataCreationTimestamp(providerSpec, time.Now())).To(MatchError(ErrNoProviderSpecMetadata))
+		})
+	})
+
+	Context("ProviderSpecInstanceType", func() {
+		DescribeTable("should return the instance type of the provider spec", func(providerSpec *runtime.RawExtension, expected string) {
+			instanceType, err := ProviderSpecInstanceType(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instanceType).To(Equal(expected))
+		},
+			Entry("on AWS", resourcebuilder.AWSProviderSpec().WithInstanceType("m6i.xlarge").BuildRawExtension(), "m6i.xlarge"),
+			Entry("on Azure", resourcebuilder.AzureProviderSpec().WithVMSize("Standard_D4s_v3").BuildRawExtension(), "Standard_D4s_v3"),
+			Entry("on GCP", resourcebuilder.GCPProviderSpec().WithMachineType("n2-standard-4").BuildRawExtension(), "n2-standard-4"),
+			Entry("on GCP with a partial URL", resourcebuilder.GCPProviderSpec().WithMachineType("zones/us-central1-a/machineTypes/n2-standard-4").BuildRawExtension(), "n2-standard-4"),
+		)
+
+		It("should return an error for vSphere", func() {
+			providerSpec := &runtime.RawExtension{
+				Raw: []byte(`{"kind":"VSphereMachineProviderSpec","apiVersion":"machine.openshift.io/v1beta1"}`),
+			}
+
+			_, err := ProviderSpecInstanceType(providerSpec)
+			Expect(err).To(MatchError(errUnsupportedPlatform))
+		})
+	})
+
+	Context("ProviderSpecEncryptionSettings", func() {
+		type encryptionTableInput struct {
+			providerSpec      *runtime.RawExtension
+			expectedEncrypted bool
+			expectedKMSKey    string
+		}
+
+		awsKMSProviderSpec := resourcebuilder.AWSProviderSpec().Build()
+		awsKMSProviderSpec.BlockDevices[0].EBS.KMSKey = machinev1beta1.AWSResourceReference{ARN: pointer.String("arn:aws:kms:us-east-1:123456789012:key/e2e")}
+
+		azureDESProviderSpec := resourcebuilder.AzureProviderSpec().Build()
+		azureDESProviderSpec.OSDisk.ManagedDisk.DiskEncryptionSet = &machinev1beta1.DiskEncryptionSetParameters{ID: "/resourceGroups/test-rg/providers/Microsoft.Compute/diskEncryptionSets/e2e"}
+
+		gcpKMSProviderSpec := resourcebuilder.GCPProviderSpec().Build()
+		gcpKMSProviderSpec.Disks[0].EncryptionKey = &machinev1beta1.GCPEncryptionKeyReference{
+			KMSKey: &machinev1beta1.GCPKMSKeyReference{
+				Name:     "e2e-key",
+				KeyRing:  "e2e-key-ring",
+				Location: "global",
+			},
+		}
+
+		DescribeTable("should return the root disk encryption settings of the provider spec", func(in encryptionTableInput) {
+			encrypted, kmsKey, err := ProviderSpecEncryptionSettings(in.providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(encrypted).To(Equal(in.expectedEncrypted))
+			Expect(kmsKey).To(Equal(in.expectedKMSKey))
+		},
+			Entry("on AWS", encryptionTableInput{
+				providerSpec:      resourcebuilder.AWSProviderSpec().BuildRawExtension(),
+				expectedEncrypted: true,
+			}),
+			Entry("on AWS with a KMS key", encryptionTableInput{
+				providerSpec:      &runtime.RawExtension{Object: awsKMSProviderSpec},
+				expectedEncrypted: true,
+				expectedKMSKey:    "arn:aws:kms:us-east-1:123456789012:key/e2e",
+			}),
+			Entry("on Azure", encryptionTableInput{
+				providerSpec: resourcebuilder.AzureProviderSpec().BuildRawExtension(),
+			}),
+			Entry("on Azure with a disk encryption set", encryptionTableInput{
+				providerSpec:      &runtime.RawExtension{Object: azureDESProviderSpec},
+				expectedEncrypted: true,
+				expectedKMSKey:    "/resourceGroups/test-rg/providers/Microsoft.Compute/diskEncryptionSets/e2e",
+			}),
+			Entry("on GCP", encryptionTableInput{
+				providerSpec: resourcebuilder.GCPProviderSpec().BuildRawExtension(),
+			}),
+			Entry("on GCP with a KMS key", encryptionTableInput{
+				providerSpec:      &runtime.RawExtension{Object: gcpKMSProviderSpec},
+				expectedEncrypted: true,
+				expectedKMSKey:    "projects/openshift-cpms-unit-tests/locations/global/keyRings/e2e-key-ring/cryptoKeys/e2e-key",
+			}),
+		)
+
+		It("should return an error for vSphere", func() {
+			providerSpec := &runtime.RawExtension{
+				Raw: []byte(`{"kind":"VSphereMachineProviderSpec","apiVersion":"machine.openshift.io/v1beta1"}`),
+			}
+
+			_, _, err := ProviderSpecEncryptionSettings(providerSpec)
+			Expect(err).To(MatchError(errUnsupportedPlatform))
+		})
+	})
+
+	Context("IncreaseProviderSpecRootDiskSize", func() {
+		DescribeTable("should grow the root disk of the provider spec", func(providerSpec *runtime.RawExtension, expectedSize int64) {
+			Expect(IncreaseProviderSpecRootDiskSize(providerSpec)).To(Succeed())
+
+			size, err := ProviderSpecRootDiskSize(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(size).To(Equal(expectedSize))
+		},
+			Entry("on AWS", resourcebuilder.AWSProviderSpec().BuildRawExtension(), int64(140)),
+			Entry("on Azure", resourcebuilder.AzureProviderSpec().BuildRawExtension(), int64(148)),
+			Entry("on GCP", resourcebuilder.GCPProviderSpec().BuildRawExtension(), int64(148)),
+		)
+
+		It("should return an error for vSphere", func() {
+			providerSpec := &runtime.RawExtension{
+				Raw: []byte(`{"kind":"VSphereMachineProviderSpec","apiVersion":"machine.openshift.io/v1beta1"}`),
+			}
+
+			Expect(IncreaseProviderSpecRootDiskSize(providerSpec)).To(MatchError(errUnsupportedPlatform))
+		})
+	})
+
+	Context("UpdateProviderSpecKMSKey", func() {
+		DescribeTable("should set the root disk encryption key of the provider spec", func(providerSpec *runtime.RawExtension, kmsKey string) {
+			Expect(UpdateProviderSpecKMSKey(providerSpec, kmsKey)).To(Succeed())
+
+			encrypted, updatedKMSKey, err := ProviderSpecEncryptionSettings(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(encrypted).To(BeTrue(), "the root disk should be encrypted")
+			Expect(updatedKMSKey).To(Equal(kmsKey))
+		},
+			Entry("on AWS", resourcebuilder.AWSProviderSpec().BuildRawExtension(), "arn:aws:kms:us-east-1:123456789012:key/e2e-rotated"),
+			Entry("on Azure", resourcebuilder.AzureProviderSpec().BuildRawExtension(), "/resourceGroups/test-rg/providers/Microsoft.Compute/diskEncryptionSets/e2e-rotated"),
+			Entry("on GCP", resourcebuilder.GCPProviderSpec().BuildRawExtension(), "projects/e2e-project/locations/global/keyRings/e2e-key-ring/cryptoKeys/e2e-rotated"),
+		)
+
+		It("should preserve the rest of the provider spec", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().WithInstanceType("m6i.xlarge").BuildRawExtension()
+
+			Expect(UpdateProviderSpecKMSKey(providerSpec, "arn:aws:kms:us-east-1:123456789012:key/e2e-rotated")).To(Succeed())
+
+			instanceType, err := ProviderSpecInstanceType(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instanceType).To(Equal("m6i.xlarge"), "the instance type should be preserved")
+		})
+
+		It("should return an error for a GCP KMS key that is not a full resource name", func() {
+			providerSpec := resourcebuilder.GCPProviderSpec().BuildRawExtension()
+
+			Expect(UpdateProviderSpecKMSKey(providerSpec, "e2e-rotated")).To(MatchError(errInvalidGCPKMSKey))
+		})
+
+		It("should return an error for vSphere", func() {
+			providerSpec := &runtime.RawExtension{
+				Raw: []byte(`{"kind":"VSphereMachineProviderSpec","apiVersion":"machine.openshift.io/v1beta1"}`),
+			}
+
+			Expect(UpdateProviderSpecKMSKey(providerSpec, "e2e-rotated")).To(MatchError(errUnsupportedPlatform))
+		})
+	})
+
+	Context("UpdateProviderSpecDiskType", func() {
+		DescribeTable("should set the root disk type of the provider spec", func(providerSpec *runtime.RawExtension, diskType string) {
+			Expect(UpdateProviderSpecDiskType(providerSpec, diskType)).To(Succeed())
+
+			updatedDiskType, err := ProviderSpecDiskType(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updatedDiskType).To(Equal(diskType))
+		},
+			Entry("on AWS", resourcebuilder.AWSProviderSpec().BuildRawExtension(), "io2"),
+			Entry("on Azure", resourcebuilder.AzureProviderSpec().BuildRawExtension(), "StandardSSD_LRS"),
+			Entry("on GCP", resourcebuilder.GCPProviderSpec().BuildRawExtension(), "pd-balanced"),
+		)
+
+		It("should set the IOPS when switching to a provisioned IOPS volume type on AWS", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().BuildRawExtension()
+			Expect(UpdateProviderSpecDiskType(providerSpec, "io2")).To(Succeed())
+
+			cfg, err := awsProviderConfigFromRawExtension(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(awsRootBlockDevice(cfg).Iops).To(HaveValue(BeEquivalentTo(provisionedIOPSVolumeTypeIOPS)))
+		})
+
+		It("should not set the IOPS when switching to a general purpose volume type on AWS", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().BuildRawExtension()
+			Expect(UpdateProviderSpecDiskType(providerSpec, "gp2")).To(Succeed())
+
+			cfg, err := awsProviderConfigFromRawExtension(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(awsRootBlockDevice(cfg).Iops).To(BeNil())
+		})
+
+		It("should return an error for vSphere", func() {
+			providerSpec := &runtime.RawExtension{
+				Raw: []byte(`{"kind":"VSphereMachineProviderSpec","apiVersion":"machine.openshift.io/v1beta1"}`),
+			}
+
+			Expect(UpdateProviderSpecDiskType(providerSpec, "thin")).To(MatchError(errUnsupportedPlatform))
+		})
+	})
+
+	Context("SetAWSProviderSpecRootVolumeIOPS", func() {
+		It("should set the IOPS of the root volume on an AWS provider spec", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().BuildRawExtension()
+
+			Expect(SetAWSProviderSpecRootVolumeIOPS(providerSpec, 4000)).To(Succeed())
+
+			iops, err := GetAWSProviderSpecRootVolumeIOPS(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(iops).To(BeEquivalentTo(4000))
+
+			diskType, err := ProviderSpecDiskType(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diskType).To(Equal("gp3"), "the volume type should be preserved")
+		})
+
+		It("should return no IOPS when the root volume does not set them", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().BuildRawExtension()
+
+			iops, err := GetAWSProviderSpecRootVolumeIOPS(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(iops).To(BeZero())
+		})
+
+		It("should return an error for a non-AWS provider spec", func() {
+			providerSpec := resourcebuilder.GCPProviderSpec().BuildRawExtension()
+
+			Expect(SetAWSProviderSpecRootVolumeIOPS(providerSpec, 4000)).To(MatchError(errUnsupportedPlatform))
+
+			_, err := GetAWSProviderSpecRootVolumeIOPS(providerSpec)
+			Expect(err).To(MatchError(errUnsupportedPlatform))
+		})
+	})
+
+	Context("ProviderSpecSubnet", func() {
+		DescribeTable("should return the subnet of the provider spec", func(providerSpec *runtime.RawExtension, expectedSubnet string) {
+			subnet, err := ProviderSpecSubnet(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(subnet).To(Equal(expectedSubnet))
+		},
+			Entry("on AWS with a subnet filter", resourcebuilder.AWSProviderSpec().BuildRawExtension(), "tag:Name=aws-subnet-12345678"),
+			Entry("on AWS with a subnet ID", resourcebuilder.AWSProviderSpec().WithSubnet(machinev1beta1.AWSResourceReference{
+				ID: pointer.String("subnet-us-east-1a"),
+			}).BuildRawExtension(), "subnet-us-east-1a"),
+			Entry("on AWS with a subnet ARN", resourcebuilder.AWSProviderSpec().WithSubnet(machinev1beta1.AWSResourceReference{
+				ARN: pointer.String("arn:aws:ec2:us-east-1:123456789012:subnet/subnet-us-east-1a"),
+			}).BuildRawExtension(), "arn:aws:ec2:us-east-1:123456789012:subnet/subnet-us-east-1a"),
+			Entry("on Azure", resourcebuilder.AzureProviderSpec().BuildRawExtension(), "subnet-12345678"),
+			Entry("on GCP", resourcebuilder.GCPProviderSpec().BuildRawExtension(), "gcp-subnetwork-12345678"),
+		)
+
+		It("should return an error for vSphere", func() {
+			providerSpec := &runtime.RawExtension{
+				Raw: []byte(`{"kind":"VSphereMachineProviderSpec","apiVersion":"machine.openshift.io/v1beta1"}`),
+			}
+
+			_, err := ProviderSpecSubnet(providerSpec)
+			Expect(err).To(MatchError(errUnsupportedPlatform))
+		})
+	})
+
+	Context("SetProviderSpecSubnet", func() {
+		DescribeTable("should set the subnet of the provider spec", func(providerSpec *runtime.RawExtension) {
+			Expect(SetProviderSpecSubnet(providerSpec, "e2e-subnet")).To(Succeed())
+
+			subnet, err := ProviderSpecSubnet(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(subnet).To(Equal("e2e-subnet"))
+		},
+			Entry("on AWS with a subnet filter", resourcebuilder.AWSProviderSpec().BuildRawExtension()),
+			Entry("on Azure", resourcebuilder.AzureProviderSpec().BuildRawExtension()),
+			Entry("on GCP", resourcebuilder.GCPProviderSpec().BuildRawExtension()),
+		)
+
+		It("should return an error for vSphere", func() {
+			providerSpec := &runtime.RawExtension{
+				Raw: []byte(`{"kind":"VSphereMachineProviderSpec","apiVersion":"machine.openshift.io/v1beta1"}`),
+			}
+
+			Expect(SetProviderSpecSubnet(providerSpec, "e2e-subnet")).To(MatchError(errUnsupportedPlatform))
+		})
+	})
+
+	Context("ProviderSpecSecurityGroups", func() {
+		DescribeTable("should return the security groups of the provider spec", func(providerSpec *runtime.RawExtension, expectedSecurityGroups []string) {
+			securityGroups, err := ProviderSpecSecurityGroups(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(securityGroups).To(Equal(expectedSecurityGroups))
+		},
+			Entry("on AWS with a security group filter", resourcebuilder.AWSProviderSpec().BuildRawExtension(), []string{"tag:Name=aws-security-group-12345678"}),
+			Entry("on AWS with security group IDs", resourcebuilder.AWSProviderSpec().WithSecurityGroups([]machinev1beta1.AWSResourceReference{
+				{ID: pointer.String("sg-master")},
+				{ID: pointer.String("sg-node")},
+			}).BuildRawExtension(), []string{"sg-master", "sg-node"}),
+			Entry("on Azure without security groups", resourcebuilder.AzureProviderSpec().BuildRawExtension(), []string{}),
+			Entry("on GCP", resourcebuilder.GCPProviderSpec().BuildRawExtension(), []string{"gcp-tag-12345678"}),
+		)
+	})
+
+	Context("AddProviderSpecSecurityGroup", func() {
+		DescribeTable("should add the security group to the provider spec", func(providerSpec *runtime.RawExtension) {
+			originalSecurityGroups, err := ProviderSpecSecurityGroups(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(AddProviderSpecSecurityGroup(providerSpec, "e2e-security-group")).To(Succeed())
+
+			securityGroups, err := ProviderSpecSecurityGroups(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(securityGroups).To(Equal(append(originalSecurityGroups, "e2e-security-group")))
+		},
+			Entry("on AWS", resourcebuilder.AWSProviderSpec().BuildRawExtension()),
+			Entry("on Azure", resourcebuilder.AzureProviderSpec().BuildRawExtension()),
+			Entry("on GCP", resourcebuilder.GCPProviderSpec().BuildRawExtension()),
+		)
+	})
+
+	Context("ProviderSpecRegion", func() {
+		DescribeTable("should return the region of the provider spec", func(providerSpec *runtime.RawExtension, expectedRegion string) {
+			region, err := ProviderSpecRegion(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(region).To(Equal(expectedRegion))
+		},
+			Entry("on AWS", resourcebuilder.AWSProviderSpec().BuildRawExtension(), "us-east-1"),
+			Entry("on Azure", resourcebuilder.AzureProviderSpec().BuildRawExtension(), "test-location"),
+			Entry("on GCP", resourcebuilder.GCPProviderSpec().BuildRawExtension(), "us-central1"),
+		)
+
+		It("should return an empty region when none is set", func() {
+			providerSpec := &runtime.RawExtension{
+				Raw: []byte(`{"kind":"AWSMachineProviderConfig","apiVersion":"machine.openshift.io/v1beta1"}`),
+			}
+
+			region, err := ProviderSpecRegion(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(region).To(BeEmpty())
+		})
+
+		It("should return an error for vSphere", func() {
+			providerSpec := &runtime.RawExtension{
+				Raw: []byte(`{"kind":"VSphereMachineProviderSpec","apiVersion":"machine.openshift.io/v1beta1"}`),
+			}
+
+			_, err := ProviderSpecRegion(providerSpec)
+			Expect(err).To(MatchError(errUnsupportedPlatform))
+		})
+	})
+
+	Context("AWSFailureDomainSubnet", func() {
+		DescribeTable("should return the subnet of the failure domain", func(failureDomain machinev1.AWSFailureDomain, expectedSubnet string) {
+			Expect(AWSFailureDomainSubnet(failureDomain)).To(Equal(expectedSubnet))
+		},
+			Entry("with a subnet ID", resourcebuilder.AWSFailureDomain().WithSubnet(machinev1.AWSResourceReference{
+				Type: machinev1.AWSIDReferenceType,
+				ID:   pointer.String("subnet-us-east-1a"),
+			}).Build(), "subnet-us-east-1a"),
+			Entry("with a subnet filter", resourcebuilder.AWSFailureDomain().WithSubnet(machinev1.AWSResourceReference{
+				Type: machinev1.AWSFiltersReferenceType,
+				Filters: &[]machinev1.AWSResourceFilter{{
+					Name:   "tag:Name",
+					Values: []string{"aws-subnet-12345678"},
+				}},
+			}).Build(), "tag:Name=aws-subnet-12345678"),
+			Entry("without a subnet", resourcebuilder.AWSFailureDomain().WithAvailabilityZone("us-east-1a").Build(), ""),
+		)
+
+		It("should match the subnet of a provider spec with the failure domain injected", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().WithSubnet(machinev1beta1.AWSResourceReference{
+				Filters: []machinev1beta1.Filter{{Name: "tag:Name", Values: []string{"subnet-a", "subnet-b"}}},
+			}).BuildRawExtension()
+
+			failureDomain := resourcebuilder.AWSFailureDomain().WithSubnet(machinev1.AWSResourceReference{
+				Type:    machinev1.AWSFiltersReferenceType,
+				Filters: &[]machinev1.AWSResourceFilter{{Name: "tag:Name", Values: []string{"subnet-a", "subnet-b"}}},
+			}).Build()
+
+			subnet, err := ProviderSpecSubnet(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(subnet).To(Equal(AWSFailureDomainSubnet(failureDomain)))
+		})
+	})
+
+	Context("SetProviderSpecInstanceType", func() {
+		DescribeTable("should set the instance type on the provider spec", func(providerSpec *runtime.RawExtension) {
+			Expect(SetProviderSpecInstanceType(providerSpec, "e2e-instance-type")).To(Succeed())
+
+			instanceType, err := ProviderSpecInstanceType(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instanceType).To(Equal("e2e-instance-type"))
+		},
+			Entry("on AWS", resourcebuilder.AWSProviderSpec().BuildRawExtension()),
+			Entry("on Azure", resourcebuilder.AzureProviderSpec().BuildRawExtension()),
+			Entry("on GCP", resourcebuilder.GCPProviderSpec().BuildRawExtension()),
+		)
+	})
+
+	Context("UpdateProviderSpecImage", func() {
+		DescribeTable("should set the boot image on the provider spec", func(providerSpec *runtime.RawExtension, image string) {
+			Expect(UpdateProviderSpecImage(providerSpec, image)).To(Succeed())
+
+			updatedImage, err := ProviderSpecImage(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updatedImage).To(Equal(image))
+		},
+			Entry("on AWS", resourcebuilder.AWSProviderSpec().BuildRawExtension(), "aws-ami-e2e"),
+			Entry("on Azure", resourcebuilder.AzureProviderSpec().BuildRawExtension(), "/resourceGroups/test-rg/providers/Microsoft.Compute/images/e2e-image"),
+			Entry("on GCP", resourcebuilder.GCPProviderSpec().BuildRawExtension(), "projects/rhcos-cloud/global/images/rhcos-e2e"),
+		)
+	})
+
+	Context("SetAWSProviderSpecTenancy", func() {
+		It("should set the tenancy on an AWS provider spec", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().BuildRawExtension()
+
+			Expect(SetAWSProviderSpecTenancy(providerSpec, "dedicated")).To(Succeed())
+
+			tenancy, err := GetAWSProviderSpecTenancy(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tenancy).To(Equal("dedicated"))
+		})
+
+		It("should return an error for a non-AWS provider spec", func() {
+			providerSpec := resourcebuilder.GCPProviderSpec().BuildRawExtension()
+
+			Expect(SetAWSProviderSpecTenancy(providerSpec, "dedicated")).To(MatchError(errUnsupportedPlatform))
+		})
+	})
+
+	Context("SetAWSProviderSpecAvailabilityZone", func() {
+		It("should set the availability zone on an AWS provider spec", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().BuildRawExtension()
+
+			Expect(SetAWSProviderSpecAvailabilityZone(providerSpec, "us-east-1f")).To(Succeed())
+
+			zone, err := GetAWSProviderSpecAvailabilityZone(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(zone).To(Equal("us-east-1f"))
+		})
+
+		It("should return an error for a non-AWS provider spec", func() {
+			providerSpec := resourcebuilder.AzureProviderSpec().BuildRawExtension()
+
+			Expect(SetAWSProviderSpecAvailabilityZone(providerSpec, "us-east-1f")).To(MatchError(errUnsupportedPlatform))
+		})
+	})
+
+	Context("ReverseAWSProviderSpecTags", func() {
+		It("should reverse the tags on an AWS provider spec", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().WithTags([]machinev1beta1.TagSpecification{
+				{Name: "a", Value: "1"},
+				{Name: "b", Value: "2"},
+				{Name: "c", Value: "3"},
+			}).BuildRawExtension()
+
+			Expect(ReverseAWSProviderSpecTags(providerSpec)).To(Succeed())
+
+			tags, err := GetAWSProviderSpecTags(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tags).To(Equal([]machinev1beta1.TagSpecification{
+				{Name: "c", Value: "3"},
+				{Name: "b", Value: "2"},
+				{Name: "a", Value: "1"},
+			}))
+		})
+
+		It("should return an error for a non-AWS provider spec", func() {
+			providerSpec := resourcebuilder.GCPProviderSpec().BuildRawExtension()
+
+			Expect(ReverseAWSProviderSpecTags(providerSpec)).To(MatchError(errUnsupportedPlatform))
+		})
+	})
+
+	Context("AddAWSProviderSpecTags", func() {
+		It("should append the tags to an AWS provider spec", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().WithTags([]machinev1beta1.TagSpecification{
+				{Name: "a", Value: "1"},
+			}).BuildRawExtension()
+
+			Expect(AddAWSProviderSpecTags(providerSpec,
+				machinev1beta1.TagSpecification{Name: "b", Value: "2"},
+				machinev1beta1.TagSpecification{Name: "c", Value: "3"},
+			)).To(Succeed())
+
+			tags, err := GetAWSProviderSpecTags(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tags).To(Equal([]machinev1beta1.TagSpecification{
+				{Name: "a", Value: "1"},
+				{Name: "b", Value: "2"},
+				{Name: "c", Value: "3"},
+			}))
+		})
+
+		It("should return an error for a non-AWS provider spec", func() {
+			providerSpec := resourcebuilder.GCPProviderSpec().BuildRawExtension()
+
+			Expect(AddAWSProviderSpecTags(providerSpec, machinev1beta1.TagSpecification{Name: "a", Value: "1"})).To(MatchError(errUnsupportedPlatform))
+		})
+	})
+
+	Context("ProviderSpecHasClusterIDTag", func() {
+		DescribeTable("should detect the cluster ID tag", func(providerSpec *runtime.RawExtension, expected bool) {
+			tagged, err := ProviderSpecHasClusterIDTag(providerSpec, "cluster-abc12")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tagged).To(Equal(expected))
+		},
+			Entry("on AWS with an owned tag", resourcebuilder.AWSProviderSpec().WithTags([]machinev1beta1.TagSpecification{
+				{Name: "kubernetes.io/cluster/cluster-abc12", Value: "owned"},
+			}).BuildRawExtension(), true),
+			Entry("on AWS with a shared tag", resourcebuilder.AWSProviderSpec().WithTags([]machinev1beta1.TagSpecification{
+				{Name: "kubernetes.io/cluster/cluster-abc12", Value: "shared"},
+			}).BuildRawExtension(), false),
+			Entry("on AWS with the tag of another cluster", resourcebuilder.AWSProviderSpec().WithTags([]machinev1beta1.TagSpecification{
+				{Name: "kubernetes.io/cluster/cluster-def34", Value: "owned"},
+			}).BuildRawExtension(), false),
+			Entry("on GCP without the control plane network tag", resourcebuilder.GCPProviderSpec().BuildRawExtension(), false),
+		)
+
+		It("should return an error for an Azure provider spec", func() {
+			_, err := ProviderSpecHasClusterIDTag(resourcebuilder.AzureProviderSpec().BuildRawExtension(), "cluster-abc12")
+			Expect(err).To(MatchError(errUnsupportedPlatform))
+		})
+	})
+
+	Context("UpdateGCPProviderSpecServiceAccount", func() {
+		It("should replace the service accounts on a GCP provider spec", func() {
+			providerSpec := resourcebuilder.GCPProviderSpec().BuildRawExtension()
+
+			Expect(UpdateGCPProviderSpecServiceAccount(providerSpec, "e2e@project.iam.gserviceaccount.com",
+				"https://www.googleapis.com/auth/cloud-platform, https://www.googleapis.com/auth/compute")).To(Succeed())
+
+			serviceAccounts, err := GetGCPProviderSpecServiceAccounts(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(serviceAccounts).To(ConsistOf(machinev1beta1.GCPServiceAccount{
+				Email: "e2e@project.iam.gserviceaccount.com",
+				Scopes: []string{
+					"https://www.googleapis.com/auth/cloud-platform",
+					"https://www.googleapis.com/auth/compute",
+				},
+			}))
+		})
+
+		It("should return an error for a non-GCP provider spec", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().BuildRawExtension()
+
+			Expect(UpdateGCPProviderSpecServiceAccount(providerSpec, "e2e@project.iam.gserviceaccount.com", "")).To(MatchError(errUnsupportedPlatform))
+		})
+	})
+
+	Context("ProviderSpecAccelerators", func() {
+		It("should return the GPUs of a GCP provider spec", func() {
+			providerSpec := resourcebuilder.GCPProviderSpec().BuildRawExtension()
+
+			cfg, err := gcpProviderConfigFromRawExtension(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+
+			cfg.GPUs = []machinev1beta1.GCPGPUConfig{
+				{Type: "nvidia-tesla-t4", Count: 1},
+				{Type: "nvidia-tesla-p4", Count: 2},
+			}
+			Expect(setProviderSpecValue(providerSpec, cfg)).To(Succeed())
+
+			accelerators, err := ProviderSpecAccelerators(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(accelerators).To(Equal([]string{"nvidia-tesla-t4:1", "nvidia-tesla-p4:2"}))
+		})
+
+		It("should return no accelerators when a GCP provider spec has no GPUs", func() {
+			providerSpec := resourcebuilder.GCPProviderSpec().BuildRawExtension()
+
+			accelerators, err := ProviderSpecAccelerators(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(accelerators).To(BeEmpty())
+		})
+
+		It("should return an error for a non-GCP provider spec", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().BuildRawExtension()
+
+			_, err := ProviderSpecAccelerators(providerSpec)
+			Expect(err).To(MatchError(ErrAcceleratorsNotSupported))
+		})
+	})
+
+	Context("ProviderSpecCloudIdentity", func() {
+		It("should return the IAM instance profile of an AWS provider spec", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().BuildRawExtension()
+
+			identity, err := ProviderSpecCloudIdentity(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(identity).To(Equal("aws-iam-instance-profile-12345678"))
+		})
+
+		It("should return the IAM instance profile ARN when an AWS provider spec does not set the ID", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().BuildRawExtension()
+
+			cfg, err := awsProviderConfigFromRawExtension(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+
+			cfg.IAMInstanceProfile = &machinev1beta1.AWSResourceReference{
+				ARN: pointer.String("arn:aws:iam::123456789012:instance-profile/e2e-profile"),
+			}
+			Expect(setProviderSpecValue(providerSpec, cfg)).To(Succeed())
+
+			identity, err := ProviderSpecCloudIdentity(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(identity).To(Equal("arn:aws:iam::123456789012:instance-profile/e2e-profile"))
+		})
+
+		It("should return the managed identity of an Azure provider spec", func() {
+			providerSpec := resourcebuilder.AzureProviderSpec().BuildRawExtension()
+
+			cfg, err := azureProviderConfigFromRawExtension(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+
+			cfg.ManagedIdentity = "e2e-identity"
+			Expect(setProviderSpecValue(providerSpec, cfg)).To(Succeed())
+
+			identity, err := ProviderSpecCloudIdentity(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(identity).To(Equal("e2e-identity"))
+		})
+
+		It("should return the service account of a GCP provider spec", func() {
+			providerSpec := resourcebuilder.GCPProviderSpec().BuildRawExtension()
+
+			identity, err := ProviderSpecCloudIdentity(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(identity).To(Equal("service-account-12345678"))
+		})
+
+		It("should return an empty identity when a GCP provider spec has no service accounts", func() {
+			providerSpec := resourcebuilder.GCPProviderSpec().BuildRawExtension()
+
+			cfg, err := gcpProviderConfigFromRawExtension(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+
+			cfg.ServiceAccounts = nil
+			Expect(setProviderSpecValue(providerSpec, cfg)).To(Succeed())
+
+			identity, err := ProviderSpecCloudIdentity(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(identity).To(BeEmpty())
+		})
+
+		It("should return an error for a VSphere provider spec", func() {
+			providerSpec := resourcebuilder.VSphereProviderSpec().BuildRawExtension()
+
+			_, err := ProviderSpecCloudIdentity(providerSpec)
+			Expect(err).To(MatchError(ErrCloudIdentityNotSupported))
+		})
+	})
+
+	Context("GetAzureProviderSpecFaultDomain", func() {
+		It("should return the fault domain of an Azure provider spec", func() {
+			providerSpec := resourcebuilder.AzureProviderSpec().BuildRawExtension()
+
+			fields, err := rawProviderSpecFields(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+
+			fields[azurePlatformFaultDomainField] = 1
+			Expect(setRawProviderSpecFields(providerSpec, fields)).To(Succeed())
+
+			faultDomain, err := GetAzureProviderSpecFaultDomain(providerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(faultDomain).To(Equal(1))
+		})
+
+		It("should return an error when the fault domain is not reported", func() {
+			providerSpec := resourcebuilder.AzureProviderSpec().BuildRawExtension()
+
+			_, err := GetAzureProviderSpecFaultDomain(providerSpec)
+			Expect(err).To(MatchError(ErrNoAzureFaultDomain))
+		})
+
+		It("should return an error for a non-Azure provider spec", func() {
+			providerSpec := resourcebuilder.AWSProviderSpec().BuildRawExtension()
+
+			_, err := GetAzureProviderSpecFaultDomain(providerSpec)
+			Expect(err).To(MatchError(errUnsupportedPlatform))
+		})
+	})
+})
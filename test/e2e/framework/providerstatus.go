@@ -0,0 +1,43 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+)
+
+// GetMachineHost returns the identifier of the underlying host, or hypervisor, that the machine's
+// instance is running on, as reported by the provider status of the machine.
+// The boolean return value reports whether the platform exposes the host in the provider status.
+// The AWS, Azure and GCP provider statuses do not expose the underlying host, as host level placement
+// is managed by the cloud provider on these platforms.
+func GetMachineHost(machine machinev1beta1.Machine) (string, bool, error) {
+	providerConfig, err := providerConfigFromRawExtension(machine.Spec.ProviderSpec.Value)
+	if err != nil {
+		return "", false, err
+	}
+
+	switch providerConfig.Type() {
+	case configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType:
+		return "", false, nil
+	default:
+		return "", false, fmt.Errorf("%w: %s", errUnsupportedPlatform, providerConfig.Type())
+	}
+}
@@ -38,6 +38,19 @@ const (
 
 	// ControlPlaneMachineSetName is the name of the control plane machine set in all clusters.
 	ControlPlaneMachineSetName = "cluster"
+
+	// ControlPlaneMachineSetOriginAnnotationKey is the annotation the operator uses to record
+	// the origin of the control plane machine set.
+	ControlPlaneMachineSetOriginAnnotationKey = "machine.openshift.io/control-plane-machine-set-origin"
+
+	// ControlPlaneMachineSetOriginGenerated is the origin annotation value for a control plane
+	// machine set generated by the operator.
+	ControlPlaneMachineSetOriginGenerated = "generated"
+
+	// ReconcileTriggerAnnotationKey is the annotation bumped on the control plane machine set to force
+	// the operator to reconcile it. The operator compares only the spec of the control plane machine set
+	// against the machines, so changes to this annotation never cause a rollout.
+	ReconcileTriggerAnnotationKey = "e2e.machine.openshift.io/reconcile-trigger"
 )
 
 var (
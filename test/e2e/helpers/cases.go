@@ -18,20 +18,69 @@ package helpers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/pointer"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
 
+	configv1 "github.com/openshift/api/config/v1"
 	machinev1 "github.com/openshift/api/machine/v1"
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/cluster-control-plane-machine-set-operator/pkg/machineproviders"
+	"github.com/openshift/cluster-control-plane-machine-set-operator/pkg/machineproviders/providers/openshift/machine/v1beta1/providerconfig"
 	"github.com/openshift/cluster-control-plane-machine-set-operator/test/e2e/framework"
 )
 
+const (
+	// machineAPIOperatorDeploymentName is the name of the deployment running the Machine API operator.
+	machineAPIOperatorDeploymentName = "machine-api-operator"
+
+	// machineAPIControllersDeploymentName is the name of the deployment running the Machine API controllers.
+	machineAPIControllersDeploymentName = "machine-api-controllers"
+
+	// operatorDeploymentName is the name of the deployment running the control plane machine set operator.
+	operatorDeploymentName = "control-plane-machine-set-operator"
+
+	// webhookServingCertSecretName is the name of the secret holding the serving certificate of the operator webhook.
+	// The secret is generated by the service CA operator, and regenerated when it is deleted.
+	webhookServingCertSecretName = "control-plane-machine-set-operator-tls"
+
+	// kubeAPIServerNamespace is the namespace in which the kube-apiserver serving certificates are stored.
+	kubeAPIServerNamespace = "openshift-kube-apiserver"
+
+	// machineReplacementsTotalMetricName is the name of the counter of machines created by the operator.
+	machineReplacementsTotalMetricName = "control_plane_machine_set_machine_replacements_total"
+
+	// deletionBlockingFinalizer is added to machines by the tests to hold them in the Deleting phase.
+	deletionBlockingFinalizer = "e2e.machine.openshift.io/block-deletion"
+)
+
+var (
+	// kubeAPIServerServingCertSecrets are the kube-apiserver serving certificates used by in-cluster clients.
+	// The service network certificate serves requests to the kubernetes service, and the internal load balancer
+	// certificate serves requests to the internal API endpoint.
+	kubeAPIServerServingCertSecrets = []string{
+		"service-network-serving-certkey",
+		"internal-loadbalancer-serving-certkey",
+	}
+)
+
 // ItShouldHaveAnActiveControlPlaneMachineSet returns an It that checks
 // there is an active control plane machine set installed within the cluster.
 func ItShouldHaveAnActiveControlPlaneMachineSet(testFramework framework.Framework) {
@@ -53,6 +102,7 @@ type ControlPlaneMachineSetRegenerationTestOptions struct {
 	OriginalProviderSpec machinev1beta1.ProviderSpec
 	UpdatedProviderSpec  machinev1beta1.ProviderSpec
 	UID                  types.UID
+	Generated            bool
 	Index                int
 }
 
@@ -66,6 +116,7 @@ func ItShouldPerformARollingUpdate(opts *RollingUpdatePeriodicTestOptions) {
 		testFramework := opts.TestFramework
 		k8sClient := testFramework.GetClient()
 		ctx := testFramework.GetContext()
+		rolloutStart := time.Now()
 
 		cpms := &machinev1.ControlPlaneMachineSet{}
 		Expect(k8sClient.Get(ctx, testFramework.ControlPlaneMachineSetKey(), cpms)).To(Succeed(), "control plane machine set should exist")
@@ -87,6 +138,14 @@ func ItShouldPerformARollingUpdate(opts *RollingUpdatePeriodicTestOptions) {
 			return CheckReplicasDoesNotExceedSurgeCapacity(rolloutCtx)
 		})
 
+		framework.Async(wg, cancel, func() bool {
+			return CheckTotalReplicasNeverExceedDesiredPlusOne(testFramework, rolloutCtx)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckReadyReplicasNeverBelowQuorum(testFramework, rolloutCtx)
+		})
+
 		framework.Async(wg, cancel, func() bool {
 			return WaitForControlPlaneMachineSetDesiredReplicas(rolloutCtx, cpms.DeepCopy())
 		})
@@ -95,12 +154,32 @@ func ItShouldPerformARollingUpdate(opts *RollingUpdatePeriodicTestOptions) {
 			return checkRolloutProgress(testFramework, rolloutCtx)
 		})
 
+		framework.Async(wg, cancel, func() bool {
+			return CheckEtcdLeaderReplacedLast(testFramework, rolloutCtx)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckAPIServerLeaderTransfersDuringRollout(testFramework, rolloutCtx)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectAtMostOneMachineDeletingAtATime(testFramework, rolloutCtx)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectLeaderStableDuringRollout(testFramework, rolloutCtx)
+		})
+
 		wg.Wait()
 
 		// If there's an error in the context, either it timed out or one of the async checks failed.
 		Expect(rolloutCtx.Err()).ToNot(HaveOccurred(), "rollout should have completed successfully")
 		By("Control plane machine replacement completed successfully")
 
+		Expect(ExpectBalancedDomainDistribution(testFramework)).To(BeTrue(), "control plane machines should be balanced across the failure domains")
+		Expect(ExpectFaultDomainSpread(testFramework)).To(BeTrue(), "control plane machines should be spread across the fault domains")
+		Expect(ExpectLastRolloutTimeUpdated(testFramework, rolloutStart)).To(BeTrue(), "control plane machine set should record the completion of the rollout")
+
 		By("Waiting for the cluster to stabilise after the rollout")
 		stabilisationTimeout := 30 * time.Minute
 		if opts.StabilisationTimeout.Seconds() != 0 {
@@ -116,6 +195,12 @@ func ItShouldPerformARollingUpdate(opts *RollingUpdatePeriodicTestOptions) {
 
 // ItShouldRollingUpdateReplaceTheOutdatedMachine checks that the control plane machine set replaces, via a rolling update,
 // the outdated machine in the given index.
+// While the rollout progresses, the control plane must remain available. The total number of machines must never
+// exceed the desired replicas plus one, the ready replicas must never fall below quorum, the status must account for
+// the surge machine, and the master machine config pool must remain stable. The outdated machine must be deleted
+// with the Replaced deletion reason, and the rollout recorded by the operator metrics.
+// The replacement machine must carry the configuration of the template, such as the encryption settings, security
+// groups, accelerators and cloud identity, and its node must join the cluster as a healthy control plane node.
 func ItShouldRollingUpdateReplaceTheOutdatedMachine(testFramework framework.Framework, index int) {
 	It("should rolling update replace the outdated machine", func() {
 		k8sClient := testFramework.GetClient()
@@ -124,6 +209,9 @@ func ItShouldRollingUpdateReplaceTheOutdatedMachine(testFramework framework.Fram
 		cpms := &machinev1.ControlPlaneMachineSet{}
 		Expect(k8sClient.Get(ctx, testFramework.ControlPlaneMachineSetKey(), cpms)).To(Succeed(), "control plane machine set should exist")
 
+		originalCreation, err := originalMachineCreationForIndex(testFramework, index)
+		Expect(err).ToNot(HaveOccurred(), "should be able to find the original machine for index %d", index)
+
 		// We give the rollout 30 minutes to complete.
 		// We pass this to Eventually and Consistently assertions to ensure that they check
 		// until they pass or until the timeout is reached.
@@ -136,20 +224,105 @@ func ItShouldRollingUpdateReplaceTheOutdatedMachine(testFramework framework.Fram
 			return CheckReplicasDoesNotExceedSurgeCapacity(rolloutCtx)
 		})
 
+		framework.Async(wg, cancel, func() bool {
+			return CheckTotalReplicasNeverExceedDesiredPlusOne(testFramework, rolloutCtx)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckReadyReplicasNeverBelowQuorum(testFramework, rolloutCtx)
+		})
+
 		framework.Async(wg, cancel, func() bool {
 			return WaitForControlPlaneMachineSetDesiredReplicas(rolloutCtx, cpms.DeepCopy())
 		})
 
 		framework.Async(wg, cancel, func() bool {
-			return CheckRolloutForIndex(testFramework, rolloutCtx, 1, machinev1.RollingUpdate)
+			return CheckRolloutForIndex(testFramework, rolloutCtx, index, machinev1.RollingUpdate)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectUnavailableReplicasTracksSurge(testFramework, rolloutCtx)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectMachineDeletionReasonAnnotation(testFramework, rolloutCtx, index, machineproviders.MachineDeletionReasonReplaced)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectMachineConfigPoolsStable(testFramework, rolloutCtx)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectEncryptionPreservedAcrossRollout(testFramework, rolloutCtx, index)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectSecurityGroupsPreservedAcrossRollout(testFramework, rolloutCtx, index)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectAcceleratorsPreservedAcrossRollout(testFramework, rolloutCtx, index)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectReplacedMachineHasControlPlaneTaint(testFramework, rolloutCtx, index)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectAuthoritativeAPIPreserved(testFramework, rolloutCtx, index)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectReplacementNewerThanOriginal(testFramework, rolloutCtx, index, originalCreation)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectSubnetMatchesFailureDomain(testFramework, rolloutCtx, index)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectValidProviderIDAfterRollout(testFramework, rolloutCtx, index)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectClusterIDTagPresent(testFramework, rolloutCtx, index)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectOwnerReferenceUIDMatchesCurrentCPMS(testFramework, rolloutCtx, index)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectCloudIdentityAttached(testFramework, rolloutCtx, index)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectReplacedNodeKubeletVersionConsistent(testFramework, rolloutCtx, index)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectNewNodeHasInternalIP(testFramework, rolloutCtx, index)
 		})
 
+		framework.Async(wg, cancel, func() bool {
+			return ExpectReplacedMachineInSameRegion(testFramework, rolloutCtx, index)
+		})
+
+		if expectedKind := providerSpecKindForPlatform(testFramework.GetPlatformType()); expectedKind != "" {
+			framework.Async(wg, cancel, func() bool {
+				return ExpectReplacedMachineProviderSpecKind(testFramework, rolloutCtx, index, expectedKind)
+			})
+		}
+
 		wg.Wait()
 
 		// If there's an error in the context, either it timed out or one of the async checks failed.
 		Expect(rolloutCtx.Err()).ToNot(HaveOccurred(), "rollout should have completed successfully")
 		By("Control plane machine rollout completed successfully")
 
+		Expect(ExpectRolloutMetricExposed(testFramework, machineReplacementsTotalMetricName)).To(BeTrue(),
+			"operator should expose the machine replacements metric")
+
 		By("Waiting for the cluster to stabilise after the rollout")
 		EventuallyClusterOperatorsShouldStabilise(30*time.Minute, 30*time.Second)
 		By("Cluster stabilised after the rollout")
@@ -174,6 +347,8 @@ func ItShouldNotOnDeleteReplaceTheOutdatedMachine(testFramework framework.Framew
 			HaveField("ReadyReplicas", Equal(int32(3))),
 		)))
 
+		Expect(ExpectUpdatedReplicasDecrementsOnDrift(testFramework)).To(BeTrue(), "updated replicas should reflect the machines matching the template")
+
 		// Check the Machine doesn't get deleted by the CPMS. We assume that if the CPMS hasn't removed
 		// the Machine within 1 minute that it won't remove it at all.
 		Consistently(komega.ObjectList(&machinev1beta1.MachineList{})).Should(HaveField("Items", ContainElement(SatisfyAll(
@@ -216,18 +391,173 @@ func ItShouldOnDeleteReplaceTheOutDatedMachineWhenDeleted(testFramework framewor
 			return CheckRolloutForIndex(testFramework, rolloutCtx, index, machinev1.OnDelete)
 		})
 
+		framework.Async(wg, cancel, func() bool {
+			return CheckTotalReplicasNeverExceedDesiredPlusOne(testFramework, rolloutCtx)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return ExpectIndexReusedAfterReplacement(testFramework, index)
+		})
+
 		wg.Wait()
 
 		// If there's an error in the context, either it timed out or one of the async checks failed.
 		Expect(rolloutCtx.Err()).ToNot(HaveOccurred(), "rollout should have completed successfully")
 		By("Control plane machine rollout completed successfully")
 
+		Expect(ExpectRolloutMetricExposed(testFramework, machineReplacementsTotalMetricName)).To(BeTrue(),
+			"operator should expose the machine replacements metric")
+
 		By("Waiting for the cluster to stabilise after the rollout")
 		EventuallyClusterOperatorsShouldStabilise(20*time.Minute, 20*time.Second)
 		By("Cluster stabilised after the rollout")
 	})
 }
 
+// ItShouldStartPendingRolloutOnStrategySwitch checks that, when the update strategy is switched from OnDelete to
+// RollingUpdate while the machine in the given index is outdated, the control plane machine set replaces the
+// outdated machine without it being deleted.
+// This is the inverse of ItShouldNotOnDeleteReplaceTheOutdatedMachine, the pending update should not be lost
+// when the strategy changes. The replacement machine must match the template and the control plane must keep
+// quorum throughout. Once the test completes, the OnDelete strategy is restored.
+func ItShouldStartPendingRolloutOnStrategySwitch(testFramework framework.Framework, index int) {
+	It("should replace the outdated machine when the strategy is switched to RollingUpdate", Offset(1), func() {
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Strategy.Type).To(Equal(machinev1.OnDelete), "control plane machine set should use the OnDelete update strategy")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+
+		desiredReplicas := *cpms.Spec.Replicas
+
+		IncreaseControlPlaneMachineInstanceSize(testFramework, index)
+
+		By("Waiting for the control plane machine set to observe the outdated machine")
+		Eventually(komega.Object(cpms)).Should(HaveField("Status.UpdatedReplicas", Equal(desiredReplicas-1)),
+			"control plane machine set should observe the outdated machine in index %d", index)
+
+		DeferCleanup(func() {
+			EnsureControlPlaneMachineSetUpdateStrategy(testFramework, machinev1.OnDelete)
+		})
+
+		EnsureControlPlaneMachineSetUpdateStrategy(testFramework, machinev1.RollingUpdate)
+
+		rolloutCtx, cancel := context.WithTimeout(testFramework.GetContext(), 30*time.Minute)
+		defer cancel()
+
+		wg := &sync.WaitGroup{}
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckRolloutForIndex(testFramework, rolloutCtx, index, machinev1.RollingUpdate)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckTotalReplicasNeverExceedDesiredPlusOne(testFramework, rolloutCtx)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckReadyReplicasNeverBelowQuorum(testFramework, rolloutCtx)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return WaitForControlPlaneMachineSetDesiredReplicas(rolloutCtx, cpms.DeepCopy())
+		})
+
+		wg.Wait()
+
+		// If there's an error in the context, either it timed out or one of the async checks failed.
+		Expect(rolloutCtx.Err()).ToNot(HaveOccurred(), "rollout should have completed successfully")
+		By("Control plane machine rollout completed successfully")
+
+		By(fmt.Sprintf("Checking the replacement machine in index %d matches the template", index))
+
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+
+		templateInstanceType, err := framework.ProviderSpecInstanceType(cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value)
+		Expect(err).ToNot(HaveOccurred(), "should be able to read the instance type of the template")
+
+		machine, err := machineForIndex(testFramework, index)
+		Expect(err).ToNot(HaveOccurred(), "control plane machine should exist")
+
+		instanceType, err := framework.ProviderSpecInstanceType(machine.Spec.ProviderSpec.Value)
+		Expect(err).ToNot(HaveOccurred(), "should be able to read the instance type of machine %s", machine.Name)
+		Expect(instanceType).To(Equal(templateInstanceType), "replacement machine %s should match the template", machine.Name)
+	})
+}
+
+// ItShouldRespectSurgeWhenMultipleDeletedUnderOnDelete checks that, when the outdated machines in the given indexes
+// are deleted at the same time under the OnDelete strategy, the control plane machine set recreates them without the
+// total number of control plane machines exceeding the desired replicas plus the number of deleted machines.
+// Under OnDelete, the operator creates a replacement for every deleted index in the same reconcile, while the deleted
+// machines remain until the etcd quorum hook is removed, so each deleted machine surges by one.
+// The indexes may be replaced in any order. The control plane must keep quorum throughout.
+func ItShouldRespectSurgeWhenMultipleDeletedUnderOnDelete(testFramework framework.Framework, indexes []int) {
+	It("should respect the surge when multiple outdated machines are deleted", Offset(1), func() {
+		k8sClient := testFramework.GetClient()
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Strategy.Type).To(Equal(machinev1.OnDelete), "control plane machine set should use the OnDelete update strategy")
+
+		machines := []*machinev1beta1.Machine{}
+
+		for _, index := range indexes {
+			IncreaseControlPlaneMachineInstanceSize(testFramework, index)
+
+			machine, err := machineForIndex(testFramework, index)
+			Expect(err).ToNot(HaveOccurred(), "control plane machine should exist for index %d", index)
+
+			machines = append(machines, machine)
+		}
+
+		By(fmt.Sprintf("Deleting the outdated machines in indexes %v", indexes))
+
+		for _, machine := range machines {
+			Expect(k8sClient.Delete(testFramework.GetContext(), machine)).To(Succeed(), "control plane machine %s should be able to be deleted", machine.Name)
+		}
+
+		rolloutCtx, cancel := context.WithTimeout(testFramework.GetContext(), 1*time.Hour)
+		defer cancel()
+
+		wg := &sync.WaitGroup{}
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckTotalReplicasNeverExceedDesiredPlusSurge(testFramework, rolloutCtx, len(machines))
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckReadyReplicasNeverBelowQuorum(testFramework, rolloutCtx)
+		})
+
+		for _, machine := range machines {
+			deleted := machine
+
+			framework.Async(wg, cancel, func() bool {
+				By(fmt.Sprintf("Waiting for the deleted machine %s to be removed", deleted.Name))
+
+				return Eventually(komega.Get(deleted), rolloutCtx).Should(MatchError(ContainSubstring("not found")),
+					"deleted machine %s should be removed from the cluster", deleted.Name)
+			})
+		}
+
+		framework.Async(wg, cancel, func() bool {
+			return WaitForControlPlaneMachineSetDesiredReplicas(rolloutCtx, cpms.DeepCopy())
+		})
+
+		wg.Wait()
+
+		// If there's an error in the context, either it timed out or one of the async checks failed.
+		Expect(rolloutCtx.Err()).ToNot(HaveOccurred(), "recreation should have completed successfully")
+		By("Control plane machines recreated successfully")
+
+		for i, index := range indexes {
+			machine, err := machineForIndex(testFramework, index)
+			Expect(err).ToNot(HaveOccurred(), "control plane machine should exist for index %d", index)
+			Expect(machine.UID).ToNot(Equal(machines[i].UID), "machine in index %d should have been recreated", index)
+			Expect(machine.Status.Phase).To(HaveValue(Equal("Running")), "recreated machine %s should be running", machine.Name)
+		}
+	})
+}
+
 // ItShouldUninstallTheControlPlaneMachineSet checks that the control plane machine set is correctly uninstalled
 // when a deletion is triggered, without triggering control plane machines changes.
 func ItShouldUninstallTheControlPlaneMachineSet(testFramework framework.Framework) {
@@ -240,6 +570,20 @@ func ItShouldUninstallTheControlPlaneMachineSet(testFramework framework.Framewor
 	})
 }
 
+// ItShouldRemoveTheFinalizerOnUninstall checks that, when the active control plane machine set is deleted,
+// the operator removes its finalizer and the deletion completes.
+// The control plane machine set is reactivated once the test completes.
+func ItShouldRemoveTheFinalizerOnUninstall(testFramework framework.Framework) {
+	It("should remove the finalizer when the control plane machine set is deleted", func() {
+		DeferCleanup(func() {
+			EnsureActiveControlPlaneMachineSet(testFramework)
+		})
+
+		Expect(ExpectCPMSFinalizerRemovedOnDelete(testFramework)).To(BeTrue(), "control plane machine set deletion should complete")
+		ExpectControlPlaneMachinesWithoutDeletionTimestamp(testFramework)
+	})
+}
+
 // ItShouldHaveTheControlPlaneMachineSetReplicasUpdated checks that the control plane machine set replicas are updated.
 func ItShouldHaveTheControlPlaneMachineSetReplicasUpdated(testFramework framework.Framework) {
 	It("should have the control plane machine set replicas up to date", func() {
@@ -261,6 +605,8 @@ func ItShouldHaveTheControlPlaneMachineSetReplicasUpdated(testFramework framewor
 			HaveField("Status.ReadyReplicas", Equal(desiredReplicas)),
 			HaveField("Status.UnavailableReplicas", Equal(int32(0))),
 		), "control plane machine set replicas should be up to date")
+
+		ExpectNilReplicasDefaulted(testFramework)
 	})
 }
 
@@ -280,6 +626,10 @@ func ItShouldNotCauseARollout(testFramework framework.Framework) {
 		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
 		desiredReplicas := *cpms.Spec.Replicas
 
+		// Force a reconcile so that the check below observes the result of a reconcile
+		// rather than relying on the operator having reconciled since the last change.
+		Expect(testFramework.TriggerCPMSReconcile()).To(Succeed(), "should be able to trigger a control plane machine set reconcile")
+
 		// We expect the control plane machine set replicas to consistently
 		// be up to date, which should mean no rollout has been triggered.
 		// Here assume that if no changes happen to the replica counts
@@ -296,49 +646,3122 @@ func ItShouldNotCauseARollout(testFramework framework.Framework) {
 	})
 }
 
-// ItShouldCheckAllControlPlaneMachinesHaveCorrectOwnerReferences checks that all the control plane machines
-// have the correct owner references set.
-func ItShouldCheckAllControlPlaneMachinesHaveCorrectOwnerReferences(testFramework framework.Framework) {
-	It("should find all control plane machines to have owner references set", func() {
-		// Check that all the control plane machines are owned.
-		ExpectControlPlaneMachinesOwned(testFramework)
+// ItShouldNotRollOnCloudNormalizedFields checks that the control plane machine set does not roll out machines
+// because of fields populated by the cloud provider once a machine has been created.
+// The drift detection of the control plane machine set only compares the provider spec of each machine, with the
+// failure domain for its index injected, against the template provider spec.
+// Fields written back once the instance exists, such as the machine provider ID, status addresses and provider
+// status (including resolved instance IDs and instance state), are excluded from this comparison.
+// The test checks that these fields are populated on every control plane machine, and that, after a reconcile,
+// the control plane machine set consistently reports all replicas as updated without deleting any machine.
+func ItShouldNotRollOnCloudNormalizedFields(testFramework framework.Framework) {
+	It("should not roll out machines because of cloud populated fields", func() {
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
 
-		// Check that no control plane machine is garbage collected (being deleted),
-		// as this may happen if incorrect owner references are added.
-		ConsistentlyControlPlaneMachinesWithoutDeletionTimestamp(testFramework)
+		desiredReplicas := *cpms.Spec.Replicas
 
-		// Check that the operators are stable.
-		EventuallyClusterOperatorsShouldStabilise()
+		By("Checking the control plane machines have fields populated by the cloud provider")
+
+		machineList := &machinev1beta1.MachineList{}
+		Expect(komega.List(machineList, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels()))()).To(Succeed(), "should be able to list control plane machines")
+		Expect(machineList.Items).To(HaveEach(SatisfyAll(
+			HaveField("Spec.ProviderID", HaveValue(Not(BeEmpty()))),
+			HaveField("Status.Addresses", Not(BeEmpty())),
+			HaveField("Status.ProviderStatus", Not(BeNil())),
+		)), "control plane machines should have fields populated by the cloud provider")
+
+		Expect(testFramework.TriggerCPMSReconcile()).To(Succeed(), "should be able to trigger a control plane machine set reconcile")
+
+		By("Checking the control plane machine set consistently reports all replicas as updated")
+		Consistently(komega.Object(cpms)).Should(SatisfyAll(
+			HaveField("Status.Replicas", Equal(desiredReplicas)),
+			HaveField("Status.UpdatedReplicas", Equal(desiredReplicas)),
+		), "control plane machine set should not observe drift from cloud populated fields")
+
+		ExpectControlPlaneMachinesWithoutDeletionTimestamp(testFramework)
 	})
 }
 
-// ItShouldPerformControlPlaneMachineSetRegeneration checks that an inactive control plane machine set
-// is regenerated if the reference machine spec changes.
-func ItShouldPerformControlPlaneMachineSetRegeneration(opts *ControlPlaneMachineSetRegenerationTestOptions, gomegaArgs ...interface{}) {
-	It("should perform control plane machine set regeneration", func() {
-		Expect(opts.TestFramework).ToNot(BeNil(), "test framework should not be nil")
-		ctx := opts.TestFramework.GetContext()
-		cpms := opts.TestFramework.NewEmptyControlPlaneMachineSet()
+// ItShouldBeginManagingMachinesWhenActivated checks that activating an inactive control plane machine set causes it
+// to begin managing the control plane machines, without rolling out any machine that already matches the template.
+// The control plane machine set is returned to its original state once the test completes.
+func ItShouldBeginManagingMachinesWhenActivated(testFramework framework.Framework) {
+	It("should begin managing the control plane machines when activated", func() {
+		ctx, cancel := context.WithTimeout(testFramework.GetContext(), 10*time.Minute)
+		defer cancel()
 
-		// Check that the control plane machine set is regenerated.
-		WaitForControlPlaneMachineSetRemovedOrRecreated(ctx, opts.TestFramework, opts.UID)
-		EnsureInactiveControlPlaneMachineSet(opts.TestFramework)
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
 
-		rawExtension, err := opts.TestFramework.ConvertToControlPlaneMachineSetProviderSpec(opts.UpdatedProviderSpec)
-		Expect(err).NotTo(HaveOccurred())
+		desiredReplicas := *cpms.Spec.Replicas
+		originalState := cpms.Spec.State
 
-		By("Checking the control plane machine set reports 1 updated machine, 2 needing update")
-		Eventually(komega.Object(cpms)).Should(
-			SatisfyAll(
-				HaveField("Status.UpdatedReplicas", Equal(int32(1))),
-				HaveField("Status.UnavailableReplicas", Equal(int32(0))),
-			),
-		)
+		DeferCleanup(func() {
+			if originalState == machinev1.ControlPlaneMachineSetStateInactive {
+				EnsureInactiveControlPlaneMachineSet(testFramework)
+			}
+		})
 
-		By("Checking the control plane machine set has the correct providerSpec")
-		Expect(cpms).To(
-			HaveField("Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value.Raw",
-				MatchJSON(rawExtension.Raw)),
-		)
+		By("Activating the control plane machine set")
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.State = machinev1.ControlPlaneMachineSetStateActive
+		})).Should(Succeed(), "control plane machine set should be able to be activated")
+
+		Expect(WaitForControlPlaneMachineSetActive(testFramework, ctx)).To(BeTrue(), "control plane machine set should begin managing the control plane machines")
+
+		By("Checking the control plane machine set does not roll out the up to date machines")
+		Consistently(komega.Object(cpms)).Should(SatisfyAll(
+			HaveField("Status.Replicas", Equal(desiredReplicas)),
+			HaveField("Status.UpdatedReplicas", Equal(desiredReplicas)),
+		), "control plane machine set should not roll out machines matching the template")
+
+		ExpectControlPlaneMachinesWithoutDeletionTimestamp(testFramework)
 	})
 }
+
+// ItShouldNotRollOnActivationWhenUpToDate checks that activating a generated control plane machine set, whose
+// template matches the existing control plane machines, does not replace any of the machines.
+// This is the common activation path for clusters, and must not be disruptive. Each control plane machine must keep its
+// UID while the control plane machine set adopts it.
+// The control plane machine set is returned to its original state once the test completes.
+func ItShouldNotRollOnActivationWhenUpToDate(testFramework framework.Framework) {
+	It("should not roll out any machine when activated with an up to date template", Offset(1), func() {
+		ctx, cancel := context.WithTimeout(testFramework.GetContext(), 10*time.Minute)
+		defer cancel()
+
+		generated, err := testFramework.IsControlPlaneMachineSetGenerated()
+		Expect(err).ToNot(HaveOccurred(), "should be able to determine whether the control plane machine set was generated")
+
+		if !generated {
+			Skip("Skipping as the control plane machine set was not generated by the operator")
+		}
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+
+		desiredReplicas := *cpms.Spec.Replicas
+		originalState := cpms.Spec.State
+
+		machineList := &machinev1beta1.MachineList{}
+		machineSelector := runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())
+		Expect(komega.List(machineList, machineSelector)()).To(Succeed(), "should be able to list machines")
+
+		originalUIDs := []types.UID{}
+
+		for _, machine := range machineList.Items {
+			originalUIDs = append(originalUIDs, machine.UID)
+		}
+
+		DeferCleanup(func() {
+			if originalState == machinev1.ControlPlaneMachineSetStateInactive {
+				EnsureInactiveControlPlaneMachineSet(testFramework)
+			}
+		})
+
+		By("Activating the control plane machine set")
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.State = machinev1.ControlPlaneMachineSetStateActive
+		})).Should(Succeed(), "control plane machine set should be able to be activated")
+
+		Expect(WaitForControlPlaneMachineSetActive(testFramework, ctx)).To(BeTrue(), "control plane machine set should begin managing the control plane machines")
+
+		By("Checking the control plane machine set reports all replicas as updated")
+		Expect(komega.Object(cpms)()).To(SatisfyAll(
+			HaveField("Status.Replicas", Equal(desiredReplicas)),
+			HaveField("Status.UpdatedReplicas", Equal(desiredReplicas)),
+		), "control plane machine set should observe the machines as up to date")
+
+		By("Checking the control plane machines are consistently not replaced")
+		Consistently(komega.ObjectList(&machinev1beta1.MachineList{}, machineSelector)).Should(HaveField("Items",
+			WithTransform(func(machines []machinev1beta1.Machine) []types.UID {
+				uids := []types.UID{}
+
+				for _, machine := range machines {
+					uids = append(uids, machine.UID)
+				}
+
+				return uids
+			}, ConsistOf(originalUIDs)),
+		), "control plane machines should keep their UIDs when the control plane machine set is activated")
+
+		ExpectControlPlaneMachinesWithoutDeletionTimestamp(testFramework)
+	})
+}
+
+// ItShouldNotRollAfterProviderSpecDefaulting checks that removing a field, which the Machine API defaults when
+// machines are created, from the control plane machine set template does not cause a perpetual rollout.
+// The defaulted field is the cloud credentials secret, which the Machine API defaults to aws-cloud-credentials on
+// AWS, azure-cloud-credentials on Azure and gcp-cloud-credentials on GCP.
+// When the existing machines already use the default secret, the defaulted template is equal to the machines,
+// and so no machine should be replaced.
+// Once the test completes, the original template provider spec is restored.
+func ItShouldNotRollAfterProviderSpecDefaulting(testFramework framework.Framework) {
+	It("should not roll out any machine when the template omits defaulted fields", Offset(1), func() {
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+
+		desiredReplicas := *cpms.Spec.Replicas
+		originalProviderSpec := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec
+
+		secretName, err := framework.GetProviderSpecCredentialsSecret(originalProviderSpec.Value)
+		Expect(err).ToNot(HaveOccurred(), "should be able to read the credentials secret from the provider spec")
+
+		defaultSecretName := defaultCredentialsSecretName(testFramework.GetPlatformType())
+		if secretName != defaultSecretName {
+			Skip(fmt.Sprintf("Skipping as the credentials secret %q is not the Machine API default %q", secretName, defaultSecretName))
+		}
+
+		machineList := &machinev1beta1.MachineList{}
+		machineSelector := runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())
+		Expect(komega.List(machineList, machineSelector)()).To(Succeed(), "should be able to list machines")
+
+		originalUIDs := []types.UID{}
+
+		for _, machine := range machineList.Items {
+			originalUIDs = append(originalUIDs, machine.UID)
+		}
+
+		updatedProviderSpec := originalProviderSpec.DeepCopy()
+		Expect(framework.ClearProviderSpecCredentialsSecret(updatedProviderSpec.Value)).To(Succeed(), "provider spec should be updated without the credentials secret")
+
+		By("Removing the credentials secret from the control plane machine set template")
+
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = *updatedProviderSpec
+		})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+		DeferCleanup(func() {
+			By("Restoring the original control plane machine set provider spec")
+
+			Eventually(komega.Update(cpms, func() {
+				cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = originalProviderSpec
+			})).Should(Succeed(), "control plane machine set should be able to be restored")
+		})
+
+		By("Checking the control plane machine set consistently reports all replicas as updated")
+		Consistently(komega.Object(cpms)).Should(HaveField("Status.UpdatedReplicas", Equal(desiredReplicas)),
+			"control plane machine set should observe the machines as up to date with the defaulted template")
+
+		By("Checking the control plane machines are not replaced")
+		Expect(komega.ObjectList(&machinev1beta1.MachineList{}, machineSelector)()).To(HaveField("Items",
+			WithTransform(func(machines []machinev1beta1.Machine) []types.UID {
+				uids := []types.UID{}
+
+				for _, machine := range machines {
+					uids = append(uids, machine.UID)
+				}
+
+				return uids
+			}, ConsistOf(originalUIDs)),
+		), "control plane machines should keep their UIDs when the template omits defaulted fields")
+
+		ExpectControlPlaneMachinesWithoutDeletionTimestamp(testFramework)
+	})
+}
+
+// defaultCredentialsSecretName returns the name of the cloud credentials secret the Machine API
+// defaults machines to use on the given platform.
+func defaultCredentialsSecretName(platform configv1.PlatformType) string {
+	switch platform {
+	case configv1.AWSPlatformType:
+		return "aws-cloud-credentials"
+	case configv1.AzurePlatformType:
+		return "azure-cloud-credentials"
+	case configv1.GCPPlatformType:
+		return "gcp-cloud-credentials"
+	default:
+		return ""
+	}
+}
+
+// ItShouldReactToInfrastructureChange checks that the control plane machine set agrees with the cluster
+// Infrastructure resource.
+// The operator only reads the platform type from the Infrastructure resource, which cannot be changed once a
+// cluster is installed, and so the operator does not watch the resource for changes. Failure domains are derived
+// from the existing control plane machines and machine sets rather than the Infrastructure resource.
+// As the relevant fields cannot be changed in test, this checks instead that the operator read the Infrastructure
+// resource correctly: the failure domains and template are for the platform of the cluster, and, where the
+// Infrastructure resource reports a region, the template provider spec is within that region.
+func ItShouldReactToInfrastructureChange(testFramework framework.Framework) {
+	It("should use the platform from the infrastructure resource", Offset(1), func() {
+		infra := &configv1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster",
+			},
+		}
+		Expect(komega.Get(infra)()).To(Succeed(), "infrastructure resource should exist")
+		Expect(infra.Status.PlatformStatus).ToNot(BeNil(), "infrastructure resource should report the platform status")
+
+		platformType := infra.Status.PlatformStatus.Type
+		Expect(platformType).To(Equal(testFramework.GetPlatformType()), "framework should use the platform from the infrastructure resource")
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+
+		generated, err := testFramework.IsControlPlaneMachineSetGenerated()
+		Expect(err).ToNot(HaveOccurred(), "should be able to determine whether the control plane machine set was generated")
+
+		if generated {
+			Expect(infra.Spec.PlatformSpec.Type).To(Equal(platformType), "generated control plane machine set should be for the platform in the infrastructure spec")
+		}
+
+		if failureDomains := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains; failureDomains.Platform != "" {
+			Expect(failureDomains.Platform).To(Equal(platformType), "failure domains should be for the platform of the cluster")
+		}
+
+		infraRegion := infrastructureRegion(infra)
+		if infraRegion == "" {
+			return
+		}
+
+		region, err := framework.ProviderSpecRegion(cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value)
+		Expect(err).ToNot(HaveOccurred(), "should be able to read the region from the template provider spec")
+		Expect(region).To(Equal(infraRegion), "template provider spec should be in the region of the cluster")
+	})
+}
+
+// infrastructureRegion returns the region reported in the platform status of the Infrastructure resource.
+// Not all platforms report a region, in which case an empty string is returned.
+func infrastructureRegion(infra *configv1.Infrastructure) string {
+	platformStatus := infra.Status.PlatformStatus
+
+	switch {
+	case platformStatus.AWS != nil:
+		return platformStatus.AWS.Region
+	case platformStatus.GCP != nil:
+		return platformStatus.GCP.Region
+	default:
+		return ""
+	}
+}
+
+// ItShouldHandleLongLabelValues checks that the control plane machine set generates a valid name for a replacement
+// machine when the machine template carries label values of the maximum length.
+// Generated machine names combine the cluster ID, the machine role, a random suffix and the index, and are used as
+// instance names, and hostnames, on several platforms, so they must be valid DNS labels no longer than 63 characters.
+// The label is removed from the template once the test completes. Template labels are not compared when determining
+// whether a machine needs an update, so this does not cause a rollout.
+func ItShouldHandleLongLabelValues(testFramework framework.Framework) {
+	It("should generate a valid machine name when the template has long label values", Offset(1), func() {
+		const longLabelKey = "e2e.openshift.io/long-label-value"
+
+		index := 1
+		longLabelValue := strings.Repeat("a", validation.LabelValueMaxLength)
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Strategy.Type).To(Equal(machinev1.RollingUpdate), "control plane machine set should use the RollingUpdate update strategy")
+
+		By("Adding a label with a value of the maximum length to the control plane machine set template")
+
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.ObjectMeta.Labels[longLabelKey] = longLabelValue
+		})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+		DeferCleanup(func() {
+			By("Removing the long label from the control plane machine set template")
+
+			Eventually(komega.Update(cpms, func() {
+				delete(cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.ObjectMeta.Labels, longLabelKey)
+			})).Should(Succeed(), "control plane machine set should be able to be restored")
+		})
+
+		IncreaseControlPlaneMachineInstanceSize(testFramework, index)
+
+		rolloutCtx, cancel := context.WithTimeout(testFramework.GetContext(), 30*time.Minute)
+		defer cancel()
+
+		Expect(EventuallyIndexIsBeingReplaced(rolloutCtx, index)).To(BeTrue(), "index %d should be replaced", index)
+
+		_, newMachine, ok := getOldAndNewMachineForIndex(rolloutCtx, testFramework, index)
+		Expect(ok).To(BeTrue(), "should be able to get the replacement machine for index %d", index)
+
+		By(fmt.Sprintf("Checking the replacement machine name %s is valid", newMachine.Name))
+
+		Expect(validation.IsDNS1123Label(newMachine.Name)).To(BeEmpty(), "replacement machine name %s should be a valid DNS label", newMachine.Name)
+		Expect(newMachine.Name).To(HaveSuffix("-%d", index), "replacement machine name %s should end with its index", newMachine.Name)
+		Expect(newMachine.Labels).To(HaveKeyWithValue(longLabelKey, longLabelValue), "replacement machine %s should carry the long label", newMachine.Name)
+
+		Expect(WaitForControlPlaneMachineSetDesiredReplicas(rolloutCtx, cpms.DeepCopy())).To(BeTrue(), "control plane machine set should reach the desired replicas")
+	})
+}
+
+// ItShouldReAdoptOrphanedMachine checks that the control plane machine set re-adopts a machine in the given index
+// whose controller owner reference has been removed, rather than creating a duplicate machine for the index.
+func ItShouldReAdoptOrphanedMachine(testFramework framework.Framework, index int) {
+	It("should re-adopt a machine that lost its owner reference", Offset(1), func() {
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+
+		machine, err := machineForIndex(testFramework, index)
+		Expect(err).ToNot(HaveOccurred(), "control plane machine should exist")
+		Expect(machine).ToNot(BeNil(), "control plane machine should exist in index %d", index)
+
+		originalUID := machine.UID
+
+		By(fmt.Sprintf("Removing the owner references from machine %s", machine.Name))
+
+		Eventually(komega.Update(machine, func() {
+			machine.SetOwnerReferences(nil)
+		})).Should(Succeed(), "should be able to remove the owner references from the machine")
+
+		Expect(testFramework.TriggerCPMSReconcile()).To(Succeed(), "should be able to trigger a control plane machine set reconcile")
+
+		By(fmt.Sprintf("Waiting for the control plane machine set to re-adopt machine %s", machine.Name))
+
+		Eventually(komega.Object(machine)).Should(
+			HaveField("ObjectMeta.OwnerReferences", ContainElement(SatisfyAll(
+				HaveField("UID", Equal(cpms.UID)),
+				HaveField("Controller", HaveValue(BeTrue())),
+			))), "control plane machine set should re-adopt the orphaned machine",
+		)
+
+		By(fmt.Sprintf("Checking no duplicate machine is created in index %d", index))
+
+		Consistently(func() ([]machinev1beta1.Machine, error) {
+			return machinesForIndex(testFramework, index)
+		}, 2*time.Minute, 10*time.Second).Should(SatisfyAll(
+			HaveLen(1),
+			HaveEach(SatisfyAll(
+				HaveField("ObjectMeta.UID", Equal(originalUID)),
+				HaveField("ObjectMeta.DeletionTimestamp", BeNil()),
+			)),
+		), "index %d should only contain the re-adopted machine", index)
+	})
+}
+
+// ItShouldRetryOnMachineUpdateConflict checks that the control plane machine set converges when the machine in the
+// given index is modified concurrently while the operator is updating it.
+// The owner references of the machine are removed, and while the operator re-adopts the machine, the machine is
+// updated continuously so that the operator only ever holds a copy of the machine with a stale resourceVersion.
+// The operator must eventually re-adopt the machine, without creating a duplicate machine for the index, and without
+// reverting the concurrent updates to the machine.
+func ItShouldRetryOnMachineUpdateConflict(testFramework framework.Framework, index int) {
+	It("should converge when a machine is updated concurrently with the operator", Offset(1), func() {
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+
+		machine, err := machineForIndex(testFramework, index)
+		Expect(err).ToNot(HaveOccurred(), "control plane machine should exist")
+		Expect(machine).ToNot(BeNil(), "control plane machine should exist in index %d", index)
+
+		originalUID := machine.UID
+
+		DeferCleanup(func() {
+			By(fmt.Sprintf("Removing the conflict annotation from machine %s", machine.Name))
+
+			Eventually(komega.Update(machine, func() {
+				delete(machine.Annotations, e2eConflictAnnotation)
+			})).Should(Succeed(), "should be able to remove the conflict annotation from the machine")
+		})
+
+		By(fmt.Sprintf("Removing the owner references from machine %s", machine.Name))
+
+		Eventually(komega.Update(machine, func() {
+			machine.SetOwnerReferences(nil)
+		})).Should(Succeed(), "should be able to remove the owner references from the machine")
+
+		By(fmt.Sprintf("Continuously updating machine %s while the control plane machine set re-adopts it", machine.Name))
+
+		conflictCtx, cancel := context.WithTimeout(testFramework.GetContext(), 5*time.Minute)
+		defer cancel()
+
+		written := make(chan int, 1)
+
+		go func() {
+			defer GinkgoRecover()
+
+			written <- induceMachineUpdateConflicts(conflictCtx, machine.DeepCopy())
+		}()
+
+		Expect(testFramework.TriggerCPMSReconcile()).To(Succeed(), "should be able to trigger a control plane machine set reconcile")
+
+		Eventually(komega.Object(machine), conflictCtx).Should(
+			HaveField("ObjectMeta.OwnerReferences", ContainElement(SatisfyAll(
+				HaveField("UID", Equal(cpms.UID)),
+				HaveField("Controller", HaveValue(BeTrue())),
+			))), "control plane machine set should re-adopt the machine despite the concurrent updates",
+		)
+
+		cancel()
+
+		lastWritten := <-written
+		Expect(lastWritten).To(BeNumerically(">", 0), "should have updated machine %s concurrently with the operator", machine.Name)
+
+		By(fmt.Sprintf("Checking the concurrent updates to machine %s were not reverted", machine.Name))
+
+		Expect(komega.Object(machine)()).To(
+			HaveField("ObjectMeta.Annotations", HaveKeyWithValue(e2eConflictAnnotation, strconv.Itoa(lastWritten))),
+			"the operator should not revert the concurrent updates to the machine",
+		)
+
+		By(fmt.Sprintf("Checking no duplicate machine is created in index %d", index))
+
+		Consistently(func() ([]machinev1beta1.Machine, error) {
+			return machinesForIndex(testFramework, index)
+		}, 2*time.Minute, 10*time.Second).Should(SatisfyAll(
+			HaveLen(1),
+			HaveEach(SatisfyAll(
+				HaveField("ObjectMeta.UID", Equal(originalUID)),
+				HaveField("ObjectMeta.DeletionTimestamp", BeNil()),
+			)),
+		), "index %d should only contain the original machine", index)
+
+		waitCtx, waitCancel := context.WithTimeout(testFramework.GetContext(), 10*time.Minute)
+		defer waitCancel()
+
+		Expect(WaitForControlPlaneMachineSetDesiredReplicas(waitCtx, cpms.DeepCopy())).To(BeTrue(),
+			"control plane machine set should reach the desired replicas")
+	})
+}
+
+// ItShouldRecreateMissingIndexInFiveReplica checks that, in a 5 replica control plane, deleting the machine in
+// index 2 causes the control plane machine set to recreate exactly that index, without disturbing the machines in the
+// other indexes. Once the rollout completes, the indexes must be exactly 0 to 4, with a single machine in each.
+// On clusters without 5 replicas this test is skipped.
+func ItShouldRecreateMissingIndexInFiveReplica(testFramework framework.Framework) {
+	It("should recreate only the missing index in a 5 replica control plane", Offset(1), func() {
+		const (
+			fiveReplicas = 5
+			missingIndex = 2
+		)
+
+		k8sClient := testFramework.GetClient()
+		ctx := testFramework.GetContext()
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+
+		if *cpms.Spec.Replicas != fiveReplicas {
+			Skip(fmt.Sprintf("Skipping as the control plane has %d replicas, %d are required", *cpms.Spec.Replicas, fiveReplicas))
+		}
+
+		Expect(cpms.Spec.Strategy.Type).To(Equal(machinev1.RollingUpdate), "control plane machine set should use the RollingUpdate update strategy")
+
+		machineList := &machinev1beta1.MachineList{}
+		machineSelector := runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())
+		Expect(komega.List(machineList, machineSelector)()).To(Succeed(), "should be able to list machines")
+
+		var untouchedMachines []machinev1beta1.Machine
+
+		for _, machine := range machineList.Items {
+			idx, err := machineIndex(machine)
+			Expect(err).ToNot(HaveOccurred(), "should be able to determine the index of machine %s", machine.Name)
+
+			if idx != missingIndex {
+				untouchedMachines = append(untouchedMachines, machine)
+			}
+		}
+
+		machine, err := machineForIndex(testFramework, missingIndex)
+		Expect(err).ToNot(HaveOccurred(), "control plane machine should exist")
+
+		By(fmt.Sprintf("Deleting machine %s in index %d", machine.Name, missingIndex))
+		Expect(k8sClient.Delete(ctx, machine)).To(Succeed(), "control plane machine should be able to be deleted")
+
+		rolloutCtx, cancel := context.WithTimeout(ctx, 1*time.Hour)
+		defer cancel()
+
+		wg := &sync.WaitGroup{}
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckRolloutForIndex(testFramework, rolloutCtx, missingIndex, machinev1.RollingUpdate)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckTotalReplicasNeverExceedDesiredPlusOne(testFramework, rolloutCtx)
+		})
+
+		wg.Wait()
+
+		// If there's an error in the context, either it timed out or one of the async checks failed.
+		Expect(rolloutCtx.Err()).ToNot(HaveOccurred(), "index %d should have been recreated successfully", missingIndex)
+		Expect(WaitForControlPlaneMachineSetDesiredReplicas(ctx, cpms.DeepCopy())).To(BeTrue(), "control plane machine set should reach the desired replicas")
+
+		By("Checking the machines in the other indexes were not disturbed")
+
+		for i := range untouchedMachines {
+			untouchedMachine := untouchedMachines[i].DeepCopy()
+
+			Expect(komega.Object(untouchedMachine)()).To(SatisfyAll(
+				HaveField("ObjectMeta.UID", Equal(untouchedMachines[i].UID)),
+				HaveField("ObjectMeta.DeletionTimestamp", BeNil()),
+			), "machine %s should not be replaced", untouchedMachine.Name)
+		}
+
+		By("Checking each of the indexes 0 to 4 has exactly one machine")
+		Expect(komega.List(machineList, machineSelector)()).To(Succeed(), "should be able to list machines")
+
+		indexCounts, err := extractMachineIndexCounts(machineList.Items)
+		Expect(err).ToNot(HaveOccurred(), "should be able to determine the machine indexes")
+		Expect(indexCounts).To(Equal(map[int]int{0: 1, 1: 1, 2: 1, 3: 1, 4: 1}), "indexes should be exactly 0 to 4, with one machine each")
+
+		By("Waiting for the cluster to stabilise after the rollout")
+		EventuallyClusterOperatorsShouldStabilise(30*time.Minute, 30*time.Second)
+	})
+}
+
+// ItShouldScaleDownHighestIndexesFirst checks that scaling a 5 replica control plane down to 3 replicas removes the
+// machines in the highest indexes, see ExpectScaleDownRemovesHighestIndexesFirst.
+// The control plane machine set is left with the reduced number of replicas, so this is a disruptive test and is only
+// run when the CPMS_E2E_ENABLE_DISRUPTIVE_TESTS environment variable is set.
+func ItShouldScaleDownHighestIndexesFirst(testFramework framework.Framework) {
+	It("should remove the highest indexes first when scaling down", Offset(1), func() {
+		lookupEnvOrSkip(disruptiveTestsEnvVar)
+
+		scaleDownCtx, cancel := context.WithTimeout(testFramework.GetContext(), 1*time.Hour)
+		defer cancel()
+
+		Expect(ExpectScaleDownRemovesHighestIndexesFirst(testFramework, scaleDownCtx)).To(BeTrue(), "scale down should remove the highest indexes first")
+
+		By("Waiting for the cluster to stabilise after the scale down")
+		EventuallyClusterOperatorsShouldStabilise(30*time.Minute, 30*time.Second)
+	})
+}
+
+// ItShouldNotRemoveMachinesBelowQuorum checks that, when a majority of the control plane machines are unhealthy, the
+// operator reports that the control plane is below quorum and does not remove any control plane machine.
+// The unhealthy machines are simulated, see ExpectBelowQuorumCondition, so this is a disruptive test and is only run
+// when the CPMS_E2E_ENABLE_DISRUPTIVE_TESTS environment variable is set.
+func ItShouldNotRemoveMachinesBelowQuorum(testFramework framework.Framework) {
+	It("should not remove control plane machines while below quorum", Offset(1), func() {
+		lookupEnvOrSkip(disruptiveTestsEnvVar)
+
+		Expect(ExpectBelowQuorumCondition(testFramework)).To(BeTrue(), "operator should report the control plane is below quorum and not remove machines")
+
+		By("Waiting for the cluster to stabilise once the machines are healthy")
+		EventuallyClusterOperatorsShouldStabilise(10*time.Minute, 10*time.Second)
+	})
+}
+
+// ItShouldHandleMachineWithoutIndexSuffix checks that the control plane machine set handles a control plane machine
+// whose name does not end in the "-<index>" suffix the machine indexes are derived from.
+// The machine is created from the machine in index 0, so the operator may fall back to the failure domain to determine
+// its index. The operator must either reconcile the machine into the index scheme, by adopting or removing it, or
+// report a Degraded condition explaining why it cannot, and must not restart while doing so, for example due to a panic.
+// Any machine left over is removed once the test completes.
+func ItShouldHandleMachineWithoutIndexSuffix(testFramework framework.Framework) {
+	It("should handle a control plane machine without an index suffix", Offset(1), func() {
+		k8sClient := testFramework.GetClient()
+		ctx := testFramework.GetContext()
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+
+		sourceMachine, err := machineForIndex(testFramework, 0)
+		Expect(err).ToNot(HaveOccurred(), "control plane machine should exist")
+		Expect(sourceMachine).ToNot(BeNil(), "control plane machine should exist in index 0")
+
+		originalRestarts, err := operatorRestartCount(testFramework)
+		Expect(err).ToNot(HaveOccurred(), "should be able to get the operator restart count")
+
+		machine := &machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-e2e-without-index", strings.TrimSuffix(sourceMachine.Name, "-0")),
+				Namespace: sourceMachine.Namespace,
+				Labels:    sourceMachine.Labels,
+			},
+			Spec: *sourceMachine.Spec.DeepCopy(),
+		}
+		machine.Spec.ProviderID = nil
+
+		By(fmt.Sprintf("Creating control plane machine %s without an index suffix", machine.Name))
+
+		Expect(k8sClient.Create(ctx, machine)).To(Succeed(), "should be able to create the machine without an index suffix")
+
+		DeferCleanup(func() {
+			By(fmt.Sprintf("Removing control plane machine %s", machine.Name))
+
+			Expect(runtimeclient.IgnoreNotFound(k8sClient.Delete(ctx, machine))).To(Succeed(), "should be able to delete the machine without an index suffix")
+
+			Eventually(func() bool {
+				return apierrors.IsNotFound(komega.Get(machine)())
+			}, 30*time.Minute, 10*time.Second).Should(BeTrue(), "machine without an index suffix should be removed")
+
+			rolloutCtx, cancel := context.WithTimeout(ctx, 1*time.Hour)
+			defer cancel()
+
+			Expect(WaitForControlPlaneMachineSetDesiredReplicas(rolloutCtx, cpms.DeepCopy())).To(BeTrue(), "control plane machine set should return to the desired replicas")
+		})
+
+		Expect(testFramework.TriggerCPMSReconcile()).To(Succeed(), "should be able to trigger a control plane machine set reconcile")
+
+		By("Waiting for the operator to reconcile the machine into the index scheme, or report why it cannot")
+
+		Eventually(func() (bool, error) {
+			if err := komega.Get(cpms)(); err != nil {
+				return false, err
+			}
+
+			for _, condition := range cpms.Status.Conditions {
+				if condition.Type == "Degraded" && condition.Status == metav1.ConditionTrue && condition.Message != "" {
+					By(fmt.Sprintf("Control plane machine set reported the machine as degraded: %s", condition.Message))
+					return true, nil
+				}
+			}
+
+			if err := komega.Get(machine)(); err != nil {
+				return apierrors.IsNotFound(err), runtimeclient.IgnoreNotFound(err)
+			}
+
+			return metav1.IsControlledBy(machine, cpms) || machine.DeletionTimestamp != nil, nil
+		}, 30*time.Minute, 10*time.Second).Should(BeTrue(), "control plane machine set should adopt, remove, or report the machine without an index suffix")
+
+		By("Checking the operator did not restart while handling the machine")
+
+		Expect(operatorRestartCount(testFramework)).To(Equal(originalRestarts), "operator should not restart while handling the machine without an index suffix")
+	})
+}
+
+// operatorRestartCount returns the total number of container restarts across the operator pods.
+func operatorRestartCount(testFramework framework.Framework) (int32, error) {
+	podList := &corev1.PodList{}
+
+	if err := testFramework.GetClient().List(testFramework.GetContext(), podList,
+		runtimeclient.InNamespace(framework.MachineAPINamespace),
+		runtimeclient.MatchingLabels{"k8s-app": operatorDeploymentName},
+	); err != nil {
+		return 0, fmt.Errorf("could not list operator pods: %w", err)
+	}
+
+	restarts := int32(0)
+
+	for _, pod := range podList.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			restarts += status.RestartCount
+		}
+	}
+
+	return restarts, nil
+}
+
+// ItShouldStopManagingWhenDeactivated checks that setting an active control plane machine set back to inactive
+// stops it from managing the control plane machines, by making the machine in the given index outdated and
+// checking that no rollout occurs.
+// The control plane machine set API may forbid changing the state once it is active, in which case the update is
+// rejected and the test is skipped.
+// The machine and the control plane machine set are returned to their original states once the test completes.
+func ItShouldStopManagingWhenDeactivated(testFramework framework.Framework, index int) {
+	It("should stop managing the control plane machines when deactivated", func() {
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+		Expect(cpms.Spec.State).To(Equal(machinev1.ControlPlaneMachineSetStateActive), "control plane machine set should be active")
+
+		desiredReplicas := *cpms.Spec.Replicas
+
+		By("Deactivating the control plane machine set")
+
+		err := komega.Update(cpms, func() {
+			cpms.Spec.State = machinev1.ControlPlaneMachineSetStateInactive
+		})()
+		if apierrors.IsInvalid(err) {
+			Skip(fmt.Sprintf("Skipping as deactivating the control plane machine set was rejected: %v", err))
+		}
+
+		Expect(err).ToNot(HaveOccurred(), "control plane machine set should be able to be deactivated")
+
+		DeferCleanup(func() {
+			EnsureActiveControlPlaneMachineSet(testFramework)
+		})
+
+		originalProviderSpec, _ := IncreaseControlPlaneMachineInstanceSize(testFramework, index)
+
+		DeferCleanup(func() {
+			UpdateControlPlaneMachineProviderSpec(testFramework, index, originalProviderSpec)
+		})
+
+		By("Checking the control plane machines are not replaced")
+		Consistently(komega.ObjectList(&machinev1beta1.MachineList{}, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels()))).
+			Should(HaveField("Items", SatisfyAll(
+				HaveLen(int(desiredReplicas)),
+				HaveEach(HaveField("ObjectMeta.DeletionTimestamp", BeNil())),
+			)), "inactive control plane machine set should not replace the outdated machine")
+	})
+}
+
+// ItShouldHandleDuplicateFailureDomains checks that the control plane machine set treats a failure domain
+// listed more than once as a single failure domain, without causing a rollout.
+func ItShouldHandleDuplicateFailureDomains(testFramework framework.Framework) {
+	It("should handle duplicate failure domains", func() {
+		Expect(ExpectDuplicateFailureDomainsHandled(testFramework)).To(BeTrue(), "control plane machine set should handle duplicate failure domains")
+	})
+}
+
+// ItShouldHandleMoreDomainsThanReplicas checks that, when the control plane machine set has more failure domains
+// than replicas, the operator selects a stable subset of the failure domains for the control plane machines without
+// erroring or causing a rollout.
+// The test is skipped when no failure domains are configured.
+func ItShouldHandleMoreDomainsThanReplicas(testFramework framework.Framework) {
+	It("should handle more failure domains than replicas", func() {
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+
+		if cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains.Platform == "" {
+			Skip("Skipping as the control plane machine set has no failure domains")
+		}
+
+		Expect(ExpectSurplusFailureDomainsHandled(testFramework)).To(BeTrue(), "control plane machine set should handle more failure domains than replicas")
+	})
+}
+
+// ItShouldNormalizeFailureDomainStrings checks that failure domain zones which differ from the zones of the control
+// plane machines only in case or surrounding whitespace, as when copied into the control plane machine set by hand,
+// do not cause a rollout, as the operator normalizes the zones before comparing them.
+// The test is skipped when no failure domains are configured, or none of the failure domains set a zone.
+// The original failure domains are restored once the test completes.
+func ItShouldNormalizeFailureDomainStrings(testFramework framework.Framework) {
+	It("should not roll out when failure domain zones differ only in case and whitespace", Offset(1), func() {
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+
+		if cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains.Platform == "" {
+			Skip("Skipping as the control plane machine set has no failure domains")
+		}
+
+		desiredReplicas := *cpms.Spec.Replicas
+		originalFailureDomains := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains.DeepCopy()
+
+		updatedFailureDomains := originalFailureDomains.DeepCopy()
+		if !denormalizeFailureDomainZones(updatedFailureDomains) {
+			Skip("Skipping as none of the failure domains set a zone")
+		}
+
+		machineSelector := runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())
+		machineList := &machinev1beta1.MachineList{}
+		Expect(komega.List(machineList, machineSelector)()).To(Succeed(), "should be able to list machines")
+
+		originalUIDs := []types.UID{}
+
+		for _, machine := range machineList.Items {
+			originalUIDs = append(originalUIDs, machine.UID)
+		}
+
+		By("Rewriting the failure domain zones in upper case with surrounding whitespace")
+
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains = *updatedFailureDomains
+		})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+		DeferCleanup(func() {
+			By("Restoring the original failure domains")
+
+			Eventually(komega.Update(cpms, func() {
+				cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains = *originalFailureDomains
+			})).Should(Succeed(), "control plane machine set should be able to be restored")
+		})
+
+		Expect(testFramework.TriggerCPMSReconcile()).To(Succeed(), "should be able to trigger a control plane machine set reconcile")
+
+		By("Checking the control plane machine set consistently reports all replicas as updated")
+
+		Consistently(komega.Object(cpms)).Should(SatisfyAll(
+			HaveField("Status.Replicas", Equal(desiredReplicas)),
+			HaveField("Status.UpdatedReplicas", Equal(desiredReplicas)),
+		), "control plane machine set should normalize the failure domain zones before comparing them")
+
+		By("Checking the control plane machines are not replaced")
+
+		Expect(komega.ObjectList(&machinev1beta1.MachineList{}, machineSelector)()).To(HaveField("Items",
+			WithTransform(func(machines []machinev1beta1.Machine) []types.UID {
+				uids := []types.UID{}
+
+				for _, machine := range machines {
+					uids = append(uids, machine.UID)
+				}
+
+				return uids
+			}, ConsistOf(originalUIDs)),
+		), "control plane machines should keep their UIDs when the failure domain zones differ only in case and whitespace")
+
+		ExpectControlPlaneMachinesWithoutDeletionTimestamp(testFramework)
+	})
+}
+
+// ItShouldHandleFewerDomainsThanReplicas checks that, when the failure domains of the control plane machine set are
+// reduced to fewer failure domains than replicas, the operator wraps the control plane machines around the remaining
+// failure domains, for example placing three replicas in failure domains A, B and A, rather than erroring.
+// The first two failure domains, in alphabetical order, are kept and the machines in the removed failure domains are
+// replaced by a rolling update. The resulting placement must be balanced and stable across reconciles.
+// The test is skipped when fewer than three failure domains are configured.
+// Once the test completes, the original failure domains are restored and the machines rebalanced.
+func ItShouldHandleFewerDomainsThanReplicas(testFramework framework.Framework) {
+	It("should wrap the control plane machines around fewer failure domains than replicas", Offset(1), func() {
+		const keptFailureDomainCount = 2
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Strategy.Type).To(Equal(machinev1.RollingUpdate), "control plane machine set should use the RollingUpdate update strategy")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+
+		desiredReplicas := *cpms.Spec.Replicas
+
+		distribution, err := ControlPlaneDomainDistribution(testFramework)
+		Expect(err).ToNot(HaveOccurred(), "should be able to get the failure domain distribution")
+
+		if len(distribution) <= keptFailureDomainCount {
+			Skip(fmt.Sprintf("Skipping as the control plane machine set has %d failure domains, more than %d are required", len(distribution), keptFailureDomainCount))
+		}
+
+		originalFailureDomains := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains.DeepCopy()
+		updatedFailureDomains := originalFailureDomains.DeepCopy()
+
+		kept, err := keepFirstFailureDomains(updatedFailureDomains, keptFailureDomainCount)
+		Expect(err).ToNot(HaveOccurred(), "should be able to reduce the failure domains")
+
+		indexes, err := controlPlaneIndexesOutsideFailureDomains(kept)
+		Expect(err).ToNot(HaveOccurred(), "should be able to find the machines outside of the kept failure domains")
+
+		By(fmt.Sprintf("Reducing the control plane machine set to %d failure domains, indexes %v should be replaced", keptFailureDomainCount, indexes))
+
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains = *updatedFailureDomains
+		})).Should(Succeed(), "control plane machine set should accept fewer failure domains than replicas")
+
+		DeferCleanup(func() {
+			By("Restoring the original failure domains of the control plane machine set")
+
+			Eventually(komega.Update(cpms, func() {
+				cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains = *originalFailureDomains
+			})).Should(Succeed(), "control plane machine set failure domains should be able to be restored")
+
+			Expect(ExpectObservedGenerationTracksSpec(testFramework, testFramework.GetContext())).To(BeTrue(), "control plane machine set should observe the restored failure domains")
+
+			restoreCtx, cancel := context.WithTimeout(testFramework.GetContext(), 1*time.Hour)
+			defer cancel()
+
+			Expect(WaitForControlPlaneMachineSetDesiredReplicas(restoreCtx, cpms.DeepCopy())).To(BeTrue(), "control plane machines should be rebalanced across the original failure domains")
+		})
+
+		rolloutCtx, cancel := context.WithTimeout(testFramework.GetContext(), 1*time.Hour)
+		defer cancel()
+
+		wg := &sync.WaitGroup{}
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckReplicasDoesNotExceedSurgeCapacity(rolloutCtx)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckReadyReplicasNeverBelowQuorum(testFramework, rolloutCtx)
+		})
+
+		for _, index := range indexes {
+			idx := index
+
+			framework.Async(wg, cancel, func() bool {
+				return CheckRolloutForIndex(testFramework, rolloutCtx, idx, machinev1.RollingUpdate)
+			})
+		}
+
+		framework.Async(wg, cancel, func() bool {
+			return WaitForControlPlaneMachineSetDesiredReplicas(rolloutCtx, cpms.DeepCopy())
+		})
+
+		wg.Wait()
+
+		// If there's an error in the context, either it timed out or one of the async checks failed.
+		Expect(rolloutCtx.Err()).ToNot(HaveOccurred(), "rollout should have completed successfully")
+		By("Control plane machines moved into the remaining failure domains")
+
+		outside, err := controlPlaneIndexesOutsideFailureDomains(kept)
+		Expect(err).ToNot(HaveOccurred(), "should be able to find the machines outside of the kept failure domains")
+		Expect(outside).To(BeEmpty(), "no control plane machine should remain in a removed failure domain")
+
+		Expect(ExpectBalancedDomainDistribution(testFramework)).To(BeTrue(), "control plane machines should wrap around the remaining failure domains")
+
+		mapping, err := controlPlaneMachineFailureDomains(kept)
+		Expect(err).ToNot(HaveOccurred(), "should be able to get the failure domains of the control plane machines")
+
+		Expect(testFramework.TriggerCPMSReconcile()).To(Succeed(), "should be able to trigger a control plane machine set reconcile")
+
+		By("Checking the wrapped placement is stable across reconciles")
+
+		Consistently(komega.Object(cpms)).Should(SatisfyAll(
+			HaveField("Status.Replicas", Equal(desiredReplicas)),
+			HaveField("Status.UpdatedReplicas", Equal(desiredReplicas)),
+		), "control plane machine set should not roll out again once the machines are wrapped around the failure domains")
+
+		Expect(controlPlaneMachineFailureDomains(kept)).To(Equal(mapping), "control plane machine failure domains should be stable across reconciles")
+	})
+}
+
+// ItShouldPreferFailureDomainOverTemplateZone checks that, on AWS, the availability zones defined in the failure
+// domains take precedence over the availability zone within the template provider spec.
+// When failure domains are configured, the failure domain for each index is injected into the template provider spec,
+// replacing any availability zone set within the template. A template availability zone that matches none of the
+// failure domains should therefore not cause a rollout, and the machines should remain within the failure domain zones.
+// The test is skipped on platforms other than AWS, or when no failure domains are configured.
+// The original template provider spec is restored once the test completes.
+func ItShouldPreferFailureDomainOverTemplateZone(testFramework framework.Framework) {
+	It("should prefer the failure domain zone over the template zone", func() {
+		if testFramework.GetPlatformType() != configv1.AWSPlatformType {
+			Skip(fmt.Sprintf("Skipping as availability zone precedence is only tested on AWS, not on platform %s", testFramework.GetPlatformType()))
+		}
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+
+		failureDomains := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains.AWS
+		if failureDomains == nil || len(*failureDomains) == 0 {
+			Skip("Skipping as the control plane machine set has no failure domains")
+		}
+
+		failureDomainZones := []string{}
+		for _, failureDomain := range *failureDomains {
+			failureDomainZones = append(failureDomainZones, failureDomain.Placement.AvailabilityZone)
+		}
+
+		desiredReplicas := *cpms.Spec.Replicas
+		originalProviderSpec := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec
+
+		updatedProviderSpec := originalProviderSpec.DeepCopy()
+		Expect(framework.SetAWSProviderSpecAvailabilityZone(updatedProviderSpec.Value, "e2e-template-zone")).To(Succeed(), "provider spec should be updated with the template availability zone")
+
+		By("Setting an availability zone on the template that matches none of the failure domains")
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = *updatedProviderSpec
+		})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+		DeferCleanup(func() {
+			By("Restoring the original control plane machine set provider spec")
+			Eventually(komega.Update(cpms, func() {
+				cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = originalProviderSpec
+			})).Should(Succeed(), "control plane machine set should be able to be updated")
+		})
+
+		Expect(testFramework.TriggerCPMSReconcile()).To(Succeed(), "should be able to trigger a control plane machine set reconcile")
+
+		By("Checking the template availability zone does not cause a rollout")
+		Consistently(komega.Object(cpms)).Should(SatisfyAll(
+			HaveField("Status.Replicas", Equal(desiredReplicas)),
+			HaveField("Status.UpdatedReplicas", Equal(desiredReplicas)),
+		), "control plane machine set should inject the failure domain zones over the template zone")
+
+		By("Checking the control plane machines remain within the failure domain zones")
+
+		machineList := &machinev1beta1.MachineList{}
+		Expect(komega.List(machineList, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels()))()).To(Succeed(), "should be able to list control plane machines")
+
+		for _, machine := range machineList.Items {
+			zone, err := framework.GetAWSProviderSpecAvailabilityZone(machine.Spec.ProviderSpec.Value)
+			Expect(err).ToNot(HaveOccurred(), "should be able to read the availability zone of machine %s", machine.Name)
+			Expect(zone).To(BeElementOf(failureDomainZones), "machine %s should be within one of the failure domain zones", machine.Name)
+		}
+	})
+}
+
+// ItShouldNotRollOnTagReorder checks that reordering the tags within the control plane machine set template on AWS
+// does not cause a rollout.
+// The tags are compared regardless of their order when detecting drift, as the order has no effect on the instance.
+// The original tag ordering is restored once the test completes.
+func ItShouldNotRollOnTagReorder(testFramework framework.Framework) {
+	It("should not roll out machines when the tags are reordered", func() {
+		if testFramework.GetPlatformType() != configv1.AWSPlatformType {
+			Skip(fmt.Sprintf("Skipping as tag reordering is only tested on AWS, not on platform %s", testFramework.GetPlatformType()))
+		}
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+
+		desiredReplicas := *cpms.Spec.Replicas
+		originalProviderSpec := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec
+
+		tags, err := framework.GetAWSProviderSpecTags(originalProviderSpec.Value)
+		Expect(err).ToNot(HaveOccurred(), "should be able to read the tags from the control plane machine set")
+
+		if len(tags) < 2 {
+			Skip("Skipping as the control plane machine set has fewer than 2 tags to reorder")
+		}
+
+		updatedProviderSpec := originalProviderSpec.DeepCopy()
+		Expect(framework.ReverseAWSProviderSpecTags(updatedProviderSpec.Value)).To(Succeed(), "provider spec should be updated with the reordered tags")
+
+		By("Reordering the tags within the control plane machine set template")
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = *updatedProviderSpec
+		})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+		DeferCleanup(func() {
+			By("Restoring the original control plane machine set tag ordering")
+			Eventually(komega.Update(cpms, func() {
+				cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = originalProviderSpec
+			})).Should(Succeed(), "control plane machine set should be able to be updated")
+		})
+
+		Expect(testFramework.TriggerCPMSReconcile()).To(Succeed(), "should be able to trigger a control plane machine set reconcile")
+
+		By("Checking the reordered tags do not cause a rollout")
+		Consistently(komega.Object(cpms)).Should(SatisfyAll(
+			HaveField("Status.Replicas", Equal(desiredReplicas)),
+			HaveField("Status.UpdatedReplicas", Equal(desiredReplicas)),
+		), "control plane machine set should not observe drift from reordered tags")
+
+		ExpectControlPlaneMachinesWithoutDeletionTimestamp(testFramework)
+	})
+}
+
+// ItShouldIgnoreMachineStatusDifferences checks that changing only the status of the live machine in the given index
+// does not cause a rollout, as the operator detects drift by comparing the machine spec with the template.
+// A condition, of a type no controller manages, is added to the machine status, and the machine must keep its UID
+// throughout. The condition is removed once the test completes.
+func ItShouldIgnoreMachineStatusDifferences(testFramework framework.Framework, index int) {
+	It("should not roll out when only the machine status differs", Offset(1), func() {
+		const statusDriftConditionType machinev1beta1.ConditionType = "E2EStatusDrift"
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+
+		desiredReplicas := *cpms.Spec.Replicas
+
+		machine, err := machineForIndex(testFramework, index)
+		Expect(err).ToNot(HaveOccurred(), "should be able to get the machine for index %d", index)
+
+		originalUID := machine.UID
+
+		By(fmt.Sprintf("Adding a %s condition to the status of machine %s", statusDriftConditionType, machine.Name))
+
+		Eventually(komega.UpdateStatus(machine, func() {
+			machine.Status.Conditions = append(machine.Status.Conditions, machinev1beta1.Condition{
+				Type:               statusDriftConditionType,
+				Status:             corev1.ConditionTrue,
+				Severity:           machinev1beta1.ConditionSeverityInfo,
+				LastTransitionTime: metav1.Now(),
+				Reason:             "E2ETest",
+				Message:            "Machine status changed by the e2e test suite",
+			})
+		})).Should(Succeed(), "machine %s status should be able to be updated", machine.Name)
+
+		DeferCleanup(func() {
+			By(fmt.Sprintf("Removing the %s condition from the status of machine %s", statusDriftConditionType, machine.Name))
+
+			Eventually(komega.UpdateStatus(machine, func() {
+				conditions := machinev1beta1.Conditions{}
+
+				for _, condition := range machine.Status.Conditions {
+					if condition.Type != statusDriftConditionType {
+						conditions = append(conditions, condition)
+					}
+				}
+
+				machine.Status.Conditions = conditions
+			})).Should(Succeed(), "machine %s status should be able to be restored", machine.Name)
+		})
+
+		Expect(testFramework.TriggerCPMSReconcile()).To(Succeed(), "should be able to trigger a control plane machine set reconcile")
+
+		By("Checking the machine status change does not cause a rollout")
+
+		Consistently(komega.Object(cpms)).Should(SatisfyAll(
+			HaveField("Status.Replicas", Equal(desiredReplicas)),
+			HaveField("Status.UpdatedReplicas", Equal(desiredReplicas)),
+		), "control plane machine set should compare only the machine spec with the template")
+
+		Consistently(komega.Object(machine)).Should(SatisfyAll(
+			HaveField("ObjectMeta.UID", Equal(originalUID)),
+			HaveField("ObjectMeta.DeletionTimestamp", BeNil()),
+		), "machine %s should not be replaced", machine.Name)
+	})
+}
+
+// ItShouldIgnoreProviderSpecMetadataName checks that a name within the metadata block embedded in the provider spec
+// does not cause a rollout, as the operator ignores the embedded metadata when comparing machines with the template.
+// The template and the live machine in the given index are given different metadata names, as a name generated per
+// machine would be, and the machine must keep its UID throughout. Platforms whose provider specs do not carry a
+// metadata block are skipped.
+func ItShouldIgnoreProviderSpecMetadataName(testFramework framework.Framework, index int) {
+	It("should not roll out when only the provider spec metadata name differs", Offset(1), func() {
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+
+		desiredReplicas := *cpms.Spec.Replicas
+		originalTemplateProviderSpec := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec
+
+		updatedTemplateProviderSpec := originalTemplateProviderSpec.DeepCopy()
+
+		err := framework.SetProviderSpecMetadataName(updatedTemplateProviderSpec.Value, "e2e-template")
+		if errors.Is(err, framework.ErrNoProviderSpecMetadata) {
+			Skip("Skipping as the provider spec does not carry a metadata block")
+		}
+
+		Expect(err).ToNot(HaveOccurred(), "should be able to set the metadata name of the template provider spec")
+
+		machine, err := machineForIndex(testFramework, index)
+		Expect(err).ToNot(HaveOccurred(), "should be able to get the machine for index %d", index)
+
+		originalUID := machine.UID
+		originalMachineProviderSpec := machine.Spec.ProviderSpec
+
+		updatedMachineProviderSpec := originalMachineProviderSpec.DeepCopy()
+		Expect(framework.SetProviderSpecMetadataName(updatedMachineProviderSpec.Value, machine.Name)).To(Succeed(),
+			"should be able to set the metadata name of the machine provider spec")
+
+		By("Setting a metadata name in the control plane machine set template provider spec")
+
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = *updatedTemplateProviderSpec
+		})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+		DeferCleanup(func() {
+			By("Restoring the original control plane machine set provider spec")
+
+			Eventually(komega.Update(cpms, func() {
+				cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = originalTemplateProviderSpec
+			})).Should(Succeed(), "control plane machine set should be able to be restored")
+		})
+
+		By(fmt.Sprintf("Setting a metadata name in the provider spec of machine %s", machine.Name))
+
+		Eventually(komega.Update(machine, func() {
+			machine.Spec.ProviderSpec = *updatedMachineProviderSpec
+		})).Should(Succeed(), "machine %s should be able to be updated", machine.Name)
+
+		DeferCleanup(func() {
+			By(fmt.Sprintf("Restoring the original provider spec of machine %s", machine.Name))
+
+			Eventually(komega.Update(machine, func() {
+				machine.Spec.ProviderSpec = originalMachineProviderSpec
+			})).Should(Succeed(), "machine %s should be able to be restored", machine.Name)
+		})
+
+		Expect(testFramework.TriggerCPMSReconcile()).To(Succeed(), "should be able to trigger a control plane machine set reconcile")
+
+		By("Checking the provider spec metadata names do not cause a rollout")
+
+		Consistently(komega.Object(cpms)).Should(SatisfyAll(
+			HaveField("Status.Replicas", Equal(desiredReplicas)),
+			HaveField("Status.UpdatedReplicas", Equal(desiredReplicas)),
+		), "control plane machine set should ignore the provider spec metadata when comparing machines")
+
+		Consistently(komega.Object(machine)).Should(SatisfyAll(
+			HaveField("ObjectMeta.UID", Equal(originalUID)),
+			HaveField("ObjectMeta.DeletionTimestamp", BeNil()),
+		), "machine %s should not be replaced", machine.Name)
+
+		ExpectControlPlaneMachinesWithoutDeletionTimestamp(testFramework)
+	})
+}
+
+// ItShouldNotRollDueToTimestampComparison checks that timestamps never cause a rollout, as the operator excludes
+// them when comparing machines with the template, and so a clock skew between the operator, the API server and the
+// Machine API controllers cannot cause spurious rollouts.
+// The provider spec metadata of the template is given a creation timestamp an hour in the past, and that of the live
+// machine in index 1 an hour in the future, as a skewed clock might set. The machine must keep its UID throughout.
+// Platforms whose provider specs do not carry a metadata block are skipped.
+func ItShouldNotRollDueToTimestampComparison(testFramework framework.Framework) {
+	It("should not roll out when only timestamps differ", Offset(1), func() {
+		const (
+			clockSkew = time.Hour
+			index     = 1
+		)
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+
+		desiredReplicas := *cpms.Spec.Replicas
+		originalTemplateProviderSpec := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec
+
+		updatedTemplateProviderSpec := originalTemplateProviderSpec.DeepCopy()
+
+		err := framework.SetProviderSpecMetadataCreationTimestamp(updatedTemplateProviderSpec.Value, time.Now().Add(-clockSkew))
+		if errors.Is(err, framework.ErrNoProviderSpecMetadata) {
+			Skip("Skipping as the provider spec does not carry a metadata block")
+		}
+
+		Expect(err).ToNot(HaveOccurred(), "should be able to set the creation timestamp of the template provider spec")
+
+		machine, err := machineForIndex(testFramework, index)
+		Expect(err).ToNot(HaveOccurred(), "should be able to get the machine for index %d", index)
+
+		originalUID := machine.UID
+		originalMachineProviderSpec := machine.Spec.ProviderSpec
+
+		updatedMachineProviderSpec := originalMachineProviderSpec.DeepCopy()
+		Expect(framework.SetProviderSpecMetadataCreationTimestamp(updatedMachineProviderSpec.Value, time.Now().Add(clockSkew))).To(Succeed(),
+			"should be able to set the creation timestamp of the machine provider spec")
+
+		By("Setting a creation timestamp in the past in the control plane machine set template provider spec")
+
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = *updatedTemplateProviderSpec
+		})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+		DeferCleanup(func() {
+			By("Restoring the original control plane machine set provider spec")
+
+			Eventually(komega.Update(cpms, func() {
+				cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = originalTemplateProviderSpec
+			})).Should(Succeed(), "control plane machine set should be able to be restored")
+		})
+
+		By(fmt.Sprintf("Setting a creation timestamp in the future in the provider spec of machine %s", machine.Name))
+
+		Eventually(komega.Update(machine, func() {
+			machine.Spec.ProviderSpec = *updatedMachineProviderSpec
+		})).Should(Succeed(), "machine %s should be able to be updated", machine.Name)
+
+		DeferCleanup(func() {
+			By(fmt.Sprintf("Restoring the original provider spec of machine %s", machine.Name))
+
+			Eventually(komega.Update(machine, func() {
+				machine.Spec.ProviderSpec = originalMachineProviderSpec
+			})).Should(Succeed(), "machine %s should be able to be restored", machine.Name)
+		})
+
+		Expect(testFramework.TriggerCPMSReconcile()).To(Succeed(), "should be able to trigger a control plane machine set reconcile")
+
+		By("Checking the skewed timestamps do not cause a rollout")
+
+		Consistently(komega.Object(cpms), 2*time.Minute, 10*time.Second).Should(SatisfyAll(
+			HaveField("Status.Replicas", Equal(desiredReplicas)),
+			HaveField("Status.UpdatedReplicas", Equal(desiredReplicas)),
+		), "control plane machine set should exclude timestamps when comparing machines")
+
+		Consistently(komega.Object(machine)).Should(SatisfyAll(
+			HaveField("ObjectMeta.UID", Equal(originalUID)),
+			HaveField("ObjectMeta.DeletionTimestamp", BeNil()),
+		), "machine %s should not be replaced", machine.Name)
+
+		ExpectControlPlaneMachinesWithoutDeletionTimestamp(testFramework)
+	})
+}
+
+// ItShouldCheckAllControlPlaneMachinesHaveCorrectOwnerReferences checks that all the control plane machines
+// have the correct owner references set.
+func ItShouldCheckAllControlPlaneMachinesHaveCorrectOwnerReferences(testFramework framework.Framework) {
+	It("should find all control plane machines to have owner references set", func() {
+		// Check that all the control plane machines are owned.
+		ExpectControlPlaneMachinesOwned(testFramework)
+
+		// Check that no control plane machine is garbage collected (being deleted),
+		// as this may happen if incorrect owner references are added.
+		ConsistentlyControlPlaneMachinesWithoutDeletionTimestamp(testFramework)
+
+		// Check that the operators are stable.
+		EventuallyClusterOperatorsShouldStabilise()
+	})
+}
+
+// ItShouldPerformControlPlaneMachineSetRegeneration checks that an inactive control plane machine set
+// is regenerated if the reference machine spec changes.
+func ItShouldPerformControlPlaneMachineSetRegeneration(opts *ControlPlaneMachineSetRegenerationTestOptions, gomegaArgs ...interface{}) {
+	It("should perform control plane machine set regeneration", func() {
+		Expect(opts.TestFramework).ToNot(BeNil(), "test framework should not be nil")
+		ctx := opts.TestFramework.GetContext()
+		cpms := opts.TestFramework.NewEmptyControlPlaneMachineSet()
+
+		// The origin is captured before the provider spec is changed, as the control plane
+		// machine set may already be being recreated by the time the test starts.
+		if !opts.Generated {
+			Skip("Skipping as the control plane machine set was not generated by the operator")
+		}
+
+		// Check that the control plane machine set is regenerated.
+		WaitForControlPlaneMachineSetRemovedOrRecreated(ctx, opts.TestFramework, opts.UID)
+		EnsureInactiveControlPlaneMachineSet(opts.TestFramework)
+
+		rawExtension, err := opts.TestFramework.ConvertToControlPlaneMachineSetProviderSpec(opts.UpdatedProviderSpec)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Checking the control plane machine set reports 1 updated machine, 2 needing update")
+		Eventually(komega.Object(cpms)).Should(
+			SatisfyAll(
+				HaveField("Status.UpdatedReplicas", Equal(int32(1))),
+				HaveField("Status.UnavailableReplicas", Equal(int32(0))),
+			),
+		)
+
+		By("Checking the control plane machine set has the correct providerSpec")
+		Expect(cpms).To(
+			HaveField("Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value.Raw",
+				MatchJSON(rawExtension.Raw)),
+		)
+	})
+}
+
+// ItShouldRolloutOnLoadBalancerConfigChange checks that attaching an additional, externally managed,
+// load balancer to the control plane machine set template causes a rolling update, and that the
+// replacement machines are attached to the new load balancer.
+// The load balancer must already exist, its name is read from the CPMS_E2E_LOAD_BALANCER_NAME
+// environment variable and the test is skipped when it is not set.
+// Once the test completes, the original provider spec is restored and rolled out.
+func ItShouldRolloutOnLoadBalancerConfigChange(testFramework framework.Framework) {
+	It("should rollout when the load balancer configuration changes", Offset(1), func() {
+		switch testFramework.GetPlatformType() {
+		case configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType:
+		default:
+			Skip(fmt.Sprintf("Skipping as load balancer changes are not supported on platform %s", testFramework.GetPlatformType()))
+		}
+
+		loadBalancerName := lookupEnvOrSkip(loadBalancerNameEnvVar)
+
+		By(fmt.Sprintf("Adding load balancer %s to the control plane machine set", loadBalancerName))
+
+		rolloutProviderSpecChange(testFramework,
+			func(providerSpec *runtime.RawExtension) error {
+				return framework.AddProviderSpecLoadBalancer(providerSpec, loadBalancerName)
+			},
+			func(machine machinev1beta1.Machine) {
+				loadBalancers, err := framework.GetProviderSpecLoadBalancers(machine.Spec.ProviderSpec.Value)
+				Expect(err).ToNot(HaveOccurred(), "should be able to read the load balancers from machine %s", machine.Name)
+				Expect(loadBalancers).To(ContainElement(loadBalancerName), "machine %s should be attached to the new load balancer", machine.Name)
+			},
+		)
+	})
+}
+
+// ItShouldRolloutOnTenancyChange checks that changing the placement tenancy of the control plane machine set
+// template to dedicated causes a rolling update, and that the replacement machines have the dedicated tenancy.
+// This test only applies to AWS.
+// Once the test completes, the original provider spec is restored and rolled out.
+func ItShouldRolloutOnTenancyChange(testFramework framework.Framework) {
+	It("should rollout when the placement tenancy changes", Offset(1), func() {
+		if testFramework.GetPlatformType() != configv1.AWSPlatformType {
+			Skip(fmt.Sprintf("Skipping as placement tenancy is not supported on platform %s", testFramework.GetPlatformType()))
+		}
+
+		tenancy := string(machinev1beta1.DedicatedTenancy)
+
+		By(fmt.Sprintf("Changing the control plane machine set placement tenancy to %s", tenancy))
+
+		rolloutProviderSpecChange(testFramework,
+			func(providerSpec *runtime.RawExtension) error {
+				return framework.SetAWSProviderSpecTenancy(providerSpec, tenancy)
+			},
+			func(machine machinev1beta1.Machine) {
+				machineTenancy, err := framework.GetAWSProviderSpecTenancy(machine.Spec.ProviderSpec.Value)
+				Expect(err).ToNot(HaveOccurred(), "should be able to read the tenancy from machine %s", machine.Name)
+				Expect(machineTenancy).To(Equal(tenancy), "machine %s should have the updated tenancy", machine.Name)
+			},
+		)
+	})
+}
+
+// ItShouldCarryRootVolumeIOPSOnRollout checks that, once the control plane machine set template changes the IOPS of
+// the root volume, the replacement for the outdated machine in the given index is created with the new IOPS.
+// Raising the IOPS of the control plane root volumes is a common way to tune etcd performance.
+// AWS only honours IOPS on provisioned IOPS and gp3 volumes, so any other root volume type is switched to gp3.
+// This test only applies to AWS.
+// Once the test completes, the original template provider spec is restored.
+func ItShouldCarryRootVolumeIOPSOnRollout(testFramework framework.Framework, index int) {
+	It("should carry the root volume IOPS to the replacement machine", Offset(1), func() {
+		if testFramework.GetPlatformType() != configv1.AWSPlatformType {
+			Skip(fmt.Sprintf("Skipping as root volume IOPS are not supported on platform %s", testFramework.GetPlatformType()))
+		}
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Strategy.Type).To(Equal(machinev1.RollingUpdate), "control plane machine set should use the RollingUpdate update strategy")
+
+		originalProviderSpec := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec
+		updatedProviderSpec := originalProviderSpec.DeepCopy()
+
+		volumeType, err := framework.ProviderSpecDiskType(updatedProviderSpec.Value)
+		Expect(err).ToNot(HaveOccurred(), "should be able to read the root volume type from the template")
+
+		switch volumeType {
+		case "gp3", "io1", "io2":
+		default:
+			Expect(framework.UpdateProviderSpecDiskType(updatedProviderSpec.Value, "gp3")).To(Succeed(), "provider spec should be updated with a gp3 root volume")
+		}
+
+		iops, err := framework.GetAWSProviderSpecRootVolumeIOPS(updatedProviderSpec.Value)
+		Expect(err).ToNot(HaveOccurred(), "should be able to read the root volume IOPS from the template")
+
+		// gp3 volumes have a baseline of 3000 IOPS, so use a higher value when the IOPS are not already set.
+		if iops == 0 {
+			iops = 3000
+		}
+
+		iops += 1000
+
+		Expect(framework.SetAWSProviderSpecRootVolumeIOPS(updatedProviderSpec.Value, iops)).To(Succeed(), "provider spec should be updated with the root volume IOPS")
+
+		By(fmt.Sprintf("Setting the control plane machine set root volume IOPS to %d", iops))
+
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = *updatedProviderSpec
+		})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+		DeferCleanup(func() {
+			By("Restoring the original control plane machine set provider spec")
+
+			Eventually(komega.Update(cpms, func() {
+				cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = originalProviderSpec
+			})).Should(Succeed(), "control plane machine set should be able to be restored")
+		})
+
+		rolloutCtx, cancel := context.WithTimeout(testFramework.GetContext(), 30*time.Minute)
+		defer cancel()
+
+		Expect(EventuallyIndexIsBeingReplaced(rolloutCtx, index)).To(BeTrue(), "index %d should be replaced", index)
+
+		_, newMachine, ok := getOldAndNewMachineForIndex(rolloutCtx, testFramework, index)
+		Expect(ok).To(BeTrue(), "should be able to get the replacement machine for index %d", index)
+
+		machineIOPS, err := framework.GetAWSProviderSpecRootVolumeIOPS(newMachine.Spec.ProviderSpec.Value)
+		Expect(err).ToNot(HaveOccurred(), "should be able to read the root volume IOPS from machine %s", newMachine.Name)
+		Expect(machineIOPS).To(Equal(iops), "replacement machine %s should be created with the root volume IOPS", newMachine.Name)
+
+		Expect(WaitForControlPlaneMachineSetDesiredReplicas(rolloutCtx, cpms.DeepCopy())).To(BeTrue(), "control plane machine set should reach the desired replicas")
+	})
+}
+
+// ItShouldRolloutOnKMSKeyRotation checks that rotating the key used to encrypt the root disks of control plane
+// machines causes a rolling update, and that the replacement machine for the given index is encrypted with the new key.
+// The key must already exist, and be usable by the control plane machines. It is read from the CPMS_E2E_KMS_KEY
+// environment variable and the test is skipped when it is not set. Platforms on which root disk encryption keys are
+// not supported are skipped.
+func ItShouldRolloutOnKMSKeyRotation(testFramework framework.Framework, index int) {
+	It("should rollout when the root disk encryption key is rotated", Offset(1), func() {
+		switch testFramework.GetPlatformType() {
+		case configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType:
+		default:
+			Skip(fmt.Sprintf("Skipping as root disk encryption keys are not supported on platform %s", testFramework.GetPlatformType()))
+		}
+
+		kmsKey := lookupEnvOrSkip(kmsKeyEnvVar)
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Strategy.Type).To(Equal(machinev1.RollingUpdate), "control plane machine set should use the RollingUpdate update strategy")
+
+		originalProviderSpec := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec
+		updatedProviderSpec := originalProviderSpec.DeepCopy()
+
+		Expect(framework.UpdateProviderSpecKMSKey(updatedProviderSpec.Value, kmsKey)).To(Succeed(), "provider spec should be updated with the root disk encryption key")
+
+		By(fmt.Sprintf("Rotating the control plane machine set root disk encryption key to %s", kmsKey))
+
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = *updatedProviderSpec
+		})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+		DeferCleanup(func() {
+			By("Restoring the original control plane machine set provider spec")
+
+			Eventually(komega.Update(cpms, func() {
+				cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = originalProviderSpec
+			})).Should(Succeed(), "control plane machine set should be able to be restored")
+		})
+
+		rolloutCtx, cancel := context.WithTimeout(testFramework.GetContext(), 30*time.Minute)
+		defer cancel()
+
+		Expect(EventuallyIndexIsBeingReplaced(rolloutCtx, index)).To(BeTrue(), "index %d should be replaced", index)
+
+		_, newMachine, ok := getOldAndNewMachineForIndex(rolloutCtx, testFramework, index)
+		Expect(ok).To(BeTrue(), "should be able to get the replacement machine for index %d", index)
+
+		encrypted, machineKMSKey, err := framework.ProviderSpecEncryptionSettings(newMachine.Spec.ProviderSpec.Value)
+		Expect(err).ToNot(HaveOccurred(), "should be able to read the root disk encryption settings from machine %s", newMachine.Name)
+		Expect(encrypted).To(BeTrue(), "replacement machine %s root disk should be encrypted", newMachine.Name)
+		Expect(machineKMSKey).To(Equal(kmsKey), "replacement machine %s should be encrypted with the rotated key", newMachine.Name)
+
+		Expect(WaitForControlPlaneMachineSetDesiredReplicas(rolloutCtx, cpms.DeepCopy())).To(BeTrue(), "control plane machine set should reach the desired replicas")
+	})
+}
+
+// ItShouldRolloutOnGCPServiceAccountChange checks that changing the service account attached to machines by the
+// control plane machine set template causes a rolling update, and that the replacement machines carry the new
+// service account.
+// This test only applies to GCP. The service account must already exist, its email is read from the
+// CPMS_E2E_GCP_SERVICE_ACCOUNT_EMAIL environment variable and the test is skipped when it is not set.
+// Once the test completes, the original provider spec is restored and rolled out.
+func ItShouldRolloutOnGCPServiceAccountChange(testFramework framework.Framework) {
+	It("should rollout when the service account changes", Offset(1), func() {
+		if testFramework.GetPlatformType() != configv1.GCPPlatformType {
+			Skip(fmt.Sprintf("Skipping as service account changes are not supported on platform %s", testFramework.GetPlatformType()))
+		}
+
+		email := lookupEnvOrSkip(gcpServiceAccountEmailEnvVar)
+		scopes := "https://www.googleapis.com/auth/cloud-platform"
+
+		By(fmt.Sprintf("Changing the control plane machine set service account to %s", email))
+
+		rolloutProviderSpecChange(testFramework,
+			func(providerSpec *runtime.RawExtension) error {
+				return framework.UpdateGCPProviderSpecServiceAccount(providerSpec, email, scopes)
+			},
+			func(machine machinev1beta1.Machine) {
+				serviceAccounts, err := framework.GetGCPProviderSpecServiceAccounts(machine.Spec.ProviderSpec.Value)
+				Expect(err).ToNot(HaveOccurred(), "should be able to read the service accounts from machine %s", machine.Name)
+				Expect(serviceAccounts).To(ConsistOf(HaveField("Email", Equal(email))), "machine %s should carry the new service account", machine.Name)
+			},
+		)
+	})
+}
+
+// ItShouldRolloutOnImageChange checks that changing the boot image of the control plane machine set template
+// causes a rolling update, and that the replacement machines are booted from the new image.
+// This mirrors the rollout performed when the operating system is upgraded via a boot image update.
+// The image must already exist, it is read from the CPMS_E2E_BOOT_IMAGE environment variable and the test is
+// skipped when it is not set, or when the template does not reference the image directly.
+// Once the test completes, the original provider spec is restored and rolled out.
+func ItShouldRolloutOnImageChange(testFramework framework.Framework) {
+	It("should rollout when the boot image changes", Offset(1), func() {
+		switch testFramework.GetPlatformType() {
+		case configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType:
+		default:
+			Skip(fmt.Sprintf("Skipping as boot image changes are not supported on platform %s", testFramework.GetPlatformType()))
+		}
+
+		image := lookupEnvOrSkip(bootImageEnvVar)
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+
+		currentImage, err := framework.ProviderSpecImage(cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value)
+		Expect(err).ToNot(HaveOccurred(), "should be able to read the image from the control plane machine set")
+
+		if currentImage == "" {
+			Skip("Skipping as the control plane machine set does not reference the boot image directly")
+		}
+
+		By(fmt.Sprintf("Changing the control plane machine set boot image to %s", image))
+
+		rolloutProviderSpecChange(testFramework,
+			func(providerSpec *runtime.RawExtension) error {
+				return framework.UpdateProviderSpecImage(providerSpec, image)
+			},
+			func(machine machinev1beta1.Machine) {
+				machineImage, err := framework.ProviderSpecImage(machine.Spec.ProviderSpec.Value)
+				Expect(err).ToNot(HaveOccurred(), "should be able to read the image from machine %s", machine.Name)
+				Expect(machineImage).To(Equal(image), "machine %s should be booted from the new image", machine.Name)
+			},
+		)
+	})
+}
+
+// ItShouldRolloutOnRootDiskSizeIncrease checks that growing the root disk of the control plane machine set template
+// causes a rolling update, and that the replacement machines have the larger root disk.
+// Growing the root disk is a common maintenance action as the etcd data grows.
+// Once the test completes, the original provider spec is restored and rolled out.
+func ItShouldRolloutOnRootDiskSizeIncrease(testFramework framework.Framework) {
+	It("should rollout when the root disk size is increased", Offset(1), func() {
+		switch testFramework.GetPlatformType() {
+		case configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType:
+		default:
+			Skip(fmt.Sprintf("Skipping as root disk size changes are not supported on platform %s", testFramework.GetPlatformType()))
+		}
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+
+		originalSize, err := framework.ProviderSpecRootDiskSize(cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value)
+		Expect(err).ToNot(HaveOccurred(), "should be able to read the root disk size from the control plane machine set")
+
+		By("Increasing the control plane machine set root disk size")
+
+		rolloutProviderSpecChange(testFramework,
+			framework.IncreaseProviderSpecRootDiskSize,
+			func(machine machinev1beta1.Machine) {
+				size, err := framework.ProviderSpecRootDiskSize(machine.Spec.ProviderSpec.Value)
+				Expect(err).ToNot(HaveOccurred(), "should be able to read the root disk size from machine %s", machine.Name)
+				Expect(size).To(BeNumerically(">", originalSize), "machine %s should have the larger root disk", machine.Name)
+			},
+		)
+	})
+}
+
+// ItShouldRolloutOnDiskTypeChange checks that changing the root disk type of the control plane machine set template
+// causes a rolling update, and that the replacement machines have the new disk type.
+// Users move etcd onto faster disk types, for example gp3 or io2 on AWS, Premium_LRS on Azure and pd-ssd on GCP.
+// Once the test completes, the original provider spec is restored and rolled out.
+func ItShouldRolloutOnDiskTypeChange(testFramework framework.Framework) {
+	It("should rollout when the disk type is changed", Offset(1), func() {
+		switch testFramework.GetPlatformType() {
+		case configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType:
+		default:
+			Skip(fmt.Sprintf("Skipping as disk type changes are not supported on platform %s", testFramework.GetPlatformType()))
+		}
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+
+		originalDiskType, err := framework.ProviderSpecDiskType(cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value)
+		Expect(err).ToNot(HaveOccurred(), "should be able to read the disk type from the control plane machine set")
+
+		diskType := alternativeDiskType(testFramework.GetPlatformType(), originalDiskType)
+
+		By(fmt.Sprintf("Changing the control plane machine set disk type from %s to %s", originalDiskType, diskType))
+
+		rolloutProviderSpecChange(testFramework,
+			func(providerSpec *runtime.RawExtension) error {
+				return framework.UpdateProviderSpecDiskType(providerSpec, diskType)
+			},
+			func(machine machinev1beta1.Machine) {
+				machineDiskType, err := framework.ProviderSpecDiskType(machine.Spec.ProviderSpec.Value)
+				Expect(err).ToNot(HaveOccurred(), "should be able to read the disk type from machine %s", machine.Name)
+				Expect(machineDiskType).To(Equal(diskType), "machine %s should have the new disk type", machine.Name)
+			},
+		)
+	})
+}
+
+// ItShouldHandleProviderSpecVersionSkew checks that switching the control plane machine set template provider spec
+// to another compatible API version is decoded and compared correctly by the operator.
+// The API version is compared along with the rest of the provider spec, so the switch should cause a single rolling
+// update, after which the machines carry the new API version and the operator does not roll them out again.
+// The supported API versions are listed by framework.CompatibleProviderSpecAPIVersions. The test prefers the current
+// API version, switching to a legacy API version only when the template already uses the current API version.
+// The test is skipped on platforms with a single provider spec API version.
+// Once the test completes, the original provider spec is restored and rolled out.
+func ItShouldHandleProviderSpecVersionSkew(testFramework framework.Framework) {
+	It("should roll out once when the provider spec API version changes", Offset(1), func() {
+		apiVersions := framework.CompatibleProviderSpecAPIVersions(testFramework.GetPlatformType())
+		if len(apiVersions) < 2 {
+			Skip(fmt.Sprintf("Skipping as platform %s has a single provider spec API version", testFramework.GetPlatformType()))
+		}
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+
+		desiredReplicas := *cpms.Spec.Replicas
+
+		currentAPIVersion, err := framework.ProviderSpecAPIVersion(cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value)
+		Expect(err).ToNot(HaveOccurred(), "should be able to read the API version of the template provider spec")
+
+		apiVersion := apiVersions[0]
+		if currentAPIVersion == apiVersion {
+			apiVersion = apiVersions[1]
+		}
+
+		By(fmt.Sprintf("Changing the control plane machine set provider spec API version from %s to %s", currentAPIVersion, apiVersion))
+
+		rolloutProviderSpecChange(testFramework,
+			func(providerSpec *runtime.RawExtension) error {
+				return framework.SetProviderSpecAPIVersion(providerSpec, apiVersion)
+			},
+			func(machine machinev1beta1.Machine) {
+				machineAPIVersion, err := framework.ProviderSpecAPIVersion(machine.Spec.ProviderSpec.Value)
+				Expect(err).ToNot(HaveOccurred(), "should be able to read the API version from machine %s", machine.Name)
+				Expect(machineAPIVersion).To(Equal(apiVersion), "machine %s should have the new provider spec API version", machine.Name)
+			},
+		)
+
+		Expect(testFramework.TriggerCPMSReconcile()).To(Succeed(), "should be able to trigger a control plane machine set reconcile")
+
+		By("Checking the new API version does not cause a rollout loop")
+
+		Consistently(komega.Object(cpms)).Should(SatisfyAll(
+			HaveField("Status.Replicas", Equal(desiredReplicas)),
+			HaveField("Status.UpdatedReplicas", Equal(desiredReplicas)),
+		), "control plane machine set should consider the machines up to date after the rollout")
+
+		ExpectControlPlaneMachinesWithoutDeletionTimestamp(testFramework)
+	})
+}
+
+// rolloutProviderSpecChange applies the mutation to the provider spec of the control plane machine set template,
+// waits for the resulting rolling update to complete, and then runs the check against each control plane machine.
+// A cleanup is registered to restore, and roll out, the original provider spec once the test completes.
+func rolloutProviderSpecChange(testFramework framework.Framework, mutate func(*runtime.RawExtension) error, check func(machinev1beta1.Machine)) {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+	Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+
+	originalProviderSpec := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec
+
+	updatedProviderSpec := originalProviderSpec.DeepCopy()
+	Expect(mutate(updatedProviderSpec.Value)).To(Succeed(), "provider spec should be updated")
+
+	Eventually(komega.Update(cpms, func() {
+		cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = *updatedProviderSpec
+	})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+	DeferCleanup(func() {
+		By("Restoring the original control plane machine set provider spec")
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = originalProviderSpec
+		})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+		Expect(checkRollingUpdateCompletes(testFramework, 1*time.Hour)).To(BeTrue(), "rollout of the original provider spec should complete")
+		EventuallyClusterOperatorsShouldStabilise(30*time.Minute, 30*time.Second)
+	})
+
+	Expect(checkRollingUpdateCompletes(testFramework, 1*time.Hour)).To(BeTrue(), "rollout of the updated provider spec should complete")
+
+	By("Checking the control plane machines have the updated provider spec")
+
+	machineList := &machinev1beta1.MachineList{}
+	Expect(komega.List(machineList, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels()))()).To(Succeed(), "should be able to list control plane machines")
+
+	for _, machine := range machineList.Items {
+		check(machine)
+	}
+
+	By("Waiting for the cluster to stabilise after the rollout")
+	EventuallyClusterOperatorsShouldStabilise(30*time.Minute, 30*time.Second)
+}
+
+// ItShouldWaitForNodeReadyBeforeDeletion makes the machine in the given index outdated and checks that, during
+// the rolling update, the old machine is not deleted while the node of the replacement machine is not Ready.
+// A node is considered Ready when its NodeReady condition has status True.
+// The readiness of the replacement node is delayed by the test, so this is a disruptive test and is only run
+// when the CPMS_E2E_ENABLE_DISRUPTIVE_TESTS environment variable is set.
+func ItShouldWaitForNodeReadyBeforeDeletion(testFramework framework.Framework, index int) {
+	It("should wait for the replacement node to be ready before deleting the outdated machine", Offset(1), func() {
+		lookupEnvOrSkip(disruptiveTestsEnvVar)
+
+		const readinessDelay = 5 * time.Minute
+
+		IncreaseControlPlaneMachineInstanceSize(testFramework, index)
+
+		rolloutCtx, cancel := context.WithTimeout(testFramework.GetContext(), 30*time.Minute)
+		defer cancel()
+
+		Expect(EventuallyIndexIsBeingReplaced(rolloutCtx, index)).To(BeTrue(), "index %d should be replaced", index)
+
+		oldMachine, newMachine, ok := getOldAndNewMachineForIndex(rolloutCtx, testFramework, index)
+		Expect(ok).To(BeTrue(), "should find the old and new machines for index %d", index)
+
+		By("Waiting for the replacement machine node to register")
+
+		nodeName := EventuallyNodeForMachine(rolloutCtx, testFramework, newMachine)
+		Expect(nodeName).ToNot(BeEmpty(), "replacement machine node should register")
+
+		By(fmt.Sprintf("Delaying the readiness of node %s by %s", nodeName, readinessDelay))
+
+		wg := &sync.WaitGroup{}
+
+		framework.Async(wg, cancel, func() bool {
+			return Expect(testFramework.DelayNodeReadiness(nodeName, readinessDelay)).To(Succeed(), "should be able to delay node readiness")
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			By("Checking the outdated machine is not deleted while the replacement node is not Ready")
+
+			// Leave a margin so that the check completes before the readiness delay ends.
+			return Consistently(komega.Object(oldMachine)).WithContext(rolloutCtx).WithTimeout(readinessDelay-time.Minute).Should(
+				HaveField("ObjectMeta.DeletionTimestamp", BeNil()),
+				"outdated machine should not be deleted while the replacement node is not Ready",
+			)
+		})
+
+		wg.Wait()
+
+		Expect(rolloutCtx.Err()).ToNot(HaveOccurred(), "node readiness checks should have completed successfully")
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(WaitForControlPlaneMachineSetDesiredReplicas(rolloutCtx, cpms)).To(BeTrue(), "rollout should complete once the node is Ready")
+
+		By("Waiting for the cluster to stabilise after the rollout")
+		EventuallyClusterOperatorsShouldStabilise(30*time.Minute, 30*time.Second)
+	})
+}
+
+// ItShouldHandleMachineWithoutNode checks that the control plane machine set handles a replacement machine whose
+// node never registers with the cluster.
+// The control plane machine set template is updated to boot machines with user data that never joins the cluster,
+// and the machine in the given index is deleted under the OnDelete update strategy so that only that index is replaced.
+// The replacement machine should be treated as unavailable, the old machine should not be removed, and the rollout
+// should be reported as still progressing. The operator does not time out replacements that never join, so it is the
+// Progressing condition, rather than a Degraded condition, that surfaces the stalled replacement.
+// This is a disruptive test and is only run when the CPMS_E2E_ENABLE_DISRUPTIVE_TESTS environment variable is set.
+func ItShouldHandleMachineWithoutNode(testFramework framework.Framework, index int) {
+	It("should handle a replacement machine whose node never registers", Offset(1), func() {
+		lookupEnvOrSkip(disruptiveTestsEnvVar)
+
+		k8sClient := testFramework.GetClient()
+
+		ctx, cancel := context.WithTimeout(testFramework.GetContext(), 30*time.Minute)
+		defer cancel()
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+
+		desiredReplicas := *cpms.Spec.Replicas
+		originalProviderSpec := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec
+		originalStrategy := EnsureControlPlaneMachineSetUpdateStrategy(testFramework, machinev1.OnDelete)
+
+		userDataSecret := createNonJoiningUserDataSecret(testFramework)
+
+		DeferCleanup(func() {
+			Expect(runtimeclient.IgnoreNotFound(k8sClient.Delete(testFramework.GetContext(), userDataSecret))).To(Succeed(), "should be able to delete the user data secret")
+		})
+
+		updatedProviderSpec := originalProviderSpec.DeepCopy()
+		Expect(framework.SetProviderSpecUserDataSecret(updatedProviderSpec.Value, userDataSecret.Name)).To(Succeed(), "provider spec should be updated with the user data secret")
+
+		By("Updating the control plane machine set to boot machines that never join the cluster")
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = *updatedProviderSpec
+		})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+		oldMachine, err := machineForIndex(testFramework, index)
+		Expect(err).ToNot(HaveOccurred(), "should be able to get the machine for index %d", index)
+
+		By(fmt.Sprintf("Deleting the machine in index %d", index))
+		Expect(k8sClient.Delete(ctx, oldMachine)).To(Succeed(), "should be able to delete the machine in index %d", index)
+
+		DeferCleanup(func() {
+			cleanupFailedReplacement(testFramework, index, originalProviderSpec, originalStrategy)
+		})
+
+		Expect(EventuallyIndexIsBeingReplaced(ctx, index)).To(BeTrue(), "index %d should be replaced", index)
+
+		_, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+		Expect(ok).To(BeTrue(), "should find the old and new machines for index %d", index)
+
+		By("Checking the replacement machine is treated as unavailable and the old machine is not removed")
+		Consistently(komega.ObjectList(&machinev1beta1.MachineList{}, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())), 10*time.Minute, 30*time.Second).Should(HaveField("Items", SatisfyAll(
+			ContainElement(SatisfyAll(
+				HaveField("ObjectMeta.Name", Equal(oldMachine.Name)),
+				HaveField("Status.NodeRef", Not(BeNil())),
+			)),
+			ContainElement(SatisfyAll(
+				HaveField("ObjectMeta.Name", Equal(newMachine.Name)),
+				HaveField("Status.NodeRef", BeNil()),
+			)),
+		)), "old machine should remain while the replacement machine has no node")
+
+		Expect(komega.Object(cpms)()).To(HaveField("Status", SatisfyAll(
+			HaveField("Replicas", Equal(desiredReplicas+1)),
+			HaveField("ReadyReplicas", Equal(desiredReplicas)),
+			HaveField("Conditions", ContainElement(SatisfyAll(
+				HaveField("Type", Equal("Progressing")),
+				HaveField("Status", Equal(metav1.ConditionTrue)),
+			))),
+		)), "control plane machine set should report the replacement as unavailable and the rollout as progressing")
+	})
+}
+
+// ItShouldSurfaceProviderErrors checks that the control plane machine set surfaces a provider error encountered while
+// creating a replacement machine.
+// The control plane machine set template is updated with an instance type that does not exist, and the machine in the
+// given index is deleted under the OnDelete update strategy so that only that index is replaced.
+// The cloud provider rejects the replacement machine, which the operator reports as a Degraded condition.
+// The provider error recorded on the replacement machine must be carried by the Degraded message.
+// This is a disruptive test and is only run when the CPMS_E2E_ENABLE_DISRUPTIVE_TESTS environment variable is set.
+func ItShouldSurfaceProviderErrors(testFramework framework.Framework, index int) {
+	It("should surface provider errors for a failed replacement machine", Offset(1), func() {
+		lookupEnvOrSkip(disruptiveTestsEnvVar)
+
+		k8sClient := testFramework.GetClient()
+
+		ctx, cancel := context.WithTimeout(testFramework.GetContext(), 30*time.Minute)
+		defer cancel()
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+
+		originalProviderSpec := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec
+		originalStrategy := EnsureControlPlaneMachineSetUpdateStrategy(testFramework, machinev1.OnDelete)
+
+		updatedProviderSpec := originalProviderSpec.DeepCopy()
+		Expect(framework.SetProviderSpecInstanceType(updatedProviderSpec.Value, "e2e-invalid-instance-type")).To(Succeed(), "provider spec should be updated with the invalid instance type")
+
+		By("Updating the control plane machine set with an invalid instance type")
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = *updatedProviderSpec
+		})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+		oldMachine, err := machineForIndex(testFramework, index)
+		Expect(err).ToNot(HaveOccurred(), "should be able to get the machine for index %d", index)
+
+		By(fmt.Sprintf("Deleting the machine in index %d", index))
+		Expect(k8sClient.Delete(ctx, oldMachine)).To(Succeed(), "should be able to delete the machine in index %d", index)
+
+		DeferCleanup(func() {
+			cleanupFailedReplacement(testFramework, index, originalProviderSpec, originalStrategy)
+		})
+
+		Expect(EventuallyIndexIsBeingReplaced(ctx, index)).To(BeTrue(), "index %d should be replaced", index)
+
+		_, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+		Expect(ok).To(BeTrue(), "should find the old and new machines for index %d", index)
+
+		By("Checking the replacement machine records the provider error")
+		Eventually(komega.Object(newMachine)).WithContext(ctx).Should(
+			HaveField("Status.ErrorMessage", HaveValue(Not(BeEmpty()))),
+			"replacement machine should record the provider error",
+		)
+
+		Expect(ExpectProviderErrorSurfaced(testFramework, pointer.StringDeref(newMachine.Status.ErrorMessage, ""))).To(BeTrue(),
+			"control plane machine set should surface the failed replacement")
+	})
+}
+
+// ItShouldHandleZeroCapacityFailureDomain checks that, when the failure domain assigned to an index cannot provide
+// capacity, the control plane machine set keeps the healthy machine in the index and surfaces the failed replacement.
+// The AWS failure domain used by the machine in index 0 is replaced by a failure domain in an availability zone that
+// has no capacity for the control plane instance type. The failure domain subnet is dropped, so the subnet is chosen
+// by availability zone. Under the RollingUpdate strategy the replacement for index 0 fails to provision, and the
+// original machine must not be removed.
+// This test only applies to AWS. The availability zone is read from the CPMS_E2E_ZERO_CAPACITY_AVAILABILITY_ZONE
+// environment variable and the test is skipped when it is not set.
+// This is a disruptive test and is only run when the CPMS_E2E_ENABLE_DISRUPTIVE_TESTS environment variable is set.
+// The original failure domains are restored, and the failed replacement removed, once the test completes.
+func ItShouldHandleZeroCapacityFailureDomain(testFramework framework.Framework) {
+	It("should keep the healthy machine when the failure domain has no capacity", Offset(1), func() {
+		lookupEnvOrSkip(disruptiveTestsEnvVar)
+
+		if testFramework.GetPlatformType() != configv1.AWSPlatformType {
+			Skip(fmt.Sprintf("Skipping as zero capacity failure domains are not tested on platform %s", testFramework.GetPlatformType()))
+		}
+
+		zone := lookupEnvOrSkip(zeroCapacityZoneEnvVar)
+		index := 0
+
+		ctx, cancel := context.WithTimeout(testFramework.GetContext(), 30*time.Minute)
+		defer cancel()
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+
+		originalFailureDomains := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains.DeepCopy()
+		if originalFailureDomains.AWS == nil || len(*originalFailureDomains.AWS) == 0 {
+			Skip("Skipping as the control plane machine set has no failure domains")
+		}
+
+		oldMachine, err := machineForIndex(testFramework, index)
+		Expect(err).ToNot(HaveOccurred(), "should be able to get the machine for index %d", index)
+		Expect(oldMachine).ToNot(BeNil(), "control plane machine should exist in index %d", index)
+
+		currentZone, err := framework.GetAWSProviderSpecAvailabilityZone(oldMachine.Spec.ProviderSpec.Value)
+		Expect(err).ToNot(HaveOccurred(), "should be able to read the availability zone of machine %s", oldMachine.Name)
+
+		updatedFailureDomains := []machinev1.AWSFailureDomain{}
+
+		for _, failureDomain := range *originalFailureDomains.AWS {
+			if failureDomain.Placement.AvailabilityZone == currentZone {
+				failureDomain.Placement.AvailabilityZone = zone
+				failureDomain.Subnet = nil
+			}
+
+			updatedFailureDomains = append(updatedFailureDomains, failureDomain)
+		}
+
+		originalProviderSpec := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec
+		originalStrategy := EnsureControlPlaneMachineSetUpdateStrategy(testFramework, machinev1.RollingUpdate)
+
+		By(fmt.Sprintf("Replacing the failure domain in availability zone %s with availability zone %s", currentZone, zone))
+
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains.AWS = &updatedFailureDomains
+		})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+		DeferCleanup(func() {
+			By("Restoring the original control plane machine set failure domains")
+
+			Eventually(komega.Update(cpms, func() {
+				cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains = *originalFailureDomains
+			})).Should(Succeed(), "control plane machine set should be able to be restored")
+
+			cleanupFailedReplacement(testFramework, index, originalProviderSpec, originalStrategy)
+		})
+
+		Expect(EventuallyIndexIsBeingReplaced(ctx, index)).To(BeTrue(), "index %d should be replaced", index)
+
+		_, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+		Expect(ok).To(BeTrue(), "should find the old and new machines for index %d", index)
+
+		By("Checking the replacement machine records the provider error")
+		Eventually(komega.Object(newMachine)).WithContext(ctx).Should(
+			HaveField("Status.ErrorMessage", HaveValue(Not(BeEmpty()))),
+			"replacement machine should record the provider error",
+		)
+
+		Expect(ExpectProviderErrorSurfaced(testFramework, pointer.StringDeref(newMachine.Status.ErrorMessage, ""))).To(BeTrue(),
+			"control plane machine set should surface the failed replacement")
+
+		By(fmt.Sprintf("Checking the healthy machine %s is not removed", oldMachine.Name))
+		Consistently(komega.Object(oldMachine), 2*time.Minute, 10*time.Second).Should(
+			HaveField("ObjectMeta.DeletionTimestamp", BeNil()),
+			"healthy machine should not be removed while the replacement has failed",
+		)
+	})
+}
+
+// ItShouldWarnWhenSurgeExceedsQuota checks that the control plane machine set explains why a rollout is stuck when
+// the cloud quota cannot accommodate a surge machine.
+// The template instance type is set to one for which the cloud account has no remaining quota, so that the replacement
+// for index 0 fails to provision under the RollingUpdate strategy.
+// The instance type is read from the CPMS_E2E_QUOTA_EXHAUSTED_INSTANCE_TYPE environment variable and the test is
+// skipped when it is not set.
+// This is a disruptive test and is only run when the CPMS_E2E_ENABLE_DISRUPTIVE_TESTS environment variable is set.
+// The original template is restored, and the failed replacement removed, once the test completes.
+func ItShouldWarnWhenSurgeExceedsQuota(testFramework framework.Framework) {
+	It("should warn when the quota cannot accommodate a surge machine", Offset(1), func() {
+		lookupEnvOrSkip(disruptiveTestsEnvVar)
+
+		substring := quotaErrorSubstring(testFramework.GetPlatformType())
+		if substring == "" {
+			Skip(fmt.Sprintf("Skipping as quota errors are not known for platform %s", testFramework.GetPlatformType()))
+		}
+
+		instanceType := lookupEnvOrSkip(quotaExhaustedInstanceTypeEnvVar)
+		index := 0
+
+		ctx, cancel := context.WithTimeout(testFramework.GetContext(), 30*time.Minute)
+		defer cancel()
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+
+		originalProviderSpec := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec
+		originalStrategy := EnsureControlPlaneMachineSetUpdateStrategy(testFramework, machinev1.RollingUpdate)
+
+		updatedProviderSpec := originalProviderSpec.DeepCopy()
+		Expect(framework.SetProviderSpecInstanceType(updatedProviderSpec.Value, instanceType)).To(Succeed(), "provider spec should be updated with the instance type")
+
+		By(fmt.Sprintf("Updating the control plane machine set with instance type %s, which has no remaining quota", instanceType))
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = *updatedProviderSpec
+		})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+		DeferCleanup(func() {
+			cleanupFailedReplacement(testFramework, index, originalProviderSpec, originalStrategy)
+		})
+
+		Expect(EventuallyIndexIsBeingReplaced(ctx, index)).To(BeTrue(), "index %d should be replaced", index)
+
+		Expect(ExpectSurgeQuotaWarning(testFramework, substring)).To(BeTrue(), "the quota problem should be explained")
+	})
+}
+
+// quotaErrorSubstring returns a substring of the error reported by the cloud provider when an instance cannot be
+// created due to insufficient quota.
+func quotaErrorSubstring(platform configv1.PlatformType) string {
+	switch platform {
+	case configv1.AWSPlatformType:
+		return "LimitExceeded"
+	case configv1.AzurePlatformType:
+		return "QuotaExceeded"
+	case configv1.GCPPlatformType:
+		return "QUOTA_EXCEEDED"
+	default:
+		return ""
+	}
+}
+
+// ItShouldCompleteRolloutDuringCertRotation checks that the control plane machine set completes a rollout while the
+// kube-apiserver serving certificates are being rotated.
+// The serving certificate secrets are deleted, which causes the kube-apiserver operator to regenerate them and roll
+// out a new kube-apiserver revision. The operator's client must reconnect through the resulting connection resets and
+// complete the replacement of the outdated machine in index 1.
+// This is a disruptive test and is only run when the CPMS_E2E_ENABLE_DISRUPTIVE_TESTS environment variable is set.
+func ItShouldCompleteRolloutDuringCertRotation(testFramework framework.Framework) {
+	It("should complete the rollout while the API server certificates are rotated", Offset(1), func() {
+		lookupEnvOrSkip(disruptiveTestsEnvVar)
+
+		index := 1
+		k8sClient := testFramework.GetClient()
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Strategy.Type).To(Equal(machinev1.RollingUpdate), "control plane machine set should use the RollingUpdate update strategy")
+
+		IncreaseControlPlaneMachineInstanceSize(testFramework, index)
+
+		By("Rotating the kube-apiserver serving certificates")
+
+		for _, name := range kubeAPIServerServingCertSecrets {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: kubeAPIServerNamespace,
+				},
+			}
+
+			Expect(runtimeclient.IgnoreNotFound(k8sClient.Delete(testFramework.GetContext(), secret))).To(Succeed(), "should be able to delete the serving certificate %s", name)
+		}
+
+		// The API server restarts during the rotation, so allow longer than usual for the rollout to complete.
+		// The Eventually assertions within the rollout checks retry through the resulting connection errors.
+		rolloutCtx, cancel := context.WithTimeout(testFramework.GetContext(), 90*time.Minute)
+		defer cancel()
+
+		wg := &sync.WaitGroup{}
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckTotalReplicasNeverExceedDesiredPlusOne(testFramework, rolloutCtx)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return WaitForControlPlaneMachineSetDesiredReplicas(rolloutCtx, cpms.DeepCopy())
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckRolloutForIndex(testFramework, rolloutCtx, index, machinev1.RollingUpdate)
+		})
+
+		wg.Wait()
+
+		// If there's an error in the context, either it timed out or one of the async checks failed.
+		Expect(rolloutCtx.Err()).ToNot(HaveOccurred(), "rollout should have completed successfully")
+
+		By("Waiting for the cluster to stabilise after the rollout")
+		EventuallyClusterOperatorsShouldStabilise(45*time.Minute, 30*time.Second)
+	})
+}
+
+// ItShouldValidateThroughWebhookCertRotation checks that the control plane machine set webhook keeps rejecting
+// invalid updates while its serving certificate is rotated.
+// The serving certificate secret is deleted, which causes the service CA operator to regenerate it. Until the operator
+// reloads the new certificate, the webhook may be unreachable, but as the webhook fails closed, invalid updates must
+// still be rejected throughout. Once the new certificate is served, the webhook must again reject invalid updates
+// with its own validation error.
+// The original serving certificate is restored once the test completes.
+// This is a disruptive test and is only run when the CPMS_E2E_ENABLE_DISRUPTIVE_TESTS environment variable is set.
+func ItShouldValidateThroughWebhookCertRotation(testFramework framework.Framework) {
+	It("should keep validating the control plane machine set while the webhook certificate is rotated", Offset(1), func() {
+		lookupEnvOrSkip(disruptiveTestsEnvVar)
+
+		k8sClient := testFramework.GetClient()
+		ctx := testFramework.GetContext()
+
+		Expect(ExpectUnknownPlatformRejected(testFramework)).To(BeTrue(), "webhook should reject invalid updates before the rotation")
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      webhookServingCertSecretName,
+				Namespace: framework.MachineAPINamespace,
+			},
+		}
+
+		Expect(komega.Get(secret)()).To(Succeed(), "webhook serving certificate secret should exist")
+
+		originalSecret := secret.DeepCopy()
+
+		By("Rotating the webhook serving certificate")
+		Expect(k8sClient.Delete(ctx, secret)).To(Succeed(), "should be able to delete the webhook serving certificate")
+
+		DeferCleanup(func() {
+			By("Restoring the original webhook serving certificate")
+
+			Eventually(func() error {
+				current := &corev1.Secret{}
+
+				if err := k8sClient.Get(ctx, runtimeclient.ObjectKeyFromObject(originalSecret), current); apierrors.IsNotFound(err) {
+					restored := originalSecret.DeepCopy()
+					restored.ResourceVersion = ""
+					restored.UID = ""
+
+					return k8sClient.Create(ctx, restored)
+				} else if err != nil {
+					return err
+				}
+
+				current.Annotations = originalSecret.Annotations
+				current.Data = originalSecret.Data
+
+				return k8sClient.Update(ctx, current)
+			}).Should(Succeed(), "webhook serving certificate should be able to be restored")
+		})
+
+		Eventually(komega.Object(secret), 5*time.Minute, 5*time.Second).Should(
+			HaveField("ObjectMeta.UID", Not(Equal(originalSecret.UID))),
+			"webhook serving certificate should be regenerated",
+		)
+
+		By("Checking invalid updates are rejected while the new certificate is loaded")
+		Consistently(func() error {
+			return updateToUnknownFailureDomainsPlatform(testFramework)
+		}, 2*time.Minute, 5*time.Second).Should(HaveOccurred(), "invalid updates should never be admitted during the rotation")
+
+		By("Checking the webhook serves the new certificate")
+		Eventually(func() error {
+			return updateToUnknownFailureDomainsPlatform(testFramework)
+		}, 5*time.Minute, 10*time.Second).Should(
+			MatchError(ContainSubstring(`supported values: "AWS", "Azure", "GCP"`)),
+			"webhook should reject invalid updates with its own validation error after the rotation",
+		)
+
+		Expect(ExpectUnknownPlatformRejected(testFramework)).To(BeTrue(), "webhook should reject invalid updates after the rotation")
+	})
+}
+
+// ItShouldResumeRolloutAfterOperatorRestart checks that the control plane machine set resumes, and completes, a
+// rollout when the operator is restarted part way through.
+// The operator holds no rollout state in memory, so once the replacement machine for index 1 has been created, the
+// operator pod is deleted. The restarted operator must reconstruct the rollout from the machines in the cluster,
+// without creating a duplicate replacement, and without the control plane losing quorum.
+func ItShouldResumeRolloutAfterOperatorRestart(testFramework framework.Framework) {
+	It("should resume the rollout after the operator restarts", Offset(1), func() {
+		index := 1
+		k8sClient := testFramework.GetClient()
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Strategy.Type).To(Equal(machinev1.RollingUpdate), "control plane machine set should use the RollingUpdate update strategy")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+
+		DeferCleanup(func() {
+			By("Waiting for the operator to be available")
+
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      operatorDeploymentName,
+					Namespace: framework.MachineAPINamespace,
+				},
+			}
+
+			Eventually(komega.Object(deployment), 10*time.Minute, 10*time.Second).Should(
+				HaveField("Status.AvailableReplicas", BeNumerically(">", 0)),
+				"operator deployment should be available",
+			)
+		})
+
+		IncreaseControlPlaneMachineInstanceSize(testFramework, index)
+
+		rolloutCtx, cancel := context.WithTimeout(testFramework.GetContext(), 1*time.Hour)
+		defer cancel()
+
+		Expect(EventuallyIndexIsBeingReplaced(rolloutCtx, index)).To(BeTrue(), "index %d should be replaced", index)
+
+		By("Deleting the operator pod part way through the rollout")
+
+		Expect(k8sClient.DeleteAllOf(testFramework.GetContext(), &corev1.Pod{},
+			runtimeclient.InNamespace(framework.MachineAPINamespace),
+			runtimeclient.MatchingLabels{"k8s-app": operatorDeploymentName},
+		)).To(Succeed(), "should be able to delete the operator pod")
+
+		wg := &sync.WaitGroup{}
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckTotalReplicasNeverExceedDesiredPlusOne(testFramework, rolloutCtx)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return WaitForControlPlaneMachineSetDesiredReplicas(rolloutCtx, cpms.DeepCopy())
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckControlPlaneMachineRollingReplacement(testFramework, index, rolloutCtx)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckReadyReplicasNeverBelowQuorum(testFramework, rolloutCtx)
+		})
+
+		wg.Wait()
+
+		// If there's an error in the context, either it timed out or one of the async checks failed.
+		Expect(rolloutCtx.Err()).ToNot(HaveOccurred(), "rollout should have completed successfully")
+
+		By("Waiting for the cluster to stabilise after the rollout")
+		EventuallyClusterOperatorsShouldStabilise(30*time.Minute, 30*time.Second)
+	})
+}
+
+// ItShouldRecoverFromStuckDeletingMachine checks that the control plane machine set recovers from a rollout that
+// left an outdated machine stuck in the Deleting phase.
+// A finalizer is added to the machine in the given index before it is made outdated, so that once the operator
+// deletes it, and the Machine API removes the instance, the Machine object remains. While the machine is stuck,
+// the operator must not create further replacements for the index. Once the finalizer is removed, the outdated
+// machine should be removed and the index left with exactly one machine.
+func ItShouldRecoverFromStuckDeletingMachine(testFramework framework.Framework, index int) {
+	It("should recover from a machine stuck in deletion", Offset(1), func() {
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Strategy.Type).To(Equal(machinev1.RollingUpdate), "control plane machine set should use the RollingUpdate update strategy")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+
+		desiredReplicas := int(*cpms.Spec.Replicas)
+
+		oldMachine, err := machineForIndex(testFramework, index)
+		Expect(err).ToNot(HaveOccurred(), "control plane machine should exist")
+
+		By(fmt.Sprintf("Blocking the deletion of machine %s", oldMachine.Name))
+
+		Eventually(komega.Update(oldMachine, func() {
+			oldMachine.SetFinalizers(append(oldMachine.GetFinalizers(), deletionBlockingFinalizer))
+		})).Should(Succeed(), "should be able to add the deletion blocking finalizer")
+
+		DeferCleanup(func() {
+			removeDeletionBlockingFinalizer(testFramework, oldMachine)
+		})
+
+		IncreaseControlPlaneMachineInstanceSize(testFramework, index)
+
+		ctx, cancel := context.WithTimeout(testFramework.GetContext(), 1*time.Hour)
+		defer cancel()
+
+		Expect(EventuallyIndexIsBeingReplaced(ctx, index)).To(BeTrue(), "index %d should be replaced", index)
+
+		By("Waiting for the outdated machine to be stuck in deletion")
+		Eventually(komega.Object(oldMachine)).WithContext(ctx).Should(SatisfyAll(
+			HaveField("ObjectMeta.DeletionTimestamp", Not(BeNil())),
+			HaveField("ObjectMeta.Finalizers", ConsistOf(deletionBlockingFinalizer)),
+		), "outdated machine should only be held by the deletion blocking finalizer")
+
+		By("Checking no further replacements are created while the machine is stuck in deletion")
+
+		machineList := &machinev1beta1.MachineList{}
+		machineSelector := runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())
+
+		Consistently(komega.ObjectList(machineList, machineSelector), 2*time.Minute, 10*time.Second).Should(
+			HaveField("Items", HaveLen(desiredReplicas+1)),
+			"only a single replacement should exist for the stuck machine",
+		)
+
+		removeDeletionBlockingFinalizer(testFramework, oldMachine)
+
+		By("Waiting for the outdated machine to be removed")
+		Eventually(komega.Get(oldMachine)).WithContext(ctx).Should(
+			MatchError(ContainSubstring("not found")), "outdated machine should be removed",
+		)
+
+		Expect(WaitForControlPlaneMachineSetDesiredReplicas(ctx, cpms.DeepCopy())).To(BeTrue(), "rollout should complete once the machine is removed")
+
+		By(fmt.Sprintf("Checking index %d has exactly one machine", index))
+		Expect(komega.List(machineList, machineSelector)()).To(Succeed(), "should be able to list machines")
+
+		indexCounts, err := extractMachineIndexCounts(machineList.Items)
+		Expect(err).ToNot(HaveOccurred(), "should be able to determine the machine indexes")
+		Expect(indexCounts).To(HaveKeyWithValue(index, 1), "index %d should have exactly one machine", index)
+
+		By("Waiting for the cluster to stabilise after the rollout")
+		EventuallyClusterOperatorsShouldStabilise(30*time.Minute, 30*time.Second)
+	})
+}
+
+// ItShouldHonourPreTerminateHookDuringRollout checks that a pre-terminate hook on an outdated machine is honoured
+// during a rolling update.
+// The hook is added to the machine in the given index before the index is made outdated. The machine should be
+// drained, once the etcd pre-drain hook is removed, but it should not be removed until the pre-terminate hook is
+// removed, after which the rollout should complete.
+func ItShouldHonourPreTerminateHookDuringRollout(testFramework framework.Framework, index int) {
+	It("should honour a pre-terminate hook on the outdated machine", Offset(1), func() {
+		const hookName = "cpms-e2e-pre-terminate"
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Strategy.Type).To(Equal(machinev1.RollingUpdate), "control plane machine set should use the RollingUpdate update strategy")
+
+		machine, err := machineForIndex(testFramework, index)
+		Expect(err).ToNot(HaveOccurred(), "control plane machine should exist")
+
+		By(fmt.Sprintf("Adding the pre-terminate hook %s to machine %s", hookName, machine.Name))
+
+		Eventually(komega.Update(machine, func() {
+			addPreTerminateHook(machine, hookName)
+		})).Should(Succeed(), "should be able to add the pre-terminate hook")
+
+		DeferCleanup(func() {
+			if err := komega.Get(machine)(); err != nil {
+				return
+			}
+
+			Eventually(komega.Update(machine, func() {
+				removePreTerminateHook(machine, hookName)
+			})).Should(Succeed(), "should be able to remove the pre-terminate hook")
+		})
+
+		IncreaseControlPlaneMachineInstanceSize(testFramework, index)
+
+		rolloutCtx, cancel := context.WithTimeout(testFramework.GetContext(), 1*time.Hour)
+		defer cancel()
+
+		Expect(EventuallyIndexIsBeingReplaced(rolloutCtx, index)).To(BeTrue(), "index %d should be replaced", index)
+		Expect(ExpectPreTerminateHookCompletes(testFramework, rolloutCtx, index, hookName)).To(BeTrue(), "pre-terminate hook should block the removal of the outdated machine until removed")
+		Expect(WaitForControlPlaneMachineSetDesiredReplicas(rolloutCtx, cpms.DeepCopy())).To(BeTrue(), "rollout should complete once the machine is removed")
+
+		By("Waiting for the cluster to stabilise after the rollout")
+		EventuallyClusterOperatorsShouldStabilise(30*time.Minute, 30*time.Second)
+	})
+}
+
+// ItShouldNotDoubleActWithMultipleReplicas checks that, when the operator runs with more than one replica, only the
+// leader acts on the control plane machine set.
+// The operator is scaled to two replicas before the machine in index 0 is made outdated. Throughout the rollout the
+// total number of machines must not exceed the surge capacity, and exactly one new machine, identified by its UID,
+// must be created for the index. The operator deployment is managed by the cluster version operator, so it is marked
+// as unmanaged while the test runs to prevent the replica count being reverted. The original replica count is restored
+// once the test completes.
+func ItShouldNotDoubleActWithMultipleReplicas(testFramework framework.Framework) {
+	It("should not create duplicate machines when the operator has multiple replicas", Offset(1), func() {
+		index := 0
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Strategy.Type).To(Equal(machinev1.RollingUpdate), "control plane machine set should use the RollingUpdate update strategy")
+
+		machineList := &machinev1beta1.MachineList{}
+		machineSelector := runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())
+		Expect(komega.List(machineList, machineSelector)()).To(Succeed(), "should be able to list machines")
+
+		originalUIDs := map[types.UID]struct{}{}
+
+		for _, machine := range machineList.Items {
+			originalUIDs[machine.UID] = struct{}{}
+		}
+
+		setDeploymentUnmanaged(operatorDeploymentName)
+
+		originalReplicas := scaleDeployment(testFramework, operatorDeploymentName, 2)
+
+		DeferCleanup(func() {
+			scaleDeployment(testFramework, operatorDeploymentName, originalReplicas)
+		})
+
+		IncreaseControlPlaneMachineInstanceSize(testFramework, index)
+
+		rolloutCtx, cancel := context.WithTimeout(testFramework.GetContext(), 1*time.Hour)
+		defer cancel()
+
+		wg := &sync.WaitGroup{}
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckTotalReplicasNeverExceedDesiredPlusOne(testFramework, rolloutCtx)
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return WaitForControlPlaneMachineSetDesiredReplicas(rolloutCtx, cpms.DeepCopy())
+		})
+
+		framework.Async(wg, cancel, func() bool {
+			return CheckControlPlaneMachineRollingReplacement(testFramework, index, rolloutCtx)
+		})
+
+		wg.Wait()
+
+		// If there's an error in the context, either it timed out or one of the async checks failed.
+		Expect(rolloutCtx.Err()).ToNot(HaveOccurred(), "rollout should have completed successfully")
+
+		By("Checking exactly one new machine was created during the rollout")
+		Expect(komega.List(machineList, machineSelector)()).To(Succeed(), "should be able to list machines")
+
+		newMachines := []string{}
+
+		for _, machine := range machineList.Items {
+			if _, ok := originalUIDs[machine.UID]; !ok {
+				newMachines = append(newMachines, machine.Name)
+			}
+		}
+
+		Expect(newMachines).To(HaveLen(1), "only the leader should create a replacement machine")
+
+		By("Waiting for the cluster to stabilise after the rollout")
+		EventuallyClusterOperatorsShouldStabilise(30*time.Minute, 30*time.Second)
+	})
+}
+
+// ItShouldDegradeOnMissingUserDataSecret checks that the control plane machine set does not remove healthy machines
+// when the template references a user data secret that does not exist.
+// The operator does not validate the secret itself. Under the RollingUpdate strategy, it creates a replacement for
+// index 0, which the Machine API fails as the user data cannot be read. The operator then reports a Degraded
+// condition carrying the error of the replacement machine, which must name the missing secret. The outdated,
+// healthy, machines must not be removed.
+// This is a disruptive test and is only run when the CPMS_E2E_ENABLE_DISRUPTIVE_TESTS environment variable is set.
+func ItShouldDegradeOnMissingUserDataSecret(testFramework framework.Framework) {
+	It("should degrade when the user data secret does not exist", Offset(1), func() {
+		lookupEnvOrSkip(disruptiveTestsEnvVar)
+
+		index := 0
+		missingSecretName := "e2e-missing-user-data"
+
+		ctx, cancel := context.WithTimeout(testFramework.GetContext(), 30*time.Minute)
+		defer cancel()
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+
+		desiredReplicas := int(*cpms.Spec.Replicas)
+		originalProviderSpec := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec
+		originalStrategy := EnsureControlPlaneMachineSetUpdateStrategy(testFramework, machinev1.RollingUpdate)
+
+		updatedProviderSpec := originalProviderSpec.DeepCopy()
+		Expect(framework.SetProviderSpecUserDataSecret(updatedProviderSpec.Value, missingSecretName)).To(Succeed(), "provider spec should be updated with the missing user data secret")
+
+		By("Updating the control plane machine set with a user data secret that does not exist")
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = *updatedProviderSpec
+		})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+		DeferCleanup(func() {
+			cleanupFailedReplacement(testFramework, index, originalProviderSpec, originalStrategy)
+		})
+
+		Expect(EventuallyIndexIsBeingReplaced(ctx, index)).To(BeTrue(), "index %d should be replaced", index)
+
+		_, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+		Expect(ok).To(BeTrue(), "should find the old and new machines for index %d", index)
+
+		By("Checking the replacement machine records the missing user data secret")
+		Eventually(komega.Object(newMachine)).WithContext(ctx).Should(
+			HaveField("Status.ErrorMessage", HaveValue(ContainSubstring(missingSecretName))),
+			"replacement machine should record the missing user data secret",
+		)
+
+		Expect(ExpectProviderErrorSurfaced(testFramework, missingSecretName)).To(BeTrue(),
+			"control plane machine set should surface the failed replacement")
+
+		By("Checking the healthy machines are not removed")
+		Consistently(komega.ObjectList(&machinev1beta1.MachineList{}, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())), 2*time.Minute, 10*time.Second).Should(
+			HaveField("Items", SatisfyAll(
+				HaveLen(desiredReplicas+1),
+				HaveEach(HaveField("ObjectMeta.DeletionTimestamp", BeNil())),
+			)), "healthy control plane machines should not be removed while the replacement has failed",
+		)
+	})
+}
+
+// ItShouldBackoffOnRepeatedProvisionFailures checks that the control plane machine set does not repeatedly create
+// replacement machines when they fail to provision, and never removes the healthy machines they would replace.
+// The control plane machine set template is updated with an instance type that does not exist. Under the
+// RollingUpdate strategy, the operator creates a replacement for index 0, which the cloud provider rejects.
+// The operator does not retry failed replacements itself, instead it reports a Degraded condition until the failed
+// machine is removed. The failed replacement is deleted a number of times, and each time the operator must create
+// exactly one new attempt. The creation timestamps of the attempts are then checked to be spaced by at least the
+// observation window, so that a bad rollout cannot storm the cloud API.
+// This is a disruptive test and is only run when the CPMS_E2E_ENABLE_DISRUPTIVE_TESTS environment variable is set.
+func ItShouldBackoffOnRepeatedProvisionFailures(testFramework framework.Framework) {
+	It("should not repeatedly create replacements that fail to provision", Offset(1), func() {
+		lookupEnvOrSkip(disruptiveTestsEnvVar)
+
+		const (
+			provisionAttempts = 3
+			observationWindow = 2 * time.Minute
+		)
+
+		index := 0
+		k8sClient := testFramework.GetClient()
+
+		ctx, cancel := context.WithTimeout(testFramework.GetContext(), 1*time.Hour)
+		defer cancel()
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+
+		machineList := &machinev1beta1.MachineList{}
+		machineSelector := runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())
+		Expect(komega.List(machineList, machineSelector)()).To(Succeed(), "should be able to list machines")
+
+		originalUIDs := map[types.UID]struct{}{}
+
+		for _, machine := range machineList.Items {
+			originalUIDs[machine.UID] = struct{}{}
+		}
+
+		originalProviderSpec := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec
+		originalStrategy := EnsureControlPlaneMachineSetUpdateStrategy(testFramework, machinev1.RollingUpdate)
+
+		updatedProviderSpec := originalProviderSpec.DeepCopy()
+		Expect(framework.SetProviderSpecInstanceType(updatedProviderSpec.Value, "e2e-invalid-instance-type")).To(Succeed(), "provider spec should be updated with the invalid instance type")
+
+		By("Updating the control plane machine set with an invalid instance type")
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = *updatedProviderSpec
+		})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+		DeferCleanup(func() {
+			cleanupFailedReplacement(testFramework, index, originalProviderSpec, originalStrategy)
+		})
+
+		// replacementMachines returns the machines created since the test started.
+		replacementMachines := func() ([]machinev1beta1.Machine, error) {
+			if err := komega.List(machineList, machineSelector)(); err != nil {
+				return nil, err
+			}
+
+			machines := []machinev1beta1.Machine{}
+
+			for _, machine := range machineList.Items {
+				if _, ok := originalUIDs[machine.UID]; !ok {
+					machines = append(machines, machine)
+				}
+			}
+
+			return machines, nil
+		}
+
+		attempts := []metav1.Time{}
+
+		var lastErrorMessage string
+
+		for attempt := 1; attempt <= provisionAttempts; attempt++ {
+			By(fmt.Sprintf("Waiting for provisioning attempt %d to fail", attempt))
+
+			Eventually(replacementMachines).WithContext(ctx).Should(ConsistOf(SatisfyAll(
+				HaveField("ObjectMeta.Name", HaveSuffix(fmt.Sprintf("-%d", index))),
+				HaveField("Status.ErrorMessage", HaveValue(Not(BeEmpty()))),
+			)), "a single failed replacement machine should exist")
+
+			failedMachines, err := replacementMachines()
+			Expect(err).ToNot(HaveOccurred(), "should be able to list the replacement machines")
+			Expect(failedMachines).To(HaveLen(1), "a single failed replacement machine should exist")
+
+			failedMachine := failedMachines[0]
+			attempts = append(attempts, failedMachine.CreationTimestamp)
+			lastErrorMessage = pointer.StringDeref(failedMachine.Status.ErrorMessage, "")
+
+			By("Checking no further replacements are created and the healthy machines are not removed")
+			Consistently(komega.ObjectList(machineList, machineSelector), observationWindow, 10*time.Second).Should(HaveField("Items", SatisfyAll(
+				HaveLen(len(originalUIDs)+1),
+				HaveEach(HaveField("ObjectMeta.DeletionTimestamp", BeNil())),
+			)), "the operator should not create further replacements or remove healthy machines")
+
+			if attempt == provisionAttempts {
+				break
+			}
+
+			By(fmt.Sprintf("Deleting the failed replacement machine %s", failedMachine.Name))
+			Expect(k8sClient.Delete(ctx, &failedMachine)).To(Succeed(), "should be able to delete the failed replacement machine")
+			Eventually(komega.Get(&failedMachine)).WithContext(ctx).Should(MatchError(ContainSubstring("not found")), "failed replacement machine should be removed")
+		}
+
+		By("Checking the provisioning attempts were spaced out")
+
+		for i := 1; i < len(attempts); i++ {
+			Expect(attempts[i].Sub(attempts[i-1].Time)).To(BeNumerically(">=", observationWindow),
+				"provisioning attempt %d should not be created within %s of the previous attempt", i+1, observationWindow)
+		}
+
+		Expect(ExpectProviderErrorSurfaced(testFramework, lastErrorMessage)).To(BeTrue(),
+			"control plane machine set should surface the failed replacement")
+	})
+}
+
+// ItShouldRejectAnEmptyTemplate checks that the control plane machine set cannot be updated to remove its
+// machine template.
+func ItShouldRejectAnEmptyTemplate(testFramework framework.Framework) {
+	It("should reject an update removing the machine template", Offset(1), func() {
+		Expect(ExpectEmptyTemplateRejected(testFramework)).To(BeTrue(), "an empty machine template should be rejected")
+	})
+}
+
+// ItShouldRejectUnknownFailureDomainsPlatform checks that the control plane machine set cannot be updated to use a
+// failure domains platform the operator does not support failure domains for.
+func ItShouldRejectUnknownFailureDomainsPlatform(testFramework framework.Framework) {
+	It("should reject an update to an unknown failure domains platform", Offset(1), func() {
+		Expect(ExpectUnknownPlatformRejected(testFramework)).To(BeTrue(), "an unknown failure domains platform should be rejected")
+	})
+}
+
+// ItShouldRejectZeroReplicas checks that the control plane machine set cannot be scaled to zero replicas.
+func ItShouldRejectZeroReplicas(testFramework framework.Framework) {
+	It("should reject an update to zero replicas", Offset(1), func() {
+		Expect(ExpectZeroReplicasRejected(testFramework)).To(BeTrue(), "zero replicas should be rejected")
+	})
+}
+
+// ItShouldRequireFailureDomainOnAWS checks how an AWS control plane machine set without failure domains is handled.
+// Without failure domains, the failure domain is derived from the availability zone in the template provider spec, so
+// the control plane machine set is only accepted when every control plane machine is in that availability zone, as on
+// a single zone cluster. Otherwise the webhook rejects it, as the machines would be moved into a single zone.
+// The control plane machine set is not modified: a dry run create of a copy without failure domains is used, which,
+// when the webhook accepts it, fails only because the control plane machine set already exists.
+func ItShouldRequireFailureDomainOnAWS(testFramework framework.Framework) {
+	It("should require failure domains on AWS unless the machines share the template zone", Offset(1), func() {
+		if testFramework.GetPlatformType() != configv1.AWSPlatformType {
+			Skip(fmt.Sprintf("Skipping as this test only applies to AWS, not platform %s", testFramework.GetPlatformType()))
+		}
+
+		k8sClient := testFramework.GetClient()
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+
+		originalGeneration := cpms.Generation
+
+		templateProviderConfig, err := providerconfig.NewProviderConfigFromMachineTemplate(*cpms.Spec.Template.OpenShiftMachineV1Beta1Machine)
+		Expect(err).ToNot(HaveOccurred(), "should be able to parse the template provider spec")
+
+		templateFailureDomain := templateProviderConfig.ExtractFailureDomain()
+
+		machineList := &machinev1beta1.MachineList{}
+		Expect(komega.List(machineList, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels()))()).To(Succeed(), "should be able to list machines")
+
+		machineFailureDomains, err := providerconfig.ExtractFailureDomainsFromMachines(machineList.Items)
+		Expect(err).ToNot(HaveOccurred(), "should be able to get the failure domains of the control plane machines")
+
+		machinesInTemplateZone := true
+
+		for _, failureDomain := range machineFailureDomains {
+			if !templateFailureDomain.Equal(failureDomain) {
+				machinesInTemplateZone = false
+			}
+		}
+
+		withoutFailureDomains := &machinev1.ControlPlaneMachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cpms.Name,
+				Namespace: cpms.Namespace,
+			},
+			Spec: *cpms.Spec.DeepCopy(),
+		}
+		withoutFailureDomains.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains = machinev1.FailureDomains{}
+
+		By("Creating a control plane machine set without failure domains with a dry run")
+
+		err = k8sClient.Create(testFramework.GetContext(), withoutFailureDomains, runtimeclient.DryRunAll)
+
+		if machinesInTemplateZone {
+			By(fmt.Sprintf("All control plane machines are in the template failure domain %s", templateFailureDomain))
+
+			Expect(apierrors.IsAlreadyExists(err)).To(BeTrue(),
+				"control plane machine set without failure domains should be accepted when all machines are in the template failure domain, got: %v", err)
+		} else {
+			By(fmt.Sprintf("Control plane machines are in failure domains %s, not only the template failure domain %s", machineFailureDomains, templateFailureDomain))
+
+			Expect(err).To(MatchError(ContainSubstring("Failure domain extracted from machine template providerSpec does not match failure domain of all control plane machines")),
+				"control plane machine set without failure domains should be rejected when the machines are in other failure domains")
+		}
+
+		Expect(komega.Object(cpms)()).To(HaveField("ObjectMeta.Generation", Equal(originalGeneration)),
+			"control plane machine set should not be modified")
+	})
+}
+
+// ItShouldRejectInvalidStrategies checks that the control plane machine set cannot be updated to use an update
+// strategy outside of those supported for control planes, including Recreate.
+func ItShouldRejectInvalidStrategies(testFramework framework.Framework) {
+	It("should reject an update to an unsupported strategy", Offset(1), func() {
+		for _, strategy := range []string{string(machinev1.Recreate), "Invalid"} {
+			Expect(ExpectInvalidStrategyRejected(testFramework, strategy)).To(BeTrue(), "the %s strategy should be rejected", strategy)
+		}
+	})
+}
+
+// ItShouldOnlySurgeByOne checks that the surge of the control plane machine set is fixed at one machine, and cannot
+// be configured through the control plane machine set spec.
+func ItShouldOnlySurgeByOne(testFramework framework.Framework) {
+	It("should only surge by one machine", Offset(1), func() {
+		Expect(ExpectSurgeRespected(testFramework, 1)).To(BeTrue(), "control plane machine set should surge by one machine")
+	})
+}
+
+// ItShouldRecreateAllMachines checks the behaviour of the Recreate update strategy for control plane machine sets.
+// Recreate removes a machine before creating its replacement, which would risk the quorum of etcd, and so the API
+// does not allow the Recreate strategy for control plane machine sets. Whether the strategy is allowed is detected
+// with a dry run update: when it is rejected, the check is that the rejection clearly names the supported strategies.
+// Should the API allow the strategy, the operator does not yet implement it, and so must report the strategy as
+// invalid through its Degraded condition without deleting any machine. Once the test completes, the original strategy
+// is restored.
+func ItShouldRecreateAllMachines(testFramework framework.Framework) {
+	It("should not recreate control plane machines with the Recreate strategy", Offset(1), func() {
+		k8sClient := testFramework.GetClient()
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+
+		originalStrategy := cpms.Spec.Strategy.Type
+
+		recreateCPMS := cpms.DeepCopy()
+		recreateCPMS.Spec.Strategy.Type = machinev1.Recreate
+
+		By("Checking whether the Recreate strategy is allowed")
+
+		err := k8sClient.Update(testFramework.GetContext(), recreateCPMS, runtimeclient.DryRunAll)
+		if apierrors.IsInvalid(err) {
+			By("The Recreate strategy is not allowed for control plane machine sets")
+
+			Expect(err).To(MatchError(ContainSubstring("Unsupported value: %q: supported values: \"RollingUpdate\", \"OnDelete\"", machinev1.Recreate)),
+				"the rejection should name the supported strategies")
+
+			return
+		}
+
+		Expect(err).ToNot(HaveOccurred(), "dry run update to the Recreate strategy should succeed")
+
+		DeferCleanup(func() {
+			EnsureControlPlaneMachineSetUpdateStrategy(testFramework, originalStrategy)
+		})
+
+		EnsureControlPlaneMachineSetUpdateStrategy(testFramework, machinev1.Recreate)
+
+		By("Checking the operator reports the Recreate strategy as invalid")
+		Eventually(komega.Object(cpms)).Should(HaveField("Status.Conditions", ContainElement(SatisfyAll(
+			HaveField("Type", Equal("Degraded")),
+			HaveField("Status", Equal(metav1.ConditionTrue)),
+			HaveField("Reason", Equal(invalidStrategyReason)),
+		))), "control plane machine set should be degraded with an invalid strategy")
+
+		By("Checking the control plane machines are not deleted")
+		Consistently(komega.ObjectList(&machinev1beta1.MachineList{}, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels()))).Should(
+			HaveField("Items", HaveEach(HaveField("ObjectMeta.DeletionTimestamp", BeNil()))),
+			"control plane machines should not be deleted with the Recreate strategy",
+		)
+	})
+}
+
+// ItShouldSurfaceExcessiveTagsError checks that the control plane machine set surfaces the provider error when the
+// template sets more tags than the cloud allows, without removing the healthy machines.
+// This is a disruptive test and is only run when the CPMS_E2E_ENABLE_DISRUPTIVE_TESTS environment variable is set.
+func ItShouldSurfaceExcessiveTagsError(testFramework framework.Framework) {
+	It("should surface the provider error when the template has too many tags", Offset(1), func() {
+		lookupEnvOrSkip(disruptiveTestsEnvVar)
+
+		Expect(ExpectExcessiveTagsRejected(testFramework)).To(BeTrue(), "excessive tags should be rejected without removing healthy machines")
+	})
+}
+
+// ItShouldDegradeOnInvalidSubnet checks that the control plane machine set surfaces the provider error when the
+// template references a subnet that does not exist, without removing the healthy machines.
+// This is a disruptive test and is only run when the CPMS_E2E_ENABLE_DISRUPTIVE_TESTS environment variable is set.
+func ItShouldDegradeOnInvalidSubnet(testFramework framework.Framework) {
+	It("should surface the provider error when the template references an invalid subnet", Offset(1), func() {
+		lookupEnvOrSkip(disruptiveTestsEnvVar)
+
+		Expect(ExpectInvalidSubnetRejected(testFramework)).To(BeTrue(), "invalid subnet should be rejected without removing healthy machines")
+	})
+}
+
+// ItShouldRecoverAfterMachineAPIOutage checks that the control plane machine set completes a rollout once the
+// Machine API controllers recover from an outage.
+// The control plane machine set creates the replacement Machine, but relies on the Machine API controllers to
+// provision it. The Machine API operator, and the Machine API controllers it manages, are scaled to zero before the
+// machine in index 0 is made outdated. While the Machine API is unavailable, the outdated machine must not
+// be removed. Once the Machine API is restored, the rollout should complete.
+// This is a disruptive test and is only run when the CPMS_E2E_ENABLE_DISRUPTIVE_TESTS environment variable is set.
+func ItShouldRecoverAfterMachineAPIOutage(testFramework framework.Framework) {
+	It("should complete the rollout after a Machine API outage", Offset(1), func() {
+		lookupEnvOrSkip(disruptiveTestsEnvVar)
+
+		index := 0
+
+		cpms := testFramework.NewEmptyControlPlaneMachineSet()
+		Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+		Expect(cpms.Spec.Strategy.Type).To(Equal(machinev1.RollingUpdate), "control plane machine set should use the RollingUpdate update strategy")
+
+		By("Disrupting the Machine API controllers")
+
+		machineAPIDeploymentNames := []string{machineAPIOperatorDeploymentName, machineAPIControllersDeploymentName}
+		originalReplicas := map[string]int32{}
+
+		// The operator must be scaled down first, otherwise it restores the controllers deployment.
+		for _, name := range machineAPIDeploymentNames {
+			originalReplicas[name] = scaleDeployment(testFramework, name, 0)
+
+			DeferCleanup(func(name string, replicas int32) {
+				scaleDeployment(testFramework, name, replicas)
+			}, name, originalReplicas[name])
+		}
+
+		oldMachine, err := machineForIndex(testFramework, index)
+		Expect(err).ToNot(HaveOccurred(), "should be able to get the machine for index %d", index)
+
+		IncreaseControlPlaneMachineInstanceSize(testFramework, index)
+
+		By("Checking the outdated machine is not removed while the Machine API is unavailable")
+		Consistently(komega.Object(oldMachine), 2*time.Minute, 10*time.Second).Should(
+			HaveField("ObjectMeta.DeletionTimestamp", BeNil()),
+			"outdated machine should not be removed while its replacement cannot be provisioned",
+		)
+
+		By("Restoring the Machine API controllers")
+
+		for _, name := range machineAPIDeploymentNames {
+			scaleDeployment(testFramework, name, originalReplicas[name])
+		}
+
+		rolloutCtx, cancel := context.WithTimeout(testFramework.GetContext(), 1*time.Hour)
+		defer cancel()
+
+		Expect(WaitForControlPlaneMachineSetDesiredReplicas(rolloutCtx, cpms.DeepCopy())).To(BeTrue(), "control plane machine set should complete the rollout")
+		Eventually(komega.Get(oldMachine)).WithContext(rolloutCtx).Should(MatchError(ContainSubstring("not found")), "outdated machine should be replaced")
+
+		By("Waiting for the cluster to stabilise after the rollout")
+		EventuallyClusterOperatorsShouldStabilise(30*time.Minute, 30*time.Second)
+	})
+}
+
+// ItShouldReportNotReadyWithoutAPIAccess checks that the operator readiness probe reflects whether the operator
+// is able to reconcile.
+// A network policy denying all egress from the operator pods is created, which prevents the operator from reaching
+// the API server. The operator pods should then report as not ready. Once the network policy is removed, the
+// operator pods should report as ready again.
+// This is a disruptive test and is only run when the CPMS_E2E_ENABLE_DISRUPTIVE_TESTS environment variable is set.
+func ItShouldReportNotReadyWithoutAPIAccess(testFramework framework.Framework) {
+	It("should report the operator as not ready while it cannot reach the API server", Offset(1), func() {
+		lookupEnvOrSkip(disruptiveTestsEnvVar)
+
+		Expect(ExpectOperatorReadyProbeHealthy(testFramework)).To(BeTrue(), "operator should be ready before the API server is blocked")
+
+		By("Blocking egress from the operator pods")
+
+		policy := &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "e2e-deny-operator-egress",
+				Namespace: framework.MachineAPINamespace,
+			},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"k8s-app": operatorDeploymentName},
+				},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			},
+		}
+
+		Expect(testFramework.GetClient().Create(testFramework.GetContext(), policy)).To(Succeed(), "should be able to create the network policy")
+
+		DeferCleanup(func() {
+			By("Removing the network policy blocking egress from the operator pods")
+			Expect(runtimeclient.IgnoreNotFound(testFramework.GetClient().Delete(testFramework.GetContext(), policy))).To(Succeed(), "should be able to delete the network policy")
+		})
+
+		By("Checking the operator pods report as not ready")
+		Eventually(operatorPods(), 5*time.Minute, 10*time.Second).Should(
+			HaveField("Items", ContainElement(podReadyCondition(corev1.ConditionFalse))),
+			"operator pods should not be ready while the API server cannot be reached",
+		)
+
+		By("Restoring egress from the operator pods")
+		Expect(testFramework.GetClient().Delete(testFramework.GetContext(), policy)).To(Succeed(), "should be able to delete the network policy")
+
+		Expect(ExpectOperatorReadyProbeHealthy(testFramework)).To(BeTrue(), "operator should be ready once the API server can be reached")
+	})
+}
+
+// setDeploymentUnmanaged adds an override to the cluster version marking the named deployment, in the Machine API
+// namespace, as unmanaged, so that the cluster version operator does not revert changes made to it.
+// The original overrides are restored once the test completes.
+func setDeploymentUnmanaged(name string) {
+	clusterVersion := &configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "version",
+		},
+	}
+
+	Expect(komega.Get(clusterVersion)()).To(Succeed(), "cluster version should exist")
+
+	originalOverrides := clusterVersion.Spec.Overrides
+
+	By(fmt.Sprintf("Marking deployment %s as unmanaged by the cluster version operator", name))
+	Eventually(komega.Update(clusterVersion, func() {
+		clusterVersion.Spec.Overrides = append(clusterVersion.Spec.Overrides, configv1.ComponentOverride{
+			Kind:      "Deployment",
+			Group:     appsv1.GroupName,
+			Namespace: framework.MachineAPINamespace,
+			Name:      name,
+			Unmanaged: true,
+		})
+	})).Should(Succeed(), "cluster version should be able to be updated")
+
+	DeferCleanup(func() {
+		By(fmt.Sprintf("Restoring the cluster version operator management of deployment %s", name))
+		Eventually(komega.Update(clusterVersion, func() {
+			clusterVersion.Spec.Overrides = originalOverrides
+		})).Should(Succeed(), "cluster version should be able to be restored")
+	})
+}
+
+// scaleDeployment scales the named deployment, in the Machine API namespace, to the given number of replicas,
+// and returns the number of replicas it had previously.
+// When scaling up, it waits for the deployment to have the given number of available replicas.
+func scaleDeployment(testFramework framework.Framework, name string, replicas int32) int32 {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: framework.MachineAPINamespace,
+		},
+	}
+
+	Expect(komega.Get(deployment)()).To(Succeed(), "deployment %s should exist", name)
+
+	originalReplicas := pointer.Int32Deref(deployment.Spec.Replicas, 1)
+
+	By(fmt.Sprintf("Scaling deployment %s to %d replicas", name, replicas))
+	Eventually(komega.Update(deployment, func() {
+		deployment.Spec.Replicas = pointer.Int32(replicas)
+	})).Should(Succeed(), "deployment %s should be able to be scaled", name)
+
+	if replicas > 0 {
+		Eventually(komega.Object(deployment), 10*time.Minute, 10*time.Second).Should(
+			HaveField("Status.AvailableReplicas", Equal(replicas)),
+			"deployment %s should have %d available replicas", name, replicas,
+		)
+	}
+
+	return originalReplicas
+}
+
+// removeDeletionBlockingFinalizer removes the deletion blocking finalizer from the machine, if it still exists.
+func removeDeletionBlockingFinalizer(testFramework framework.Framework, machine *machinev1beta1.Machine) {
+	By(fmt.Sprintf("Removing the deletion blocking finalizer from machine %s", machine.Name))
+
+	Eventually(func() error {
+		if err := komega.Get(machine)(); err != nil {
+			return runtimeclient.IgnoreNotFound(err)
+		}
+
+		finalizers := []string{}
+
+		for _, finalizer := range machine.GetFinalizers() {
+			if finalizer != deletionBlockingFinalizer {
+				finalizers = append(finalizers, finalizer)
+			}
+		}
+
+		machine.SetFinalizers(finalizers)
+
+		return runtimeclient.IgnoreNotFound(testFramework.GetClient().Update(testFramework.GetContext(), machine))
+	}).Should(Succeed(), "should be able to remove the deletion blocking finalizer")
+}
+
+// createNonJoiningUserDataSecret creates a user data secret, based on the existing control plane user data, that boots
+// an instance which never joins the cluster.
+func createNonJoiningUserDataSecret(testFramework framework.Framework) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "master-user-data-no-join-",
+			Namespace:    framework.MachineAPINamespace,
+		},
+		StringData: map[string]string{
+			// An empty, valid ignition config boots the operating system without any kubelet configuration.
+			"userData": `{"ignition":{"version":"3.2.0"}}`,
+		},
+	}
+
+	Expect(testFramework.GetClient().Create(testFramework.GetContext(), secret)).To(Succeed(), "should be able to create the user data secret")
+
+	return secret
+}
+
+// cleanupFailedReplacement restores the control plane machine set after a test that deliberately created a broken
+// replacement machine in the given index, removing the replacement machines without a node so that a working
+// replacement is created.
+func cleanupFailedReplacement(testFramework framework.Framework, index int, originalProviderSpec machinev1beta1.ProviderSpec,
+	originalStrategy machinev1.ControlPlaneMachineSetStrategyType) {
+	k8sClient := testFramework.GetClient()
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	ctx, cancel := context.WithTimeout(testFramework.GetContext(), 1*time.Hour)
+	defer cancel()
+
+	By("Restoring the original control plane machine set provider spec")
+	Eventually(komega.Update(cpms, func() {
+		cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = originalProviderSpec
+	})).Should(Succeed(), "control plane machine set should be able to be updated")
+
+	By("Deleting the replacement machines without a node")
+
+	machineList := &machinev1beta1.MachineList{}
+	Expect(k8sClient.List(ctx, machineList, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels()))).To(Succeed(), "should be able to list machines")
+
+	for i := range machineList.Items {
+		machine := &machineList.Items[i]
+
+		if idx, err := machineIndex(*machine); err != nil || idx != index || machine.Status.NodeRef != nil {
+			continue
+		}
+
+		Expect(runtimeclient.IgnoreNotFound(k8sClient.Delete(ctx, machine))).To(Succeed(), "should be able to delete machine %s", machine.Name)
+	}
+
+	if ok := WaitForControlPlaneMachineSetDesiredReplicas(ctx, cpms.DeepCopy()); !ok {
+		Fail("control plane machine set should return to the desired replicas")
+	}
+
+	EnsureControlPlaneMachineSetUpdateStrategy(testFramework, originalStrategy)
+
+	EventuallyClusterOperatorsShouldStabilise(30*time.Minute, 30*time.Second)
+}
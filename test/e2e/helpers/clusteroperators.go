@@ -17,12 +17,21 @@ limitations under the License.
 package helpers
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/format"
+	gomegatypes "github.com/onsi/gomega/types"
 
 	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-control-plane-machine-set-operator/test/e2e/framework"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
 )
 
@@ -48,3 +57,54 @@ func EventuallyClusterOperatorsShouldStabilise(gomegaArgs ...interface{}) {
 		),
 	))), "cluster operators should all be available, not progressing and not degraded")
 }
+
+// ExpectOperatorReadyProbeHealthy checks that the control plane machine set operator deployment has a readiness
+// probe configured, and that, within a few minutes, all of its pods report as ready.
+func ExpectOperatorReadyProbeHealthy(testFramework framework.Framework) bool {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      operatorDeploymentName,
+			Namespace: framework.MachineAPINamespace,
+		},
+	}
+
+	if ok := Expect(komega.Get(deployment)()).To(Succeed(), "operator deployment should exist"); !ok {
+		return false
+	}
+
+	if ok := Expect(deployment.Spec.Template.Spec.Containers).To(HaveEach(
+		HaveField("ReadinessProbe", Not(BeNil())),
+	), "operator containers should have a readiness probe"); !ok {
+		return false
+	}
+
+	By("Checking the operator pods report as ready")
+
+	if ok := Eventually(operatorPods(), 5*time.Minute, 10*time.Second).Should(HaveField("Items", SatisfyAll(
+		Not(BeEmpty()),
+		HaveEach(podReadyCondition(corev1.ConditionTrue)),
+	)), "operator pods should be ready"); !ok {
+		return false
+	}
+
+	return Eventually(komega.Object(deployment), 5*time.Minute, 10*time.Second).Should(SatisfyAll(
+		HaveField("Status.ReadyReplicas", BeNumerically(">", 0)),
+		HaveField("Status.ReadyReplicas", Equal(pointer.Int32Deref(deployment.Spec.Replicas, 1))),
+	), "operator deployment should report all of its replicas as ready")
+}
+
+// operatorPods returns a function that lists the control plane machine set operator pods.
+func operatorPods() func() (runtimeclient.ObjectList, error) {
+	return komega.ObjectList(&corev1.PodList{},
+		runtimeclient.InNamespace(framework.MachineAPINamespace),
+		runtimeclient.MatchingLabels{"k8s-app": operatorDeploymentName},
+	)
+}
+
+// podReadyCondition matches a pod whose Ready condition has the given status.
+func podReadyCondition(status corev1.ConditionStatus) gomegatypes.GomegaMatcher {
+	return HaveField("Status.Conditions", ContainElement(SatisfyAll(
+		HaveField("Type", Equal(corev1.PodReady)),
+		HaveField("Status", Equal(status)),
+	)))
+}
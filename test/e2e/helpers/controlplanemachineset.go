@@ -20,19 +20,71 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
 
+	configv1 "github.com/openshift/api/config/v1"
 	machinev1 "github.com/openshift/api/machine/v1"
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/cluster-control-plane-machine-set-operator/pkg/machineproviders/providers/openshift/machine/v1beta1/failuredomain"
+	"github.com/openshift/cluster-control-plane-machine-set-operator/pkg/machineproviders/providers/openshift/machine/v1beta1/providerconfig"
 	"github.com/openshift/cluster-control-plane-machine-set-operator/test/e2e/framework"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
 )
 
+const (
+	// awsMaxTagsPerResource is the maximum number of tags AWS allows on a single resource.
+	awsMaxTagsPerResource = 50
+
+	// failedReplacementReason is the reason the operator sets on the Degraded condition when a replacement machine
+	// is in an error state.
+	failedReplacementReason = "FailedReplacement"
+
+	// invalidStrategyReason is the reason the operator sets on the Degraded condition when the update strategy
+	// is not supported.
+	invalidStrategyReason = "InvalidStrategy"
+
+	// belowQuorumReason is the reason the operator sets on the Progressing condition when too few control plane machines
+	// are ready to maintain etcd quorum.
+	belowQuorumReason = "BelowQuorum"
+
+	// controlPlaneMachineSetFinalizer is the finalizer added by the operator to active control plane machine sets.
+	controlPlaneMachineSetFinalizer = "controlplanemachineset.machine.openshift.io"
+
+	// observedGenerationTimeout is how long the operator is given to observe the latest generation
+	// of the control plane machine set after it has been changed.
+	observedGenerationTimeout = 5 * time.Minute
+
+	// scaleDownFromReplicas is the number of replicas a control plane machine set must have for the scale down check.
+	scaleDownFromReplicas = 5
+
+	// scaleDownToReplicas is the number of replicas the control plane machine set is scaled down to in the scale down check.
+	scaleDownToReplicas = 3
+
+	// unknownFailureDomainsPlatform is a failure domains platform the operator has no failure domain support for.
+	// The API only accepts known platform types, so a known platform is used so that updates reach the webhook.
+	unknownFailureDomainsPlatform = configv1.BareMetalPlatformType
+
+	// lastRolloutTimeAnnotation is the annotation the operator sets on the control plane machine set to record when
+	// it last observed all of its replicas become up to date.
+	lastRolloutTimeAnnotation = "machine.openshift.io/control-plane-machine-set-last-rollout-time"
+)
+
 var (
 	errUIDNotChanged = errors.New("UID has not changed")
 )
@@ -79,6 +131,94 @@ func ExpectControlPlaneMachineSetToBeInactiveOrNotFound(testFramework framework.
 	}
 }
 
+// ExpectNilReplicasDefaulted checks that replicas is defaulted when a control plane machine set omits it.
+// Replicas is immutable, so the existing control plane machine set is updated, without replicas, as a dry run.
+// The dry run response holds the object as it would be persisted, which should have replicas defaulted to 3, while
+// the persisted control plane machine set is left unchanged.
+// As the default only matches 3 replica control planes, the check is skipped on clusters with 5 replicas.
+func ExpectNilReplicasDefaulted(testFramework framework.Framework) {
+	By("Checking the control plane machine set replicas are defaulted when omitted")
+
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+	Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist")
+	Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set")
+
+	if *cpms.Spec.Replicas != 3 {
+		By("Skipping replicas defaulting check as the control plane does not have 3 replicas")
+		return
+	}
+
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cpms)
+	Expect(err).ToNot(HaveOccurred(), "should be able to convert the control plane machine set to unstructured")
+
+	withoutReplicas := &unstructured.Unstructured{Object: object}
+	withoutReplicas.SetGroupVersionKind(machinev1.GroupVersion.WithKind("ControlPlaneMachineSet"))
+	unstructured.RemoveNestedField(withoutReplicas.Object, "spec", "replicas")
+
+	Expect(testFramework.GetClient().Update(testFramework.GetContext(), withoutReplicas, runtimeclient.DryRunAll)).To(Succeed(),
+		"control plane machine set without replicas should be accepted")
+
+	replicas, found, err := unstructured.NestedInt64(withoutReplicas.Object, "spec", "replicas")
+	Expect(err).ToNot(HaveOccurred(), "should be able to read the defaulted replicas")
+	Expect(found).To(BeTrue(), "replicas should be defaulted")
+	Expect(replicas).To(BeEquivalentTo(3), "replicas should be defaulted to 3")
+
+	// Status updates may change the resource version, but would not change the generation.
+	Expect(komega.Object(cpms)()).To(HaveField("ObjectMeta.Generation", Equal(cpms.Generation)),
+		"dry run should not modify the control plane machine set")
+}
+
+// ExpectSurgeRespected checks that the control plane machine set honours the given surge during a rollout.
+// The control plane machine set API has no field to configure the surge, it is fixed at one machine, to protect the
+// quorum of etcd. To check the surge cannot be configured, the existing control plane machine set is updated, with
+// maxSurge set within its strategy, as a dry run. The API prunes unknown fields, so the dry run response must not
+// include the surge, and the persisted control plane machine set is left unchanged.
+// As only a surge of one can be honoured, any other surge fails the check. The rollout checks, such as
+// CheckTotalReplicasNeverExceedDesiredPlusOne, check that rollouts surge by at most one machine.
+func ExpectSurgeRespected(testFramework framework.Framework, surge int32) bool {
+	By(fmt.Sprintf("Checking the control plane machine set surge cannot be configured to %d", surge))
+
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cpms)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to convert the control plane machine set to unstructured"); !ok {
+		return false
+	}
+
+	withSurge := &unstructured.Unstructured{Object: object}
+	withSurge.SetGroupVersionKind(machinev1.GroupVersion.WithKind("ControlPlaneMachineSet"))
+
+	if ok := Expect(unstructured.SetNestedField(withSurge.Object, int64(surge), "spec", "strategy", "maxSurge")).To(Succeed(),
+		"should be able to set the surge of the control plane machine set"); !ok {
+		return false
+	}
+
+	if ok := Expect(testFramework.GetClient().Update(testFramework.GetContext(), withSurge, runtimeclient.DryRunAll)).To(Succeed(),
+		"control plane machine set with a surge should be accepted, with the surge pruned"); !ok {
+		return false
+	}
+
+	_, found, err := unstructured.NestedFieldNoCopy(withSurge.Object, "spec", "strategy", "maxSurge")
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to read the surge from the dry run response"); !ok {
+		return false
+	}
+
+	if ok := Expect(found).To(BeFalse(), "control plane machine set surge should not be configurable"); !ok {
+		return false
+	}
+
+	// Status updates may change the resource version, but would not change the generation.
+	if ok := Expect(komega.Object(cpms)()).To(HaveField("ObjectMeta.Generation", Equal(cpms.Generation)),
+		"dry run should not modify the control plane machine set"); !ok {
+		return false
+	}
+
+	return Expect(surge).To(BeEquivalentTo(1), "control plane machine set rollouts only surge by one machine")
+}
+
 // EnsureActiveControlPlaneMachineSet ensures that there is an active control plane machine set
 // within the cluster. For fully supported clusters, this means waiting for the control plane machine set
 // to be created and checking that it is active. For manually supported clusters, this means creating the
@@ -172,6 +312,53 @@ func ensureManualActiveControlPlaneMachineSet(testFramework framework.Framework,
 	Fail("manual support for the control plane machine set not yet implemented")
 }
 
+// WaitForControlPlaneMachineSetActive waits for the control plane machine set to be active and to have begun
+// managing the control plane machines.
+// The control plane machine set is managing the machines once it has observed its latest generation
+// and has added itself as the controller owner of each of the control plane machines.
+func WaitForControlPlaneMachineSetActive(testFramework framework.Framework, ctx context.Context) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	By("Waiting for the control plane machine set to be active")
+
+	if ok := Eventually(komega.Object(cpms)).WithContext(ctx).Should(SatisfyAll(
+		HaveField("Spec.State", Equal(machinev1.ControlPlaneMachineSetStateActive)),
+		WithTransform(func(cpms *machinev1.ControlPlaneMachineSet) bool {
+			return cpms.Status.ObservedGeneration == cpms.Generation
+		}, BeTrue()),
+	), "control plane machine set should be active and have observed its latest generation"); !ok {
+		return false
+	}
+
+	By("Waiting for the control plane machine set to own the control plane machines")
+
+	machineList := &machinev1beta1.MachineList{}
+
+	return Eventually(komega.ObjectList(machineList, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels()))).WithContext(ctx).Should(
+		HaveField("Items", HaveEach(
+			HaveField("ObjectMeta.OwnerReferences", ContainElement(SatisfyAll(
+				HaveField("UID", Equal(cpms.UID)),
+				HaveField("Controller", HaveValue(BeTrue())),
+			))),
+		)), "control plane machines should be owned by the control plane machine set")
+}
+
+// ExpectObservedGenerationTracksSpec checks that the observed generation in the control plane machine set status
+// catches up with the generation of the control plane machine set, proving that the operator has reconciled the latest spec.
+// It should be used after each change to the control plane machine set spec, to catch the operator acting on a stale spec.
+func ExpectObservedGenerationTracksSpec(testFramework framework.Framework, ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, observedGenerationTimeout)
+	defer cancel()
+
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	By("Waiting for the control plane machine set to observe its latest generation")
+
+	return Eventually(komega.Object(cpms)).WithContext(ctx).Should(WithTransform(func(cpms *machinev1.ControlPlaneMachineSet) int64 {
+		return cpms.Generation - cpms.Status.ObservedGeneration
+	}, BeZero()), "control plane machine set status should have observed the latest generation")
+}
+
 // WaitForControlPlaneMachineSetDesiredReplicas waits for the control plane machine set to have the desired number of replicas.
 // It first waits for the updated replicas to equal the desired number, and then waits for the final replica
 // count to equal the desired number.
@@ -305,6 +492,59 @@ func WaitForControlPlaneMachineSetRemovedOrRecreated(ctx context.Context, testFr
 	return true
 }
 
+// ExpectCPMSFinalizerRemovedOnDelete deletes the control plane machine set and checks that the operator
+// removes its finalizer so that the deletion completes.
+// The deleted control plane machine set must be removed from the cluster within 5 minutes, rather than being left
+// terminating, though the operator may recreate a new control plane machine set in its place.
+func ExpectCPMSFinalizerRemovedOnDelete(testFramework framework.Framework) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	if cpms.Spec.State == machinev1.ControlPlaneMachineSetStateActive {
+		if ok := Expect(cpms.GetFinalizers()).To(ContainElement(controlPlaneMachineSetFinalizer),
+			"active control plane machine set should have the operator finalizer"); !ok {
+			return false
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(testFramework.GetContext(), 5*time.Minute)
+	defer cancel()
+
+	DeleteControlPlaneMachineSet(testFramework, ctx, cpms)
+
+	By("Waiting for the deleted control plane machine set to be removed")
+
+	oldUID := cpms.GetUID()
+
+	if ok := Eventually(func() error {
+		current := testFramework.NewEmptyControlPlaneMachineSet()
+		if err := komega.Get(current)(); err != nil {
+			return err
+		}
+
+		if current.GetUID() == oldUID {
+			return fmt.Errorf("%w: control plane machine set has finalizers %v", errUIDNotChanged, current.GetFinalizers())
+		}
+
+		return nil
+	}).WithContext(ctx).Should(SatisfyAny(
+		BeNil(),
+		MatchError(ContainSubstring("not found")),
+	), "deleted control plane machine set should be removed and not left terminating"); !ok {
+		return false
+	}
+
+	recreated := testFramework.NewEmptyControlPlaneMachineSet()
+	if err := komega.Get(recreated)(); err != nil {
+		return Expect(err).To(MatchError(ContainSubstring("not found")), "getting control plane machine set should not error")
+	}
+
+	return Expect(recreated.GetDeletionTimestamp()).To(BeNil(), "recreated control plane machine set should not be terminating")
+}
+
 // IncreaseControlPlaneMachineSetInstanceSize increases the instance size of the control plane machine set.
 // This should trigger the control plane machine set to update the machines based on the
 // update strategy.
@@ -318,6 +558,7 @@ func IncreaseControlPlaneMachineSetInstanceSize(testFramework framework.Framewor
 
 	updatedProviderSpec := originalProviderSpec.DeepCopy()
 	Expect(testFramework.IncreaseProviderSpecInstanceSize(updatedProviderSpec.Value)).To(Succeed(), "provider spec should be updated with bigger instance size")
+	expectInstanceTypeChanged(originalProviderSpec, *updatedProviderSpec)
 
 	By("Increasing the control plane machine set instance size")
 
@@ -341,3 +582,1350 @@ func GetControlPlaneMachineSetUID(testFramework framework.Framework) types.UID {
 
 	return cpms.ObjectMeta.UID
 }
+
+// IsControlPlaneMachineSetGenerated checks whether the control plane machine set was generated by the operator.
+func IsControlPlaneMachineSetGenerated(testFramework framework.Framework) bool {
+	Expect(testFramework).ToNot(BeNil(), "test framework should not be nil")
+
+	generated, err := testFramework.IsControlPlaneMachineSetGenerated()
+	Expect(err).ToNot(HaveOccurred(), "should be able to determine the control plane machine set origin")
+
+	return generated
+}
+
+// ExpectUnavailableReplicasTracksSurge checks the status accounting of the control plane machine set
+// while a surge machine is in flight during a rolling update.
+// UnavailableReplicas counts the indexes without a Ready machine, so while the outdated machine in
+// the index remains Ready, the unready surge machine is reflected in the difference between
+// Replicas and ReadyReplicas rather than in UnavailableReplicas.
+// The check waits for the surge to begin and then ensures, until the rollout completes, that at most
+// the single surge machine is unready and that no index is reported as unavailable.
+func ExpectUnavailableReplicasTracksSurge(testFramework framework.Framework, ctx context.Context) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	if ok := Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set"); !ok {
+		return false
+	}
+
+	desiredReplicas := *cpms.Spec.Replicas
+
+	By("Waiting for the control plane machine set to report a surge machine")
+
+	if ok := Eventually(komega.Object(cpms)).WithContext(ctx).Should(
+		HaveField("Status.Replicas", BeNumerically(">", desiredReplicas)),
+		"control plane machine set should report a surge machine",
+	); !ok {
+		return false
+	}
+
+	By("Checking the unavailable replicas remain accurate until the surge has completed")
+
+	return framework.RunCheckUntil(ctx,
+		func(_ context.Context, g framework.GomegaAssertions) bool { // Check function
+			if ok := g.Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+				return false
+			}
+
+			return g.Expect(cpms.Status).To(SatisfyAll(
+				HaveField("UnavailableReplicas", BeZero()),
+				HaveField("ReadyReplicas", BeNumerically(">=", cpms.Status.Replicas-1)),
+			), "only the surge machine should be unready and no index should be unavailable")
+		},
+		func(_ context.Context, g framework.GomegaAssertions) bool { // Condition function
+			if ok := g.Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+				return false
+			}
+
+			return g.Expect(cpms.Status).To(SatisfyAll(
+				HaveField("Replicas", Equal(desiredReplicas)),
+				HaveField("ReadyReplicas", Equal(desiredReplicas)),
+				HaveField("UpdatedReplicas", Equal(desiredReplicas)),
+				HaveField("UnavailableReplicas", BeZero()),
+			), "surge should complete with all replicas ready and updated")
+		},
+	)
+}
+
+// ExpectUpdatedReplicasDecrementsOnDrift checks that, with the OnDelete update strategy, the updated replicas
+// of the control plane machine set reflect the number of control plane machines still matching the template,
+// once some of the machines have drifted from the template, and that none of the machines are being deleted.
+func ExpectUpdatedReplicasDecrementsOnDrift(testFramework framework.Framework) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	if ok := Expect(cpms.Spec.Strategy.Type).To(Equal(machinev1.OnDelete), "control plane machine set should use the OnDelete update strategy"); !ok {
+		return false
+	}
+
+	machineList := &machinev1beta1.MachineList{}
+	machineSelector := runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())
+
+	if ok := Expect(komega.List(machineList, machineSelector)()).To(Succeed(), "should be able to list control plane machines"); !ok {
+		return false
+	}
+
+	templateProviderSpec := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value
+	upToDateMachines := int32(0)
+
+	for _, machine := range machineList.Items {
+		providerSpec, err := testFramework.ConvertToControlPlaneMachineSetProviderSpec(machine.Spec.ProviderSpec)
+		if ok := Expect(err).ToNot(HaveOccurred(), "should be able to convert the provider spec of machine %s", machine.Name); !ok {
+			return false
+		}
+
+		if matches, err := MatchJSON(templateProviderSpec.Raw).Match(providerSpec.Raw); err == nil && matches {
+			upToDateMachines++
+		}
+	}
+
+	if ok := Expect(upToDateMachines).To(BeNumerically("<", len(machineList.Items)), "expected at least one control plane machine to have drifted from the template"); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the control plane machine set reports %d updated replicas", upToDateMachines))
+
+	if ok := Eventually(komega.Object(cpms)).Should(HaveField("Status.UpdatedReplicas", Equal(upToDateMachines)),
+		"control plane machine set updated replicas should reflect the machines matching the template"); !ok {
+		return false
+	}
+
+	return Expect(komega.ObjectList(machineList, machineSelector)()).To(HaveField("Items",
+		HaveEach(HaveField("ObjectMeta.DeletionTimestamp", BeNil())),
+	), "expected none of the control plane machines to be deleted under the OnDelete update strategy")
+}
+
+// ExpectProviderErrorSurfaced checks that the control plane machine set surfaces a provider error,
+// for example a failure to create the replacement machine in the cloud, as a Degraded condition
+// whose message contains the expected substring.
+// The Degraded message carries the error message of each failed replacement machine, so the substring
+// should identify the provider error rather than the generic failed replacement message.
+// If the condition is not observed, the full set of control plane machine set conditions is printed
+// to aid debugging of the stuck rollout.
+func ExpectProviderErrorSurfaced(testFramework framework.Framework, substring string) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	By(fmt.Sprintf("Waiting for the control plane machine set to report a Degraded condition containing %q", substring))
+
+	return Eventually(komega.Object(cpms), 15*time.Minute, 10*time.Second).Should(
+		HaveField("Status.Conditions", ContainElement(SatisfyAll(
+			HaveField("Type", Equal("Degraded")),
+			HaveField("Status", Equal(metav1.ConditionTrue)),
+			HaveField("Message", ContainSubstring(substring)),
+		))),
+		func() string {
+			return fmt.Sprintf("control plane machine set should report a Degraded condition containing %q, observed conditions:\n%s",
+				substring, format.Object(cpms.Status.Conditions, 1))
+		},
+	)
+}
+
+// ExpectSurgeQuotaWarning checks that, when the cloud quota cannot accommodate a surge machine, the problem is
+// explained to the user.
+// The control plane machine set must report a Degraded condition with the FailedReplacement reason, and the
+// replacement machine must explain the quota problem, either in its error message or in an event, by containing
+// the given substring.
+func ExpectSurgeQuotaWarning(testFramework framework.Framework, substring string) bool {
+	k8sClient := testFramework.GetClient()
+	ctx := testFramework.GetContext()
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	By(fmt.Sprintf("Waiting for the control plane machine set to report a Degraded condition with reason %s", failedReplacementReason))
+
+	if ok := Eventually(komega.Object(cpms), 15*time.Minute, 10*time.Second).Should(
+		HaveField("Status.Conditions", ContainElement(SatisfyAll(
+			HaveField("Type", Equal("Degraded")),
+			HaveField("Status", Equal(metav1.ConditionTrue)),
+			HaveField("Reason", Equal(failedReplacementReason)),
+		))), "control plane machine set should report the failed replacement",
+	); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Waiting for a replacement machine to explain the quota problem with %q", substring))
+
+	return Eventually(func() ([]string, error) {
+		machineList := &machinev1beta1.MachineList{}
+		if err := k8sClient.List(ctx, machineList, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())); err != nil {
+			return nil, fmt.Errorf("could not list control plane machines: %w", err)
+		}
+
+		eventList := &corev1.EventList{}
+		if err := k8sClient.List(ctx, eventList, runtimeclient.InNamespace(framework.MachineAPINamespace)); err != nil {
+			return nil, fmt.Errorf("could not list events: %w", err)
+		}
+
+		messages := []string{}
+
+		for _, machine := range machineList.Items {
+			if machine.Status.ErrorMessage != nil {
+				messages = append(messages, *machine.Status.ErrorMessage)
+			}
+
+			for _, event := range eventList.Items {
+				if event.InvolvedObject.Kind == "Machine" && event.InvolvedObject.UID == machine.UID {
+					messages = append(messages, event.Message)
+				}
+			}
+		}
+
+		return messages, nil
+	}, 15*time.Minute, 10*time.Second).Should(ContainElement(ContainSubstring(substring)),
+		"a replacement machine should explain the quota problem")
+}
+
+// ExpectDuplicateFailureDomainsHandled checks that the control plane machine set tolerates failure domains that are
+// listed more than once.
+// Duplicate failure domains are accepted by the API and are deduplicated by the operator when mapping failure domains
+// to indexes, so a duplicated failure domain is treated as a single failure domain and should not cause a rollout.
+// The first failure domain is duplicated within the control plane machine set, and the original failure domains are
+// restored before returning.
+// When no failure domains are configured, the check is skipped.
+func ExpectDuplicateFailureDomainsHandled(testFramework framework.Framework) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	if ok := Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set"); !ok {
+		return false
+	}
+
+	desiredReplicas := *cpms.Spec.Replicas
+	originalFailureDomains := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains.DeepCopy()
+	updatedFailureDomains := originalFailureDomains.DeepCopy()
+
+	if !duplicateFirstFailureDomain(updatedFailureDomains) {
+		By("Skipping duplicate failure domains check as no failure domains are configured")
+		return true
+	}
+
+	By("Duplicating the first failure domain of the control plane machine set")
+
+	if ok := Eventually(komega.Update(cpms, func() {
+		cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains = *updatedFailureDomains
+	})).Should(Succeed(), "control plane machine set should accept duplicate failure domains"); !ok {
+		return false
+	}
+
+	defer func() {
+		By("Restoring the original failure domains of the control plane machine set")
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains = *originalFailureDomains
+		})).Should(Succeed(), "control plane machine set failure domains should be able to be restored")
+	}()
+
+	if ok := Expect(testFramework.TriggerCPMSReconcile()).To(Succeed(), "should be able to trigger a control plane machine set reconcile"); !ok {
+		return false
+	}
+
+	By("Checking the duplicated failure domain does not cause a rollout")
+
+	if ok := Consistently(komega.Object(cpms)).Should(SatisfyAll(
+		HaveField("Status.Replicas", Equal(desiredReplicas)),
+		HaveField("Status.UpdatedReplicas", Equal(desiredReplicas)),
+	), "control plane machine set should treat the duplicated failure domain as a single failure domain"); !ok {
+		return false
+	}
+
+	return Expect(komega.ObjectList(&machinev1beta1.MachineList{}, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels()))()).
+		To(HaveField("Items", HaveEach(HaveField("ObjectMeta.DeletionTimestamp", BeNil()))),
+			"expected none of the control plane machines to be deleted")
+}
+
+// ExpectSurplusFailureDomainsHandled checks that the control plane machine set handles more failure domains than
+// it has replicas, by keeping each control plane machine within its existing failure domain.
+// As many surplus failure domains as there are replicas are added to the control plane machine set, and the check
+// expects the operator to neither roll out nor degrade, and the failure domain of each machine to be stable across
+// reconciles. The original failure domains are restored before returning.
+// When there are fewer failure domains than replicas, or the existing failure domains would not be selected over
+// the surplus failure domains, the check is skipped.
+func ExpectSurplusFailureDomainsHandled(testFramework framework.Framework) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	if ok := Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set"); !ok {
+		return false
+	}
+
+	desiredReplicas := *cpms.Spec.Replicas
+	originalFailureDomains := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains.DeepCopy()
+
+	original, err := failuredomain.NewFailureDomains(*originalFailureDomains)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the failure domains of the control plane machine set"); !ok {
+		return false
+	}
+
+	if len(original) < int(desiredReplicas) {
+		By(fmt.Sprintf("Skipping surplus failure domains check as there are fewer failure domains (%d) than replicas (%d)", len(original), desiredReplicas))
+		return true
+	}
+
+	updatedFailureDomains := originalFailureDomains.DeepCopy()
+
+	if !addSurplusFailureDomains(updatedFailureDomains, int(desiredReplicas)) {
+		By("Skipping surplus failure domains check as no failure domains are configured")
+		return true
+	}
+
+	updated, err := failuredomain.NewFailureDomains(*updatedFailureDomains)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the failure domains with the surplus failure domains"); !ok {
+		return false
+	}
+
+	if !surplusFailureDomainsSortLast(original, updated) {
+		By("Skipping surplus failure domains check as the existing failure domains do not sort before the surplus failure domains")
+		return true
+	}
+
+	originalMapping, err := controlPlaneMachineFailureDomains(original)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the failure domains of the control plane machines"); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Adding surplus failure domains to the control plane machine set, for %d failure domains in total", len(updated)))
+
+	if ok := Eventually(komega.Update(cpms, func() {
+		cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains = *updatedFailureDomains
+	})).Should(Succeed(), "control plane machine set should accept more failure domains than replicas"); !ok {
+		return false
+	}
+
+	defer func() {
+		By("Restoring the original failure domains of the control plane machine set")
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains = *originalFailureDomains
+		})).Should(Succeed(), "control plane machine set failure domains should be able to be restored")
+	}()
+
+	if ok := ExpectObservedGenerationTracksSpec(testFramework, testFramework.GetContext()); !ok {
+		return false
+	}
+
+	// Reconcile more than once, to check the selected failure domains do not change between reconciles.
+	for i := 0; i < 2; i++ {
+		if ok := Expect(testFramework.TriggerCPMSReconcile()).To(Succeed(), "should be able to trigger a control plane machine set reconcile"); !ok {
+			return false
+		}
+
+		By("Checking the surplus failure domains do not cause a rollout or degrade the control plane machine set")
+
+		if ok := Consistently(komega.Object(cpms)).Should(SatisfyAll(
+			HaveField("Status.Replicas", Equal(desiredReplicas)),
+			HaveField("Status.UpdatedReplicas", Equal(desiredReplicas)),
+			HaveField("Status.Conditions", Not(ContainElement(SatisfyAll(
+				HaveField("Type", Equal("Degraded")),
+				HaveField("Status", Equal(metav1.ConditionTrue)),
+			)))),
+		), "control plane machine set should select a subset of the failure domains without a rollout"); !ok {
+			return false
+		}
+
+		By("Checking the control plane machines remain within their original failure domains")
+
+		mapping, err := controlPlaneMachineFailureDomains(original)
+		if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the failure domains of the control plane machines"); !ok {
+			return false
+		}
+
+		if ok := Expect(mapping).To(Equal(originalMapping), "control plane machine failure domains should be stable across reconciles"); !ok {
+			return false
+		}
+	}
+
+	return Expect(komega.ObjectList(&machinev1beta1.MachineList{}, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels()))()).
+		To(HaveField("Items", HaveEach(HaveField("ObjectMeta.DeletionTimestamp", BeNil()))),
+			"expected none of the control plane machines to be deleted")
+}
+
+// keepFirstFailureDomains reduces the failure domains of the configured platform to the first count failure domains,
+// in the alphabetical order the operator uses to create its base index mapping.
+// It returns the failure domains that were kept.
+func keepFirstFailureDomains(failureDomains *machinev1.FailureDomains, count int) ([]failuredomain.FailureDomain, error) {
+	switch {
+	case failureDomains.AWS != nil:
+		domains := *failureDomains.AWS
+		sort.Slice(domains, func(i, j int) bool {
+			return failuredomain.NewAWSFailureDomain(domains[i]).String() < failuredomain.NewAWSFailureDomain(domains[j]).String()
+		})
+
+		if len(domains) > count {
+			*failureDomains.AWS = domains[:count]
+		}
+	case failureDomains.Azure != nil:
+		domains := *failureDomains.Azure
+		sort.Slice(domains, func(i, j int) bool {
+			return failuredomain.NewAzureFailureDomain(domains[i]).String() < failuredomain.NewAzureFailureDomain(domains[j]).String()
+		})
+
+		if len(domains) > count {
+			*failureDomains.Azure = domains[:count]
+		}
+	case failureDomains.GCP != nil:
+		domains := *failureDomains.GCP
+		sort.Slice(domains, func(i, j int) bool {
+			return failuredomain.NewGCPFailureDomain(domains[i]).String() < failuredomain.NewGCPFailureDomain(domains[j]).String()
+		})
+
+		if len(domains) > count {
+			*failureDomains.GCP = domains[:count]
+		}
+	}
+
+	kept, err := failuredomain.NewFailureDomains(*failureDomains)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the kept failure domains: %w", err)
+	}
+
+	return kept, nil
+}
+
+// denormalizeFailureDomainZones rewrites the zone of each failure domain of the configured platform in upper case,
+// surrounded by whitespace, as though copied into the control plane machine set by hand.
+// It returns false when none of the failure domains set a zone.
+func denormalizeFailureDomainZones(failureDomains *machinev1.FailureDomains) bool {
+	denormalize := func(zone string) string {
+		return fmt.Sprintf(" %s ", strings.ToUpper(zone))
+	}
+
+	changed := false
+
+	switch {
+	case failureDomains.AWS != nil:
+		for i := range *failureDomains.AWS {
+			if zone := (*failureDomains.AWS)[i].Placement.AvailabilityZone; zone != "" {
+				(*failureDomains.AWS)[i].Placement.AvailabilityZone = denormalize(zone)
+				changed = true
+			}
+		}
+	case failureDomains.Azure != nil:
+		for i := range *failureDomains.Azure {
+			if zone := (*failureDomains.Azure)[i].Zone; zone != "" {
+				(*failureDomains.Azure)[i].Zone = denormalize(zone)
+				changed = true
+			}
+		}
+	case failureDomains.GCP != nil:
+		for i := range *failureDomains.GCP {
+			if zone := (*failureDomains.GCP)[i].Zone; zone != "" {
+				(*failureDomains.GCP)[i].Zone = denormalize(zone)
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
+
+// controlPlaneIndexesOutsideFailureDomains returns the indexes of the control plane machines whose failure domain
+// is none of the given failure domains.
+func controlPlaneIndexesOutsideFailureDomains(failureDomains []failuredomain.FailureDomain) ([]int, error) {
+	machineList := &machinev1beta1.MachineList{}
+	if err := komega.List(machineList, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels()))(); err != nil {
+		return nil, fmt.Errorf("failed to list control plane machines: %w", err)
+	}
+
+	indexes := []int{}
+
+	for _, machine := range machineList.Items {
+		machineFailureDomain, err := providerconfig.ExtractFailureDomainFromMachine(machine)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get failure domain of machine %s: %w", machine.Name, err)
+		}
+
+		if containsFailureDomain(failureDomains, machineFailureDomain) {
+			continue
+		}
+
+		idx, err := machineIndex(machine)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get index of machine %s: %w", machine.Name, err)
+		}
+
+		indexes = append(indexes, idx)
+	}
+
+	sort.Ints(indexes)
+
+	return indexes, nil
+}
+
+// surplusFailureDomainsSortLast checks that every failure domain within updated, that is not within original,
+// sorts after all of the original failure domains.
+func surplusFailureDomainsSortLast(original, updated []failuredomain.FailureDomain) bool {
+	lastOriginal := ""
+
+	for _, failureDomain := range original {
+		if failureDomain.String() > lastOriginal {
+			lastOriginal = failureDomain.String()
+		}
+	}
+
+	for _, failureDomain := range updated {
+		if containsFailureDomain(original, failureDomain) {
+			continue
+		}
+
+		if failureDomain.String() <= lastOriginal {
+			return false
+		}
+	}
+
+	return true
+}
+
+// containsFailureDomain checks whether the failure domain is equal to any of the failure domains in the list.
+func containsFailureDomain(failureDomains []failuredomain.FailureDomain, failureDomain failuredomain.FailureDomain) bool {
+	for _, fd := range failureDomains {
+		if fd.Equal(failureDomain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// duplicateFirstFailureDomain appends a copy of the first failure domain to the failure domains
+// of the configured platform.
+// It returns false when there are no failure domains to duplicate.
+func duplicateFirstFailureDomain(failureDomains *machinev1.FailureDomains) bool {
+	switch {
+	case failureDomains.AWS != nil && len(*failureDomains.AWS) > 0:
+		*failureDomains.AWS = append(*failureDomains.AWS, (*failureDomains.AWS)[0])
+	case failureDomains.Azure != nil && len(*failureDomains.Azure) > 0:
+		*failureDomains.Azure = append(*failureDomains.Azure, (*failureDomains.Azure)[0])
+	case failureDomains.GCP != nil && len(*failureDomains.GCP) > 0:
+		*failureDomains.GCP = append(*failureDomains.GCP, (*failureDomains.GCP)[0])
+	default:
+		return false
+	}
+
+	return true
+}
+
+// ControlPlaneDomainDistribution returns the number of control plane machines in each failure domain, keyed by the
+// string representation of the failure domain.
+// Failure domains configured within the control plane machine set, that have no machines, are included with a count
+// of zero. When no failure domains are configured, an empty distribution is returned.
+func ControlPlaneDomainDistribution(testFramework framework.Framework) (map[string]int, error) {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+	if err := komega.Get(cpms)(); err != nil {
+		return nil, fmt.Errorf("failed to get control plane machine set: %w", err)
+	}
+
+	distribution := map[string]int{}
+
+	if cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains.Platform == "" {
+		return distribution, nil
+	}
+
+	failureDomains, err := failuredomain.NewFailureDomains(cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get failure domains from control plane machine set: %w", err)
+	}
+
+	for _, failureDomain := range failureDomains {
+		distribution[failureDomain.String()] = 0
+	}
+
+	machineFailureDomains, err := controlPlaneMachineFailureDomains(failureDomains)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range machineFailureDomains {
+		distribution[key]++
+	}
+
+	return distribution, nil
+}
+
+// controlPlaneMachineFailureDomains returns the string representation of the failure domain of each control plane
+// machine, keyed by the machine name.
+// Each machine is reported against the given failure domain it matches, as failure domains extracted from
+// machines may reference the same resources differently.
+func controlPlaneMachineFailureDomains(failureDomains []failuredomain.FailureDomain) (map[string]string, error) {
+	machineList := &machinev1beta1.MachineList{}
+	if err := komega.List(machineList, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels()))(); err != nil {
+		return nil, fmt.Errorf("failed to list control plane machines: %w", err)
+	}
+
+	out := map[string]string{}
+
+	for _, machine := range machineList.Items {
+		machineFailureDomain, err := providerconfig.ExtractFailureDomainFromMachine(machine)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get failure domain of machine %s: %w", machine.Name, err)
+		}
+
+		key := machineFailureDomain.String()
+
+		for _, failureDomain := range failureDomains {
+			if failureDomain.Equal(machineFailureDomain) {
+				key = failureDomain.String()
+				break
+			}
+		}
+
+		out[machine.Name] = key
+	}
+
+	return out, nil
+}
+
+// addSurplusFailureDomains appends the given number of failure domains to the failure domains of the configured
+// platform. The surplus failure domains do not exist within the cloud, so no machine can be created in them.
+// The names of the surplus failure domains sort after those of real failure domains, so that the operator, which
+// sorts failure domains alphabetically to create its base index mapping, prefers the existing failure domains.
+// It returns false when there are no failure domains to add to.
+func addSurplusFailureDomains(failureDomains *machinev1.FailureDomains, count int) bool {
+	for i := 1; i <= count; i++ {
+		name := fmt.Sprintf("zz-e2e-surplus-%d", i)
+
+		switch {
+		case failureDomains.AWS != nil && len(*failureDomains.AWS) > 0:
+			*failureDomains.AWS = append(*failureDomains.AWS, machinev1.AWSFailureDomain{
+				Placement: machinev1.AWSFailureDomainPlacement{AvailabilityZone: name},
+			})
+		case failureDomains.Azure != nil && len(*failureDomains.Azure) > 0:
+			*failureDomains.Azure = append(*failureDomains.Azure, machinev1.AzureFailureDomain{Zone: name})
+		case failureDomains.GCP != nil && len(*failureDomains.GCP) > 0:
+			*failureDomains.GCP = append(*failureDomains.GCP, machinev1.GCPFailureDomain{Zone: name})
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// ExpectBalancedDomainDistribution checks that the control plane machines are spread as evenly across the failure
+// domains as the number of failure domains allows, that is, no failure domain has more than one machine more than
+// any other failure domain.
+// Repeated rollouts should not cause machines to accumulate within a single failure domain.
+// When no failure domains are configured, the check is skipped.
+func ExpectBalancedDomainDistribution(testFramework framework.Framework) bool {
+	distribution, err := ControlPlaneDomainDistribution(testFramework)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the failure domain distribution"); !ok {
+		return false
+	}
+
+	if len(distribution) == 0 {
+		By("Skipping failure domain distribution check as no failure domains are configured")
+		return true
+	}
+
+	By(fmt.Sprintf("Checking the control plane machines are balanced across the failure domains: %v", distribution))
+
+	minCount, maxCount := -1, 0
+
+	for _, count := range distribution {
+		if minCount == -1 || count < minCount {
+			minCount = count
+		}
+
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+
+	return Expect(maxCount-minCount).To(BeNumerically("<=", 1), "control plane machines should be balanced across the failure domains: %v", distribution)
+}
+
+// ExpectLastRolloutTimeUpdated checks that the control plane machine set records a last rollout time that is not
+// before the given time. This should be used after a rollout to check that its completion was recorded.
+// The annotation has a precision of one second, so the given time is truncated before comparison.
+func ExpectLastRolloutTimeUpdated(testFramework framework.Framework, since time.Time) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+	since = since.Truncate(time.Second)
+
+	By("Checking the control plane machine set recorded the last rollout time")
+
+	return Eventually(func() (time.Time, error) {
+		if err := komega.Get(cpms)(); err != nil {
+			return time.Time{}, fmt.Errorf("could not get control plane machine set: %w", err)
+		}
+
+		lastRolloutTime, err := time.Parse(time.RFC3339, cpms.GetAnnotations()[lastRolloutTimeAnnotation])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("could not parse last rollout time: %w", err)
+		}
+
+		return lastRolloutTime, nil
+	}, observedGenerationTimeout, 10*time.Second).Should(
+		BeTemporally(">=", since),
+		"last rollout time should be no earlier than %s", since.Format(time.RFC3339),
+	)
+}
+
+// ExpectFaultDomainSpread checks that the control plane machines, within their availability set, are spread across
+// the fault domains. Machines sharing a fault domain share power and network hardware, so poor spread risks a single
+// hardware failure taking out several control plane machines at once.
+// This check only applies to Azure clusters using availability sets, and is skipped on other platforms, on zone based
+// clusters, which do not use availability sets, and where the machines do not report their fault domain.
+func ExpectFaultDomainSpread(testFramework framework.Framework) bool {
+	if testFramework.GetPlatformType() != configv1.AzurePlatformType {
+		By(fmt.Sprintf("Skipping fault domain spread check as fault domains are not supported on platform %s", testFramework.GetPlatformType()))
+		return true
+	}
+
+	machineList := &machinev1beta1.MachineList{}
+	machineSelector := runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())
+
+	if ok := Expect(komega.List(machineList, machineSelector)()).To(Succeed(), "should be able to list control plane machines"); !ok {
+		return false
+	}
+
+	distribution := map[string]map[int]int{}
+
+	for _, machine := range machineList.Items {
+		availabilitySet, err := framework.GetAzureProviderSpecAvailabilitySet(machine.Spec.ProviderSpec.Value)
+		if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the availability set of machine %s", machine.Name); !ok {
+			return false
+		}
+
+		if availabilitySet == "" {
+			By(fmt.Sprintf("Skipping fault domain spread check as machine %s is not in an availability set", machine.Name))
+			return true
+		}
+
+		faultDomain, err := framework.GetAzureProviderSpecFaultDomain(machine.Spec.ProviderSpec.Value)
+		if errors.Is(err, framework.ErrNoAzureFaultDomain) {
+			By(fmt.Sprintf("Skipping fault domain spread check as machine %s does not report its fault domain", machine.Name))
+			return true
+		}
+
+		if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the fault domain of machine %s", machine.Name); !ok {
+			return false
+		}
+
+		if distribution[availabilitySet] == nil {
+			distribution[availabilitySet] = map[int]int{}
+		}
+
+		distribution[availabilitySet][faultDomain]++
+	}
+
+	for availabilitySet, faultDomains := range distribution {
+		By(fmt.Sprintf("Checking the control plane machines in availability set %s are spread across the fault domains: %v", availabilitySet, faultDomains))
+
+		machines, minCount, maxCount := 0, -1, 0
+
+		for _, count := range faultDomains {
+			machines += count
+
+			if minCount == -1 || count < minCount {
+				minCount = count
+			}
+
+			if count > maxCount {
+				maxCount = count
+			}
+		}
+
+		if machines > 1 {
+			if ok := Expect(len(faultDomains)).To(BeNumerically(">", 1), "control plane machines in availability set %s should not share a single fault domain", availabilitySet); !ok {
+				return false
+			}
+		}
+
+		if ok := Expect(maxCount-minCount).To(BeNumerically("<=", 1), "control plane machines in availability set %s should be balanced across the fault domains: %v", availabilitySet, faultDomains); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ExpectRolloutMetricExposed checks that the operator exposes the named counter, labelled with the platform and the
+// update strategy of the control plane machine set, and that it has recorded at least one replacement.
+// This should be called once a rollout has completed. Counters reset when the operator restarts, so, rather than
+// comparing against a value recorded before the rollout, this checks that the rollout was recorded.
+func ExpectRolloutMetricExposed(testFramework framework.Framework, metricName string) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	labels := map[string]string{
+		"platform": string(testFramework.GetPlatformType()),
+		"strategy": string(cpms.Spec.Strategy.Type),
+	}
+
+	By(fmt.Sprintf("Checking the operator exposes the %s metric", metricName))
+
+	return Eventually(func() (float64, error) {
+		return testFramework.OperatorMetricValue(metricName, labels)
+	}, 2*time.Minute, 10*time.Second).Should(BeNumerically(">=", 1), "operator should record the replacement in the %s metric", metricName)
+}
+
+// ExpectExcessiveTagsRejected checks that the control plane machine set surfaces the provider error when the template
+// sets more tags than AWS allows on an instance, without removing the healthy machines.
+// The template is updated with more tags than AWS allows. Under the RollingUpdate strategy, the operator creates a
+// replacement for index 0 which AWS rejects. The operator must surface the provider error recorded on the replacement
+// machine in a Degraded condition, while the healthy, outdated, machines must not be removed.
+// The original template is restored, and the failed replacement removed, once the test completes.
+// On platforms other than AWS, this check is skipped.
+func ExpectExcessiveTagsRejected(testFramework framework.Framework) bool {
+	if testFramework.GetPlatformType() != configv1.AWSPlatformType {
+		By(fmt.Sprintf("Skipping excessive tags check as tag limits are not tested on platform %s", testFramework.GetPlatformType()))
+		return true
+	}
+
+	index := 0
+
+	ctx, cancel := context.WithTimeout(testFramework.GetContext(), 30*time.Minute)
+	defer cancel()
+
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	if ok := Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set"); !ok {
+		return false
+	}
+
+	desiredReplicas := int(*cpms.Spec.Replicas)
+	originalProviderSpec := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec
+	originalStrategy := EnsureControlPlaneMachineSetUpdateStrategy(testFramework, machinev1.RollingUpdate)
+
+	tags := []machinev1beta1.TagSpecification{}
+
+	for i := 0; i <= awsMaxTagsPerResource; i++ {
+		tags = append(tags, machinev1beta1.TagSpecification{Name: fmt.Sprintf("e2e-tag-%d", i), Value: "e2e"})
+	}
+
+	updatedProviderSpec := originalProviderSpec.DeepCopy()
+	if ok := Expect(framework.AddAWSProviderSpecTags(updatedProviderSpec.Value, tags...)).To(Succeed(), "provider spec should be updated with the excessive tags"); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Updating the control plane machine set with more than %d tags", awsMaxTagsPerResource))
+
+	if ok := Eventually(komega.Update(cpms, func() {
+		cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = *updatedProviderSpec
+	})).Should(Succeed(), "control plane machine set should be able to be updated"); !ok {
+		return false
+	}
+
+	DeferCleanup(func() {
+		cleanupFailedReplacement(testFramework, index, originalProviderSpec, originalStrategy)
+	})
+
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	_, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	By("Checking the replacement machine records the provider error")
+
+	if ok := Eventually(komega.Object(newMachine)).WithContext(ctx).Should(
+		HaveField("Status.ErrorMessage", HaveValue(Not(BeEmpty()))),
+		"replacement machine should record the provider error",
+	); !ok {
+		return false
+	}
+
+	if ok := ExpectProviderErrorSurfaced(testFramework, pointer.StringDeref(newMachine.Status.ErrorMessage, "")); !ok {
+		return false
+	}
+
+	By("Checking the healthy machines are not removed")
+
+	return Consistently(komega.ObjectList(&machinev1beta1.MachineList{}, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())), 2*time.Minute, 10*time.Second).Should(
+		HaveField("Items", SatisfyAll(
+			HaveLen(desiredReplicas+1),
+			HaveEach(HaveField("ObjectMeta.DeletionTimestamp", BeNil())),
+		)), "healthy control plane machines should not be removed while the replacement has failed",
+	)
+}
+
+// ExpectInvalidSubnetRejected checks that the control plane machine set surfaces the provider error when the template
+// references a subnet that does not exist, without removing the healthy machines.
+// The template is updated with a nonexistent subnet. Under the RollingUpdate strategy, the operator creates a
+// replacement for index 0 which the cloud provider rejects. The provider error, recorded on the replacement machine,
+// must reference the subnet, and the operator must surface it in a Degraded condition. The healthy, outdated,
+// machines must not be removed.
+// The original template is restored, and the failed replacement removed, once the test completes.
+// On AWS, a subnet set by the failure domains replaces the subnet of the template, so this check is skipped when the
+// failure domains set a subnet.
+func ExpectInvalidSubnetRejected(testFramework framework.Framework) bool {
+	index := 0
+
+	ctx, cancel := context.WithTimeout(testFramework.GetContext(), 30*time.Minute)
+	defer cancel()
+
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	if ok := Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set"); !ok {
+		return false
+	}
+
+	invalidSubnet, ok := nonexistentSubnet(testFramework.GetPlatformType())
+	if !ok {
+		By(fmt.Sprintf("Skipping invalid subnet check as subnets are not tested on platform %s", testFramework.GetPlatformType()))
+		return true
+	}
+
+	if awsFailureDomains := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains.AWS; awsFailureDomains != nil {
+		for _, failureDomain := range *awsFailureDomains {
+			if framework.AWSFailureDomainSubnet(failureDomain) != "" {
+				By("Skipping invalid subnet check as the failure domains set the subnet")
+				return true
+			}
+		}
+	}
+
+	desiredReplicas := int(*cpms.Spec.Replicas)
+	originalProviderSpec := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec
+	originalStrategy := EnsureControlPlaneMachineSetUpdateStrategy(testFramework, machinev1.RollingUpdate)
+
+	updatedProviderSpec := originalProviderSpec.DeepCopy()
+	if ok := Expect(framework.SetProviderSpecSubnet(updatedProviderSpec.Value, invalidSubnet)).To(Succeed(), "provider spec should be updated with the invalid subnet"); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Updating the control plane machine set with the nonexistent subnet %s", invalidSubnet))
+
+	if ok := Eventually(komega.Update(cpms, func() {
+		cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec = *updatedProviderSpec
+	})).Should(Succeed(), "control plane machine set should be able to be updated"); !ok {
+		return false
+	}
+
+	DeferCleanup(func() {
+		cleanupFailedReplacement(testFramework, index, originalProviderSpec, originalStrategy)
+	})
+
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	_, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	By("Checking the replacement machine records the provider error referencing the subnet")
+
+	if ok := Eventually(komega.Object(newMachine)).WithContext(ctx).Should(
+		HaveField("Status.ErrorMessage", HaveValue(ContainSubstring(invalidSubnet))),
+		"replacement machine should record the provider error referencing the subnet",
+	); !ok {
+		return false
+	}
+
+	if ok := ExpectProviderErrorSurfaced(testFramework, invalidSubnet); !ok {
+		return false
+	}
+
+	By("Checking the healthy machines are not removed")
+
+	return Consistently(komega.ObjectList(&machinev1beta1.MachineList{}, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())), 2*time.Minute, 10*time.Second).Should(
+		HaveField("Items", SatisfyAll(
+			HaveLen(desiredReplicas+1),
+			HaveEach(HaveField("ObjectMeta.DeletionTimestamp", BeNil())),
+		)), "healthy control plane machines should not be removed while the replacement has failed",
+	)
+}
+
+// nonexistentSubnet returns a subnet, in the form used by the platform, which does not exist.
+// It returns false on platforms where subnets are not tested.
+func nonexistentSubnet(platform configv1.PlatformType) (string, bool) {
+	switch platform {
+	case configv1.AWSPlatformType:
+		return "subnet-0e2e0000000000000", true
+	case configv1.AzurePlatformType:
+		return "e2e-nonexistent-subnet", true
+	case configv1.GCPPlatformType:
+		return "e2e-nonexistent-subnetwork", true
+	default:
+		return "", false
+	}
+}
+
+// ExpectEmptyTemplateRejected checks that an update removing the machine template from the control plane machine set
+// is rejected, with an error naming the missing template field.
+// A control plane machine set without a template would not be able to create bootable machines.
+// Should the update be accepted, the original template is restored before failing.
+func ExpectEmptyTemplateRejected(testFramework framework.Framework) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	originalTemplate := cpms.Spec.Template.DeepCopy()
+
+	By("Attempting to remove the machine template from the control plane machine set")
+
+	err := komega.Update(cpms, func() {
+		cpms.Spec.Template.OpenShiftMachineV1Beta1Machine = nil
+	})()
+	if err == nil {
+		By("Restoring the machine template of the control plane machine set")
+
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template = *originalTemplate
+		})).Should(Succeed(), "control plane machine set template should be able to be restored")
+	}
+
+	if ok := Expect(err).To(MatchError(ContainSubstring("%s configuration is required", machinev1.OpenShiftMachineV1Beta1MachineType)),
+		"removing the machine template should be rejected with an error naming the required field"); !ok {
+		return false
+	}
+
+	By("Checking the machine template of the control plane machine set is unchanged")
+
+	return Expect(komega.Object(testFramework.NewEmptyControlPlaneMachineSet())()).To(
+		HaveField("Spec.Template.OpenShiftMachineV1Beta1Machine", Not(BeNil())),
+		"control plane machine set should keep its machine template",
+	)
+}
+
+// ExpectUnknownPlatformRejected checks that the control plane machine set cannot be updated to use a failure domains
+// platform the operator does not support failure domains for, and that the rejection lists the supported platforms.
+// Should the update be accepted, the original failure domains are restored before failing.
+func ExpectUnknownPlatformRejected(testFramework framework.Framework) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	originalFailureDomains := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains.DeepCopy()
+
+	By(fmt.Sprintf("Attempting to set the failure domains platform of the control plane machine set to %s", unknownFailureDomainsPlatform))
+
+	if ok := Expect(updateToUnknownFailureDomainsPlatform(testFramework)).To(MatchError(SatisfyAll(
+		ContainSubstring("failureDomains.platform"),
+		ContainSubstring("Unsupported value: %q", unknownFailureDomainsPlatform),
+		ContainSubstring(`supported values: "AWS", "Azure", "GCP"`),
+	)), "an unknown failure domains platform should be rejected with an error listing the supported platforms"); !ok {
+		return false
+	}
+
+	By("Checking the failure domains of the control plane machine set are unchanged")
+
+	return Expect(komega.Object(testFramework.NewEmptyControlPlaneMachineSet())()).To(
+		HaveField("Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains", Equal(*originalFailureDomains)),
+		"control plane machine set should keep its failure domains",
+	)
+}
+
+// updateToUnknownFailureDomainsPlatform attempts to update the control plane machine set to use the
+// unknownFailureDomainsPlatform, and returns the error from the update.
+// Should the update be accepted, the original failure domains are restored before returning.
+func updateToUnknownFailureDomainsPlatform(testFramework framework.Framework) error {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+	if err := komega.Get(cpms)(); err != nil {
+		return fmt.Errorf("could not get control plane machine set: %w", err)
+	}
+
+	originalFailureDomains := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains.DeepCopy()
+
+	err := komega.Update(cpms, func() {
+		cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains = machinev1.FailureDomains{
+			Platform: unknownFailureDomainsPlatform,
+		}
+	})()
+	if err == nil {
+		By("Restoring the failure domains of the control plane machine set")
+
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains = *originalFailureDomains
+		})).Should(Succeed(), "control plane machine set failure domains should be able to be restored")
+	}
+
+	return err
+}
+
+// ExpectInvalidStrategyRejected checks that the control plane machine set cannot be updated to use the given update
+// strategy type, and that the strategy of the control plane machine set is unchanged.
+// The strategy type is validated as an enum by the API, only RollingUpdate and OnDelete are allowed.
+// Recreate is defined by the API, but removing a control plane machine before its replacement is created would
+// risk the quorum of etcd, and so it is not allowed for control plane machine sets.
+// If the update is unexpectedly accepted, the original strategy is restored.
+func ExpectInvalidStrategyRejected(testFramework framework.Framework, badValue string) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	originalStrategy := cpms.Spec.Strategy.Type
+
+	By(fmt.Sprintf("Attempting to set the control plane machine set strategy to %s", badValue))
+
+	err := komega.Update(cpms, func() {
+		cpms.Spec.Strategy.Type = machinev1.ControlPlaneMachineSetStrategyType(badValue)
+	})()
+	if err == nil {
+		EnsureControlPlaneMachineSetUpdateStrategy(testFramework, originalStrategy)
+	}
+
+	if ok := Expect(err).To(MatchError(ContainSubstring("Unsupported value: %q", badValue)),
+		"setting the strategy to %s should be rejected", badValue); !ok {
+		return false
+	}
+
+	By("Checking the strategy of the control plane machine set is unchanged")
+
+	return Expect(komega.Object(testFramework.NewEmptyControlPlaneMachineSet())()).To(
+		HaveField("Spec.Strategy.Type", Equal(originalStrategy)),
+		"control plane machine set should keep its update strategy",
+	)
+}
+
+// ExpectZeroReplicasRejected checks that the control plane machine set cannot be scaled to zero replicas, as a
+// control plane cannot operate without any machines, and that the replicas of the control plane machine set are
+// unchanged. The replicas are validated as an enum by the API, so the error must state the allowed values, 3 and 5.
+// If the update is unexpectedly accepted, the original replicas are restored.
+func ExpectZeroReplicasRejected(testFramework framework.Framework) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	if ok := Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set"); !ok {
+		return false
+	}
+
+	originalReplicas := *cpms.Spec.Replicas
+
+	By("Attempting to set the control plane machine set replicas to 0")
+
+	err := komega.Update(cpms, func() {
+		cpms.Spec.Replicas = pointer.Int32(0)
+	})()
+	if err == nil {
+		Eventually(komega.Update(cpms, func() {
+			cpms.Spec.Replicas = pointer.Int32(originalReplicas)
+		})).Should(Succeed(), "control plane machine set replicas should be able to be restored")
+	}
+
+	if ok := Expect(err).To(MatchError(ContainSubstring(`Unsupported value: 0: supported values: "3", "5"`)),
+		"setting the replicas to 0 should be rejected, stating the allowed values"); !ok {
+		return false
+	}
+
+	By("Checking the replicas of the control plane machine set are unchanged")
+
+	return Expect(komega.Object(testFramework.NewEmptyControlPlaneMachineSet())()).To(
+		HaveField("Spec.Replicas", HaveValue(Equal(originalReplicas))),
+		"control plane machine set should keep its replicas",
+	)
+}
+
+// ExpectScaleDownRemovesHighestIndexesFirst checks that, when a 5 replica control plane machine set is scaled down
+// to 3 replicas, the machines in indexes 4 and 3 are removed and the machines in indexes 0 to 2 are kept.
+// The control plane must maintain quorum throughout the scale down.
+// The check is skipped on clusters that do not have 5 control plane replicas, or when the control plane machine set
+// rejects the change in replicas, as the replicas are currently immutable.
+// The control plane machine set is left with the reduced number of replicas.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+func ExpectScaleDownRemovesHighestIndexesFirst(testFramework framework.Framework, ctx context.Context) bool {
+	k8sClient := testFramework.GetClient()
+
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	if cpms.Spec.Replicas == nil || *cpms.Spec.Replicas != scaleDownFromReplicas {
+		By(fmt.Sprintf("Skipping scale down check as the control plane machine set does not have %d replicas", scaleDownFromReplicas))
+		return true
+	}
+
+	quorum := int32(scaleDownFromReplicas/2 + 1)
+	desiredReplicas := int32(scaleDownToReplicas)
+
+	By("Checking whether the control plane machine set supports scaling down")
+
+	scaledCPMS := cpms.DeepCopy()
+	scaledCPMS.Spec.Replicas = &desiredReplicas
+
+	if err := k8sClient.Update(ctx, scaledCPMS, runtimeclient.DryRunAll); apierrors.IsInvalid(err) {
+		By(fmt.Sprintf("Skipping scale down check as the control plane machine set does not support scaling down: %v", err))
+		return true
+	} else if ok := Expect(err).ToNot(HaveOccurred(), "dry run scale down of the control plane machine set should succeed"); !ok {
+		return false
+	}
+
+	machineList := &machinev1beta1.MachineList{}
+	machineSelector := runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())
+
+	if ok := Expect(k8sClient.List(ctx, machineList, machineSelector)).To(Succeed(), "should be able to list control plane machines"); !ok {
+		return false
+	}
+
+	keptMachines := []machinev1beta1.Machine{}
+	keptIndexes := []int{}
+
+	for _, machine := range machineList.Items {
+		index, err := machineIndex(machine)
+		if ok := Expect(err).ToNot(HaveOccurred(), "should be able to determine the index of machine %s", machine.Name); !ok {
+			return false
+		}
+
+		if index < scaleDownToReplicas {
+			keptMachines = append(keptMachines, machine)
+			keptIndexes = append(keptIndexes, index)
+		}
+	}
+
+	By(fmt.Sprintf("Scaling the control plane machine set down to %d replicas", scaleDownToReplicas))
+
+	if ok := Eventually(komega.Update(cpms, func() {
+		cpms.Spec.Replicas = &desiredReplicas
+	})).WithContext(ctx).Should(Succeed(), "control plane machine set should be able to be scaled down"); !ok {
+		return false
+	}
+
+	scaleDownCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	wg := &sync.WaitGroup{}
+
+	framework.Async(wg, cancel, func() bool {
+		By(fmt.Sprintf("Checking the control plane never has fewer than %d ready replicas", quorum))
+
+		return Consistently(komega.Object(cpms.DeepCopy())).WithContext(scaleDownCtx).Should(
+			HaveField("Status.ReadyReplicas", BeNumerically(">=", quorum)),
+			"control plane should maintain quorum throughout the scale down",
+		)
+	})
+
+	framework.Async(wg, cancel, func() bool {
+		By(fmt.Sprintf("Waiting for the control plane machines to only have indexes %v", keptIndexes))
+
+		return Eventually(komega.ObjectList(&machinev1beta1.MachineList{}, machineSelector)).WithContext(scaleDownCtx).Should(
+			HaveField("Items", WithTransform(func(machines []machinev1beta1.Machine) []int {
+				indexes := []int{}
+
+				for _, machine := range machines {
+					index, err := machineIndex(machine)
+					if err != nil {
+						return nil
+					}
+
+					indexes = append(indexes, index)
+				}
+
+				return indexes
+			}, ConsistOf(keptIndexes))),
+			"control plane machines in the highest indexes should be removed",
+		)
+	})
+
+	wg.Wait()
+
+	if ok := Expect(scaleDownCtx.Err()).ToNot(HaveOccurred(), "scale down should have completed successfully"); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the control plane machines in indexes %v were not replaced", keptIndexes))
+
+	for i := range keptMachines {
+		machine := &keptMachines[i]
+		originalUID := machine.UID
+
+		if ok := Expect(komega.Object(machine)()).To(SatisfyAll(
+			HaveField("ObjectMeta.UID", Equal(originalUID)),
+			HaveField("ObjectMeta.DeletionTimestamp", BeNil()),
+		), "machine %s should not be removed during the scale down", machine.Name); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ExpectBelowQuorumCondition checks that, when too few control plane machines are ready to maintain etcd quorum, the
+// operator reports a Progressing condition with the BelowQuorum reason and does not remove any control plane machine.
+// Unhealthy machines are simulated by marking enough machines, starting from index 1, with the Failed phase, which the
+// Machine API does not reconcile further. In a 3 replica control plane this is two machines. No machine may be deleted
+// while the control plane is below quorum, though the operator may still create machines to restore quorum.
+// The original phases are restored before returning.
+// As the operator would otherwise replace the failed machines, this check must only be run in disruptive tests.
+func ExpectBelowQuorumCondition(testFramework framework.Framework) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	if ok := Expect(cpms.Spec.Replicas).ToNot(BeNil(), "replicas should always be set"); !ok {
+		return false
+	}
+
+	desiredReplicas := int(*cpms.Spec.Replicas)
+	unhealthyCount := desiredReplicas - (desiredReplicas/2 + 1) + 1
+
+	for index := 1; index <= unhealthyCount; index++ {
+		machine, err := machineForIndex(testFramework, index)
+		if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the machine for index %d", index); !ok {
+			return false
+		}
+
+		originalPhase := machine.Status.Phase
+
+		By(fmt.Sprintf("Marking machine %s as Failed", machine.Name))
+
+		if ok := Eventually(komega.UpdateStatus(machine, func() {
+			machine.Status.Phase = pointer.String("Failed")
+		})).Should(Succeed(), "machine %s status should be able to be updated", machine.Name); !ok {
+			return false
+		}
+
+		defer func() {
+			By(fmt.Sprintf("Restoring the phase of machine %s", machine.Name))
+
+			Eventually(komega.UpdateStatus(machine, func() {
+				machine.Status.Phase = originalPhase
+			})).Should(Succeed(), "machine %s status should be able to be restored", machine.Name)
+		}()
+	}
+
+	By(fmt.Sprintf("Waiting for the control plane machine set to report a Progressing condition with reason %s", belowQuorumReason))
+
+	if ok := Eventually(komega.Object(cpms), 5*time.Minute, 10*time.Second).Should(
+		HaveField("Status.Conditions", ContainElement(SatisfyAll(
+			HaveField("Type", Equal("Progressing")),
+			HaveField("Status", Equal(metav1.ConditionTrue)),
+			HaveField("Reason", Equal(belowQuorumReason)),
+			HaveField("Message", ContainSubstring("required to maintain quorum")),
+		))),
+		func() string {
+			return fmt.Sprintf("control plane machine set should report a Progressing condition with reason %s, observed conditions:\n%s",
+				belowQuorumReason, format.Object(cpms.Status.Conditions, 1))
+		},
+	); !ok {
+		return false
+	}
+
+	By("Checking the operator does not remove any control plane machine while below quorum")
+
+	machineList := &machinev1beta1.MachineList{}
+	machineSelector := runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())
+
+	return Consistently(komega.ObjectList(machineList, machineSelector), 2*time.Minute, 10*time.Second).Should(
+		HaveField("Items", HaveEach(HaveField("ObjectMeta.DeletionTimestamp", BeNil()))),
+		"no control plane machines should be deleted while below quorum",
+	)
+}
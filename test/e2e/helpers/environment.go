@@ -0,0 +1,72 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+)
+
+const (
+	// disruptiveTestsEnvVar is the environment variable that opts into tests which deliberately
+	// disrupt the cluster, for example by interfering with node status or cloud provider resources.
+	disruptiveTestsEnvVar = "CPMS_E2E_ENABLE_DISRUPTIVE_TESTS"
+
+	// etcdLeaderOrderEnvVar is the environment variable that opts into checking that the etcd leader is not
+	// the first control plane machine replaced during a full rollout. The operator does not consider etcd
+	// leadership when ordering replacements, so this check documents the gap and is not run by default.
+	etcdLeaderOrderEnvVar = "CPMS_E2E_ENABLE_ETCD_LEADER_ORDER_CHECK"
+
+	// loadBalancerNameEnvVar is the environment variable holding the name of a pre-existing
+	// load balancer that control plane machines can be attached to.
+	loadBalancerNameEnvVar = "CPMS_E2E_LOAD_BALANCER_NAME"
+
+	// gcpServiceAccountEmailEnvVar is the environment variable holding the email of a pre-existing
+	// GCP service account, with the permissions required by control plane machines.
+	gcpServiceAccountEmailEnvVar = "CPMS_E2E_GCP_SERVICE_ACCOUNT_EMAIL"
+
+	// bootImageEnvVar is the environment variable holding a boot image, for the current platform,
+	// that control plane machines can be booted from, for example an AWS AMI ID.
+	bootImageEnvVar = "CPMS_E2E_BOOT_IMAGE"
+
+	// kmsKeyEnvVar is the environment variable holding a pre-existing root disk encryption key, for the current
+	// platform, that control plane machines can be encrypted with. On AWS this is the ARN of a KMS key, on Azure
+	// the ID of a disk encryption set, and on GCP the full resource name of a KMS key.
+	kmsKeyEnvVar = "CPMS_E2E_KMS_KEY"
+
+	// zeroCapacityZoneEnvVar is the environment variable holding the name of an AWS availability zone
+	// in which the control plane instance type has no capacity.
+	zeroCapacityZoneEnvVar = "CPMS_E2E_ZERO_CAPACITY_AVAILABILITY_ZONE"
+
+	// quotaExhaustedInstanceTypeEnvVar is the environment variable holding an instance type, for the current
+	// platform, for which the cloud account has no remaining quota.
+	quotaExhaustedInstanceTypeEnvVar = "CPMS_E2E_QUOTA_EXHAUSTED_INSTANCE_TYPE"
+)
+
+// lookupEnvOrSkip returns the value of the environment variable.
+// If the environment variable is not set, or is empty, the test is skipped.
+// This allows tests that require resources external to the cluster to be opted into.
+func lookupEnvOrSkip(key string) string {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		Skip(fmt.Sprintf("Skipping as the environment variable %s is not set", key))
+	}
+
+	return value
+}
@@ -18,6 +18,7 @@ package helpers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
@@ -29,9 +30,14 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	configv1 "github.com/openshift/api/config/v1"
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/cluster-control-plane-machine-set-operator/pkg/machineproviders"
 	"github.com/openshift/cluster-control-plane-machine-set-operator/test/e2e/framework"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
 )
@@ -40,6 +46,16 @@ const (
 	// machineClusterIDLabel is the label used to identify the cluster a machine belongs to.
 	// We use this to check that the Machine name has the expected format.
 	machineClusterIDLabel = "machine.openshift.io/cluster-api-cluster"
+
+	// e2eSecurityGroup is the security group added to control plane machines to drive a rollout.
+	e2eSecurityGroup = "cpms-e2e-security-group"
+
+	// e2eLifecycleHookOwner is the owner of lifecycle hooks added to control plane machines by the e2e tests.
+	e2eLifecycleHookOwner = "cpms-e2e"
+
+	// e2eConflictAnnotation is the annotation the e2e tests write to control plane machines to change their
+	// resourceVersion while the operator is updating them.
+	e2eConflictAnnotation = "machine.openshift.io/cpms-e2e-conflict-generation"
 )
 
 var (
@@ -48,6 +64,9 @@ var (
 
 	// errMoreThanOneMachineInIndex is returned when there is more than one machine in the given index.
 	errMoreThanOneMachineInIndex = errors.New("more than one control plane machine in index")
+
+	// errNoMachineInIndex is returned when there is no machine in the given index.
+	errNoMachineInIndex = errors.New("no control plane machine in index")
 )
 
 // CheckControlPlaneMachineRollingReplacement checks that the machines with the given index
@@ -74,6 +93,11 @@ func CheckControlPlaneMachineRollingReplacement(testFramework framework.Framewor
 	}
 
 	By("Replacement machine is Running")
+
+	if ok := ExpectReplacementOnDifferentHost(testFramework, idx); !ok {
+		return false
+	}
+
 	By("Checking that the old machine is marked for deletion")
 
 	if ok := Eventually(komega.Object(oldMachine), ctx).Should(HaveField("ObjectMeta.DeletionTimestamp", Not(BeNil())), "expected old machine to be marked for deletion"); !ok {
@@ -377,6 +401,7 @@ func IncreaseControlPlaneMachineInstanceSize(testFramework framework.Framework,
 
 	updatedProviderSpec := originalProviderSpec.DeepCopy()
 	Expect(testFramework.IncreaseProviderSpecInstanceSize(updatedProviderSpec.Value)).To(Succeed(), "provider spec should be updated with bigger instance size")
+	expectInstanceTypeChanged(originalProviderSpec, *updatedProviderSpec)
 
 	By(fmt.Sprintf("Updating the provider spec of the control plane machine at index %d", index))
 
@@ -388,6 +413,129 @@ func IncreaseControlPlaneMachineInstanceSize(testFramework framework.Framework,
 	return originalProviderSpec, machine.Spec.ProviderSpec
 }
 
+// IncreaseControlPlaneMachineDiskSize increases the root disk size of the control plane machine
+// in the given index. This should trigger the control plane machine set to update the machine in
+// this index based on the update strategy.
+// The original provider spec of the machine is returned so that it can be restored.
+func IncreaseControlPlaneMachineDiskSize(testFramework framework.Framework, index int) machinev1beta1.ProviderSpec {
+	machine, err := machineForIndex(testFramework, index)
+	Expect(err).ToNot(HaveOccurred(), "control plane machine should exist")
+
+	originalProviderSpec := machine.Spec.ProviderSpec
+
+	updatedProviderSpec := originalProviderSpec.DeepCopy()
+	Expect(framework.IncreaseProviderSpecRootDiskSize(updatedProviderSpec.Value)).To(Succeed(), "provider spec should be updated with a bigger root disk")
+
+	By(fmt.Sprintf("Updating the root disk size of the control plane machine at index %d", index))
+
+	Eventually(komega.Update(machine, func() {
+		machine.Spec.ProviderSpec = *updatedProviderSpec
+	})).Should(Succeed(), "control plane machine should be able to be updated")
+
+	return originalProviderSpec
+}
+
+// ChangeControlPlaneMachineDiskType changes the root disk type of the control plane machine in the given index.
+// This should trigger the control plane machine set to update the machine in this index based on the update strategy.
+// The test is skipped on platforms where the disk type cannot be changed.
+// The original provider spec of the machine is returned so that it can be restored.
+func ChangeControlPlaneMachineDiskType(testFramework framework.Framework, index int) machinev1beta1.ProviderSpec {
+	switch testFramework.GetPlatformType() {
+	case configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType:
+	default:
+		Skip(fmt.Sprintf("Skipping as disk type changes are not supported on platform %s", testFramework.GetPlatformType()))
+	}
+
+	machine, err := machineForIndex(testFramework, index)
+	Expect(err).ToNot(HaveOccurred(), "control plane machine should exist")
+
+	originalProviderSpec := machine.Spec.ProviderSpec
+
+	originalDiskType, err := framework.ProviderSpecDiskType(originalProviderSpec.Value)
+	Expect(err).ToNot(HaveOccurred(), "should be able to read the disk type of the control plane machine")
+
+	diskType := alternativeDiskType(testFramework.GetPlatformType(), originalDiskType)
+
+	updatedProviderSpec := originalProviderSpec.DeepCopy()
+	Expect(framework.UpdateProviderSpecDiskType(updatedProviderSpec.Value, diskType)).To(Succeed(), "provider spec should be updated with the new disk type")
+
+	By(fmt.Sprintf("Updating the disk type of the control plane machine at index %d to %s", index, diskType))
+
+	Eventually(komega.Update(machine, func() {
+		machine.Spec.ProviderSpec = *updatedProviderSpec
+	})).Should(Succeed(), "control plane machine should be able to be updated")
+
+	return originalProviderSpec
+}
+
+// AddControlPlaneMachineSecurityGroup adds a security group to the control plane machine in the given index.
+// This should trigger the control plane machine set to update the machine in this index based on the update strategy.
+// Only the machine spec is changed, the security group is not applied to the existing instance, and so the security
+// group does not need to exist.
+// The original provider spec of the machine is returned so that it can be restored.
+func AddControlPlaneMachineSecurityGroup(testFramework framework.Framework, index int) machinev1beta1.ProviderSpec {
+	switch testFramework.GetPlatformType() {
+	case configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType:
+	default:
+		Skip(fmt.Sprintf("Skipping as security group changes are not supported on platform %s", testFramework.GetPlatformType()))
+	}
+
+	machine, err := machineForIndex(testFramework, index)
+	Expect(err).ToNot(HaveOccurred(), "control plane machine should exist")
+
+	originalProviderSpec := machine.Spec.ProviderSpec
+
+	updatedProviderSpec := originalProviderSpec.DeepCopy()
+	Expect(framework.AddProviderSpecSecurityGroup(updatedProviderSpec.Value, e2eSecurityGroup)).To(Succeed(), "provider spec should be updated with the new security group")
+
+	By(fmt.Sprintf("Adding security group %s to the control plane machine at index %d", e2eSecurityGroup, index))
+
+	Eventually(komega.Update(machine, func() {
+		machine.Spec.ProviderSpec = *updatedProviderSpec
+	})).Should(Succeed(), "control plane machine should be able to be updated")
+
+	return originalProviderSpec
+}
+
+// alternativeDiskType returns a disk type, for the platform, that differs from the current disk type.
+// Where possible, this is a faster disk type suitable for etcd.
+func alternativeDiskType(platform configv1.PlatformType, current string) string {
+	switch platform {
+	case configv1.AWSPlatformType:
+		if current == "gp3" {
+			return "io2"
+		}
+
+		return "gp3"
+	case configv1.AzurePlatformType:
+		if current == "Premium_LRS" {
+			return "StandardSSD_LRS"
+		}
+
+		return "Premium_LRS"
+	case configv1.GCPPlatformType:
+		if current == "pd-ssd" {
+			return "pd-balanced"
+		}
+
+		return "pd-ssd"
+	default:
+		return current
+	}
+}
+
+// expectInstanceTypeChanged checks that the instance type of the updated provider spec differs from
+// the instance type of the original provider spec.
+func expectInstanceTypeChanged(originalProviderSpec, updatedProviderSpec machinev1beta1.ProviderSpec) {
+	originalInstanceType, err := framework.ProviderSpecInstanceType(originalProviderSpec.Value)
+	Expect(err).ToNot(HaveOccurred(), "should be able to read the original instance type")
+
+	updatedInstanceType, err := framework.ProviderSpecInstanceType(updatedProviderSpec.Value)
+	Expect(err).ToNot(HaveOccurred(), "should be able to read the updated instance type")
+
+	Expect(updatedInstanceType).ToNot(Equal(originalInstanceType), "instance type should have been changed")
+}
+
 // UpdateControlPlaneMachineProviderSpec updates the provider spec of the control plane machine in the given index
 // to match the provider spec given.
 func UpdateControlPlaneMachineProviderSpec(testFramework framework.Framework, index int, updatedProviderSpec machinev1beta1.ProviderSpec, gomegaArgs ...interface{}) {
@@ -468,6 +616,26 @@ func machineForIndex(testFramework framework.Framework, index int) (*machinev1be
 	return indexMachine, nil
 }
 
+// machinesForIndex returns all of the control plane machines in the given index.
+func machinesForIndex(testFramework framework.Framework, index int) ([]machinev1beta1.Machine, error) {
+	machineSelector := runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())
+	machineList := &machinev1beta1.MachineList{}
+
+	if err := testFramework.GetClient().List(testFramework.GetContext(), machineList, machineSelector); err != nil {
+		return nil, fmt.Errorf("could not list control plane machines: %w", err)
+	}
+
+	machines := []machinev1beta1.Machine{}
+
+	for _, machine := range machineList.Items {
+		if strings.HasSuffix(machine.Name, fmt.Sprintf("-%d", index)) {
+			machines = append(machines, machine)
+		}
+	}
+
+	return machines, nil
+}
+
 // sortMachinesByCreationTimeDescending sorts a slice of Machines by CreationTime, Name (descending).
 func sortMachinesByCreationTimeDescending(machines []machinev1beta1.Machine) []machinev1beta1.Machine {
 	// Sort in inverse order so that the newest one is first.
@@ -482,3 +650,940 @@ func sortMachinesByCreationTimeDescending(machines []machinev1beta1.Machine) []m
 
 	return machines
 }
+
+// EventuallyNodeForMachine waits for the node backing the machine to register and returns its name.
+// The node is matched on the provider ID so that the node can be found before the machine is linked to it.
+func EventuallyNodeForMachine(ctx context.Context, testFramework framework.Framework, machine *machinev1beta1.Machine) string {
+	var nodeName string
+
+	Eventually(func() (string, error) {
+		if err := testFramework.GetClient().Get(ctx, runtimeclient.ObjectKeyFromObject(machine), machine); err != nil {
+			return "", fmt.Errorf("failed to get machine: %w", err)
+		}
+
+		providerID := pointer.StringDeref(machine.Spec.ProviderID, "")
+		if providerID == "" {
+			return "", nil
+		}
+
+		nodeList := &corev1.NodeList{}
+		if err := testFramework.GetClient().List(ctx, nodeList); err != nil {
+			return "", fmt.Errorf("failed to list nodes: %w", err)
+		}
+
+		for _, node := range nodeList.Items {
+			if node.Spec.ProviderID == providerID {
+				nodeName = node.Name
+				break
+			}
+		}
+
+		return nodeName, nil
+	}).WithContext(ctx).ShouldNot(BeEmpty(), "expected the node for machine %s to register", machine.Name)
+
+	return nodeName
+}
+
+// ExpectReplacementOnDifferentHost checks that the replacement machine in the given index is not running
+// on the same underlying host as the machine it is replacing.
+// This must be called while both the old and replacement machines exist, once the replacement machine is Running.
+// On platforms that do not expose the underlying host in the machine provider status, this check is skipped.
+func ExpectReplacementOnDifferentHost(testFramework framework.Framework, index int) bool {
+	oldMachine, newMachine, ok := getOldAndNewMachineForIndex(testFramework.GetContext(), testFramework, index)
+	if !ok {
+		return false
+	}
+
+	oldHost, exposed, err := framework.GetMachineHost(*oldMachine)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the host of machine %s", oldMachine.Name); !ok {
+		return false
+	}
+
+	if !exposed {
+		By("Skipping host placement check as the platform does not expose the underlying host")
+		return true
+	}
+
+	newHost, _, err := framework.GetMachineHost(*newMachine)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the host of machine %s", newMachine.Name); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the replacement machine for index %d is not on host %s", index, oldHost))
+
+	return Expect(newHost).ToNot(Equal(oldHost), "replacement machine should not be co-located with the machine it replaces")
+}
+
+// ExpectMachineDeletionReasonAnnotation waits for a machine in the given index to be deleted by the control plane
+// machine set, and checks that the deleting machine has been annotated with the expected deletion reason.
+// The control plane machine set records the reason on the machine, using the
+// machine.openshift.io/control-plane-machine-set-deletion-reason annotation, before deleting it.
+// The reason is Replaced when an updated replacement exists for the index, or OutdatedNotReady when an
+// outdated machine was removed because it was not ready.
+func ExpectMachineDeletionReasonAnnotation(testFramework framework.Framework, ctx context.Context, index int, reason string) bool {
+	By(fmt.Sprintf("Waiting for the deleting machine in index %d to have the deletion reason %s", index, reason))
+
+	inIndex := func(machine machinev1beta1.Machine) bool {
+		idx, err := machineIndex(machine)
+
+		return err == nil && idx == index
+	}
+
+	machineList := &machinev1beta1.MachineList{}
+
+	return Eventually(komega.ObjectList(machineList, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels()))).WithContext(ctx).Should(
+		HaveField("Items", ContainElement(SatisfyAll(
+			WithTransform(inIndex, BeTrue()),
+			HaveField("ObjectMeta.DeletionTimestamp", Not(BeNil())),
+			HaveField("ObjectMeta.Annotations", HaveKeyWithValue(machineproviders.MachineDeletionReasonAnnotationKey, reason)),
+		))), "expected the deleting machine in index %d to have the deletion reason %s", index, reason)
+}
+
+// ExpectIndexReusedAfterReplacement checks that the replacement machine for the given index reuses the index
+// of the machine it replaces, and that the machine it replaces is removed.
+// Machine names are not otherwise compared, so platforms that include a random component in the name,
+// ahead of the index suffix, are supported.
+// This must be called once a replacement has been triggered for the index, it waits for the replacement
+// machine to be created and for the old machine to be removed.
+func ExpectIndexReusedAfterReplacement(testFramework framework.Framework, index int) bool {
+	ctx, cancel := context.WithTimeout(testFramework.GetContext(), 30*time.Minute)
+	defer cancel()
+
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	oldMachine, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the replacement machine %s reuses index %d", newMachine.Name, index))
+
+	if ok := Expect(newMachine.Name).To(SatisfyAll(
+		HaveSuffix(fmt.Sprintf("-%d", index)),
+		Not(Equal(oldMachine.Name)),
+	), "replacement machine name should end with the index of the machine it replaces"); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Waiting for the replaced machine %s to be removed", oldMachine.Name))
+
+	return Eventually(komega.Get(oldMachine)).WithContext(ctx).Should(MatchError(ContainSubstring("not found")),
+		"expected the replaced machine to be removed from the cluster")
+}
+
+// ExpectEncryptionPreservedAcrossRollout checks that the replacement machine for the given index has the same
+// root disk encryption settings as the machine it replaces.
+// Losing encryption on a replacement control plane machine would break compliance requirements, so both the
+// encryption state and the customer managed key must match.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+// On platforms without encryption settings support, this check is skipped.
+func ExpectEncryptionPreservedAcrossRollout(testFramework framework.Framework, ctx context.Context, index int) bool {
+	switch testFramework.GetPlatformType() {
+	case configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType:
+	default:
+		By(fmt.Sprintf("Skipping encryption check as encryption settings are not supported on platform %s", testFramework.GetPlatformType()))
+		return true
+	}
+
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	oldMachine, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the replacement machine %s preserves the encryption settings of %s", newMachine.Name, oldMachine.Name))
+
+	oldEncrypted, oldKMSKey, err := framework.ProviderSpecEncryptionSettings(oldMachine.Spec.ProviderSpec.Value)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the encryption settings of the original machine"); !ok {
+		return false
+	}
+
+	newEncrypted, newKMSKey, err := framework.ProviderSpecEncryptionSettings(newMachine.Spec.ProviderSpec.Value)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the encryption settings of the replacement machine"); !ok {
+		return false
+	}
+
+	if ok := Expect(newEncrypted).To(Equal(oldEncrypted), "replacement machine root disk encryption should match the original machine"); !ok {
+		return false
+	}
+
+	return Expect(newKMSKey).To(Equal(oldKMSKey), "replacement machine root disk encryption key should match the original machine")
+}
+
+// ExpectSecurityGroupsPreservedAcrossRollout checks that the replacement machine for the given index has the
+// security groups of the control plane machine set template.
+// Losing the control plane security groups would firewall off the API server on the replacement machine.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+// On platforms without security group support, this check is skipped.
+func ExpectSecurityGroupsPreservedAcrossRollout(testFramework framework.Framework, ctx context.Context, index int) bool {
+	switch testFramework.GetPlatformType() {
+	case configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType:
+	default:
+		By(fmt.Sprintf("Skipping security group check as security groups are not supported on platform %s", testFramework.GetPlatformType()))
+		return true
+	}
+
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	templateSecurityGroups, err := framework.ProviderSpecSecurityGroups(cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the security groups of the template"); !ok {
+		return false
+	}
+
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	_, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the replacement machine %s has the security groups of the template", newMachine.Name))
+
+	securityGroups, err := framework.ProviderSpecSecurityGroups(newMachine.Spec.ProviderSpec.Value)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the security groups of the replacement machine"); !ok {
+		return false
+	}
+
+	return Expect(securityGroups).To(ConsistOf(templateSecurityGroups), "replacement machine %s should have the security groups of the template", newMachine.Name)
+}
+
+// ExpectAcceleratorsPreservedAcrossRollout checks that the replacement machine for the given index has the
+// accelerators of the control plane machine set template.
+// Some specialised deployments attach accelerators to the control plane, and expect replacements to keep them.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+// On platforms that do not support attaching accelerators, this check is skipped.
+func ExpectAcceleratorsPreservedAcrossRollout(testFramework framework.Framework, ctx context.Context, index int) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	templateAccelerators, err := framework.ProviderSpecAccelerators(cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value)
+	if errors.Is(err, framework.ErrAcceleratorsNotSupported) {
+		By(fmt.Sprintf("Skipping accelerator check as accelerators are not supported on platform %s", testFramework.GetPlatformType()))
+		return true
+	}
+
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the accelerators of the template"); !ok {
+		return false
+	}
+
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	_, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the replacement machine %s has the accelerators of the template", newMachine.Name))
+
+	accelerators, err := framework.ProviderSpecAccelerators(newMachine.Spec.ProviderSpec.Value)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the accelerators of the replacement machine"); !ok {
+		return false
+	}
+
+	return Expect(accelerators).To(ConsistOf(templateAccelerators), "replacement machine %s should have the accelerators of the template", newMachine.Name)
+}
+
+// ExpectPreTerminateHookCompletes checks that the pre-terminate hook with the given name, on the machine in the
+// given index, blocks the deletion of the machine until the hook is removed, and that the deletion then completes.
+// The hook must already have been added to the machine, see addPreTerminateHook.
+// Pre-terminate hooks are only honoured once the machine has been drained, after all pre-drain hooks, such as the
+// etcd quorum hook, have been removed. So the check waits for the machine to be deleted and for its pre-drain hooks to
+// be removed, then checks that the machine is not removed while the hook is present, before removing the hook.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+func ExpectPreTerminateHookCompletes(testFramework framework.Framework, ctx context.Context, index int, hookName string) bool {
+	machines, err := machinesForIndex(testFramework, index)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the machines for index %d", index); !ok {
+		return false
+	}
+
+	var machine *machinev1beta1.Machine
+
+	for i := range machines {
+		if hasPreTerminateHook(&machines[i], hookName) {
+			machine = machines[i].DeepCopy()
+		}
+	}
+
+	if ok := Expect(machine).ToNot(BeNil(), "a machine in index %d should have the pre-terminate hook %s", index, hookName); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Waiting for machine %s to be deleted and for its pre-drain hooks to be removed", machine.Name))
+
+	if ok := Eventually(komega.Object(machine)).WithContext(ctx).Should(SatisfyAll(
+		HaveField("ObjectMeta.DeletionTimestamp", Not(BeNil())),
+		HaveField("Spec.LifecycleHooks.PreDrain", BeEmpty()),
+	), "machine %s should be deleted and have its pre-drain hooks removed", machine.Name); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the pre-terminate hook %s blocks the removal of machine %s", hookName, machine.Name))
+
+	if ok := Consistently(komega.Get(machine), 2*time.Minute, 10*time.Second).WithContext(ctx).Should(Succeed(),
+		"machine %s should not be removed while the pre-terminate hook %s is present", machine.Name, hookName); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Removing the pre-terminate hook %s from machine %s", hookName, machine.Name))
+
+	if ok := Eventually(komega.Update(machine, func() {
+		removePreTerminateHook(machine, hookName)
+	})).WithContext(ctx).Should(Succeed(), "should be able to remove the pre-terminate hook from machine %s", machine.Name); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Waiting for machine %s to be removed", machine.Name))
+
+	return Eventually(komega.Get(machine)).WithContext(ctx).Should(MatchError(ContainSubstring("not found")),
+		"machine %s should be removed once the pre-terminate hook is removed", machine.Name)
+}
+
+// addPreTerminateHook adds a pre-terminate hook with the given name to the machine, owned by the e2e tests.
+func addPreTerminateHook(machine *machinev1beta1.Machine, hookName string) {
+	if hasPreTerminateHook(machine, hookName) {
+		return
+	}
+
+	machine.Spec.LifecycleHooks.PreTerminate = append(machine.Spec.LifecycleHooks.PreTerminate, machinev1beta1.LifecycleHook{
+		Name:  hookName,
+		Owner: e2eLifecycleHookOwner,
+	})
+}
+
+// removePreTerminateHook removes the pre-terminate hook with the given name from the machine.
+func removePreTerminateHook(machine *machinev1beta1.Machine, hookName string) {
+	hooks := []machinev1beta1.LifecycleHook{}
+
+	for _, hook := range machine.Spec.LifecycleHooks.PreTerminate {
+		if hook.Name != hookName {
+			hooks = append(hooks, hook)
+		}
+	}
+
+	machine.Spec.LifecycleHooks.PreTerminate = hooks
+}
+
+// hasPreTerminateHook returns whether the machine has a pre-terminate hook with the given name.
+func hasPreTerminateHook(machine *machinev1beta1.Machine, hookName string) bool {
+	for _, hook := range machine.Spec.LifecycleHooks.PreTerminate {
+		if hook.Name == hookName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExpectReplacedMachineHasControlPlaneTaint checks that the replacement machine for the given index carries the
+// taints from the control plane machine set template, and that those taints are applied to its node.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+// A missing control plane taint would allow regular workloads to be scheduled onto the control plane.
+func ExpectReplacedMachineHasControlPlaneTaint(testFramework framework.Framework, ctx context.Context, index int) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	templateTaints := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.Taints
+
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	_, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the replacement machine %s carries the template taints", newMachine.Name))
+
+	taints, err := framework.MachineSpecTaints(newMachine)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the taints of the replacement machine"); !ok {
+		return false
+	}
+
+	if ok := Expect(taints).To(ConsistOf(templateTaints), "replacement machine should carry the taints from the template"); !ok {
+		return false
+	}
+
+	if len(templateTaints) == 0 {
+		return true
+	}
+
+	nodeName := EventuallyNodeForMachine(ctx, testFramework, newMachine)
+	if ok := Expect(nodeName).ToNot(BeEmpty(), "replacement machine node should register"); !ok {
+		return false
+	}
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+
+	elements := []interface{}{}
+
+	for _, taint := range templateTaints {
+		elements = append(elements, SatisfyAll(
+			HaveField("Key", Equal(taint.Key)),
+			HaveField("Value", Equal(taint.Value)),
+			HaveField("Effect", Equal(taint.Effect)),
+		))
+	}
+
+	return Eventually(komega.Object(node)).WithContext(ctx).Should(
+		HaveField("Spec.Taints", ContainElements(elements...)),
+		"replacement machine node %s should carry the taints from the template", nodeName,
+	)
+}
+
+// providerSpecKindForPlatform returns the kind of the provider spec expected for machines on the given platform.
+// An empty string is returned for platforms without a known provider spec kind.
+func providerSpecKindForPlatform(platform configv1.PlatformType) string {
+	switch platform {
+	case configv1.AWSPlatformType:
+		return "AWSMachineProviderConfig"
+	case configv1.AzurePlatformType:
+		return "AzureMachineProviderSpec"
+	case configv1.GCPPlatformType:
+		return "GCPMachineProviderSpec"
+	case configv1.VSpherePlatformType:
+		return "VSphereMachineProviderSpec"
+	case configv1.OpenStackPlatformType:
+		return "OpenstackProviderSpec"
+	case configv1.NutanixPlatformType:
+		return "NutanixMachineProviderConfig"
+	default:
+		return ""
+	}
+}
+
+// ExpectReplacedMachineProviderSpecKind checks that the provider spec of the replacement machine for the given index
+// has the expected kind.
+// The kind is decoded from the type metadata embedded in the raw provider spec. Were the operator to encode the
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+// provider spec with the wrong kind, the Machine API would be unable to decode it.
+func ExpectReplacedMachineProviderSpecKind(testFramework framework.Framework, ctx context.Context, index int, expectedKind string) bool {
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	_, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the replacement machine %s has a provider spec of kind %s", newMachine.Name, expectedKind))
+
+	if ok := Expect(newMachine.Spec.ProviderSpec.Value).ToNot(BeNil(), "replacement machine should have a provider spec"); !ok {
+		return false
+	}
+
+	typeMeta := metav1.TypeMeta{}
+	if ok := Expect(json.Unmarshal(newMachine.Spec.ProviderSpec.Value.Raw, &typeMeta)).To(Succeed(), "should be able to decode the provider spec type metadata"); !ok {
+		return false
+	}
+
+	if ok := Expect(typeMeta.APIVersion).ToNot(BeEmpty(), "replacement machine provider spec should have an apiVersion"); !ok {
+		return false
+	}
+
+	return Expect(typeMeta.Kind).To(Equal(expectedKind), "replacement machine provider spec should have the expected kind")
+}
+
+// ExpectAuthoritativeAPIPreserved checks that the replacement machine for the given index has the same authoritative
+// API as the machine it replaces, so that replacing a machine never flips which API is authoritative for it.
+// On clusters where the original machine has no authoritative API, without the Machine API migration feature,
+// this check is skipped.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+func ExpectAuthoritativeAPIPreserved(testFramework framework.Framework, ctx context.Context, index int) bool {
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	oldMachine, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	authoritativeAPIs := []string{}
+
+	for _, machine := range []*machinev1beta1.Machine{oldMachine, newMachine} {
+		authoritativeAPI, err := framework.MachineAuthoritativeAPI(machine)
+		if ok := Expect(err).ToNot(HaveOccurred(), "should be able to read the authoritative API of machine %s", machine.Name); !ok {
+			return false
+		}
+
+		authoritativeAPIs = append(authoritativeAPIs, authoritativeAPI)
+	}
+
+	if authoritativeAPIs[0] == "" {
+		By("Skipping authoritative API check as the machine does not have an authoritative API")
+		return true
+	}
+
+	By(fmt.Sprintf("Checking the replacement machine %s preserves the authoritative API %s", newMachine.Name, authoritativeAPIs[0]))
+
+	return Expect(authoritativeAPIs[1]).To(Equal(authoritativeAPIs[0]), "replacement machine should have the same authoritative API as the original machine")
+}
+
+// creationTimestampSkewTolerance is the tolerance allowed when comparing creation timestamps of machines.
+// Creation timestamps are set by whichever API server handles the request, with a precision of one second,
+// so timestamps set by different API servers may be skewed slightly.
+const creationTimestampSkewTolerance = 5 * time.Second
+
+// originalMachineCreationForIndex returns the creation timestamp of the oldest control plane machine in the given
+// index. When called before the index is replaced, this is the creation timestamp of the original machine.
+func originalMachineCreationForIndex(testFramework framework.Framework, index int) (time.Time, error) {
+	machineList := &machinev1beta1.MachineList{}
+	if err := testFramework.GetClient().List(testFramework.GetContext(), machineList, runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())); err != nil {
+		return time.Time{}, fmt.Errorf("could not list control plane machines: %w", err)
+	}
+
+	var oldest *metav1.Time
+
+	for _, machine := range machineList.Items {
+		if idx, err := machineIndex(machine); err != nil || idx != index {
+			continue
+		}
+
+		if oldest == nil || machine.CreationTimestamp.Before(oldest) {
+			oldest = machine.CreationTimestamp.DeepCopy()
+		}
+	}
+
+	if oldest == nil {
+		return time.Time{}, fmt.Errorf("%w: %d", errNoMachineInIndex, index)
+	}
+
+	return oldest.Time, nil
+}
+
+// ExpectReplacementNewerThanOriginal checks that the replacement machine for the given index was created after the
+// original machine, whose creation timestamp was recorded before the rollout began.
+// This catches a stale machine object being mistaken for the replacement. The replacement may be created by a
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+// different API server to the original, so a small clock skew is tolerated.
+func ExpectReplacementNewerThanOriginal(testFramework framework.Framework, ctx context.Context, index int, originalCreation time.Time) bool {
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	oldMachine, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the replacement machine %s is newer than the original machine %s", newMachine.Name, oldMachine.Name))
+
+	if ok := Expect(oldMachine.CreationTimestamp.Time).To(BeTemporally("==", originalCreation),
+		"original machine %s should have the recorded creation timestamp", oldMachine.Name); !ok {
+		return false
+	}
+
+	return Expect(newMachine.CreationTimestamp.Time).To(BeTemporally(">", originalCreation.Add(-creationTimestampSkewTolerance)),
+		"replacement machine %s should be created after the original machine", newMachine.Name)
+}
+
+// ExpectSubnetMatchesFailureDomain checks that the replacement machine for the given index is placed in the subnet
+// of the failure domain for its availability zone, as configured on the control plane machine set.
+// A mismatch would place a control plane machine in the wrong subnet.
+// Only AWS failure domains configure subnets, so on other platforms, or when the failure domains do not set a
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+// subnet, this check is skipped.
+func ExpectSubnetMatchesFailureDomain(testFramework framework.Framework, ctx context.Context, index int) bool {
+	if testFramework.GetPlatformType() != configv1.AWSPlatformType {
+		By(fmt.Sprintf("Skipping subnet check as failure domains do not configure subnets on platform %s", testFramework.GetPlatformType()))
+		return true
+	}
+
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	failureDomains := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains
+	if failureDomains.AWS == nil {
+		By("Skipping subnet check as the control plane machine set has no failure domains")
+		return true
+	}
+
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	_, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	zone, err := framework.GetAWSProviderSpecAvailabilityZone(newMachine.Spec.ProviderSpec.Value)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to read the availability zone of machine %s", newMachine.Name); !ok {
+		return false
+	}
+
+	expectedSubnets := []string{}
+
+	for _, failureDomain := range *failureDomains.AWS {
+		if failureDomain.Placement.AvailabilityZone != zone {
+			continue
+		}
+
+		if subnet := framework.AWSFailureDomainSubnet(failureDomain); subnet != "" {
+			expectedSubnets = append(expectedSubnets, subnet)
+		}
+	}
+
+	if len(expectedSubnets) == 0 {
+		By(fmt.Sprintf("Skipping subnet check as no failure domain configures a subnet for availability zone %s", zone))
+		return true
+	}
+
+	subnet, err := framework.ProviderSpecSubnet(newMachine.Spec.ProviderSpec.Value)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to read the subnet of machine %s", newMachine.Name); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the replacement machine %s uses the subnet of its failure domain", newMachine.Name))
+
+	return Expect(subnet).To(BeElementOf(expectedSubnets), "replacement machine should use the subnet of the failure domain for availability zone %s", zone)
+}
+
+// ExpectValidProviderIDAfterRollout checks that, once the replacement machine for the given index has been provisioned,
+// its provider ID matches the format expected for the platform.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+// The provider ID is what links the machine to its node, so a malformed value would leave the machine without a node.
+func ExpectValidProviderIDAfterRollout(testFramework framework.Framework, ctx context.Context, index int) bool {
+	switch testFramework.GetPlatformType() {
+	case configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType:
+	default:
+		By(fmt.Sprintf("Skipping provider ID check as the provider ID format is not known for platform %s", testFramework.GetPlatformType()))
+		return true
+	}
+
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	_, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Waiting for the replacement machine %s to have a provider ID", newMachine.Name))
+
+	if ok := Eventually(komega.Object(newMachine), ctx).Should(HaveField("Spec.ProviderID", HaveValue(Not(BeEmpty()))),
+		"expected replacement machine %s to have a provider ID", newMachine.Name); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the provider ID of the replacement machine %s is well formed", newMachine.Name))
+
+	return Expect(framework.ValidateProviderIDFormat(newMachine)).To(Succeed(), "replacement machine %s should have a valid provider ID", newMachine.Name)
+}
+
+// ExpectClusterIDTagPresent checks that the provider spec of the replacement machine for the given index tags the
+// instance as belonging to the cluster, using the infrastructure name from the Infrastructure resource.
+// Without this tag the cloud provider integration cannot discover the instance.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+// On Azure the tag is added by the Machine API provider rather than in the provider spec, so the check is skipped.
+func ExpectClusterIDTagPresent(testFramework framework.Framework, ctx context.Context, index int) bool {
+	switch testFramework.GetPlatformType() {
+	case configv1.AWSPlatformType, configv1.GCPPlatformType:
+	default:
+		By(fmt.Sprintf("Skipping cluster ID tag check as the tag is not set in the provider spec on platform %s", testFramework.GetPlatformType()))
+		return true
+	}
+
+	infra := &configv1.Infrastructure{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+		},
+	}
+
+	if ok := Expect(komega.Get(infra)()).To(Succeed(), "infrastructure resource should exist"); !ok {
+		return false
+	}
+
+	clusterID := infra.Status.InfrastructureName
+
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	_, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the replacement machine %s is tagged with the cluster ID %s", newMachine.Name, clusterID))
+
+	tagged, err := framework.ProviderSpecHasClusterIDTag(newMachine.Spec.ProviderSpec.Value, clusterID)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to read the tags from machine %s", newMachine.Name); !ok {
+		return false
+	}
+
+	return Expect(tagged).To(BeTrue(), "replacement machine %s should be tagged with the cluster ID %s", newMachine.Name, clusterID)
+}
+
+// ExpectOwnerReferenceUIDMatchesCurrentCPMS checks that the replacement machine for the given index is controlled
+// by the current control plane machine set, by comparing the UID of its controller owner reference with the UID
+// of the control plane machine set fetched once the replacement exists.
+// When the control plane machine set has been deleted and recreated, the new control plane machine set has a new UID,
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+// so a replacement carrying the UID of the previous control plane machine set would be garbage collected.
+func ExpectOwnerReferenceUIDMatchesCurrentCPMS(testFramework framework.Framework, ctx context.Context, index int) bool {
+	k8sClient := testFramework.GetClient()
+
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	_, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	// Fetch the control plane machine set after the replacement exists, so that a recreation before the
+	// rollout is reflected in the expected UID.
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+	if ok := Expect(k8sClient.Get(ctx, testFramework.ControlPlaneMachineSetKey(), cpms)).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the replacement machine %s is controlled by the control plane machine set with UID %s", newMachine.Name, cpms.UID))
+
+	return Expect(newMachine.GetOwnerReferences()).To(ContainElement(SatisfyAll(
+		HaveField("UID", Equal(cpms.UID)),
+		HaveField("Controller", HaveValue(BeTrue())),
+	)), "replacement machine %s should be controlled by the current control plane machine set", newMachine.Name)
+}
+
+// ExpectNewNodeHasInternalIP checks that the node of the replacement machine for the given index reports an internal
+// IP address, which the new control plane node needs to join etcd.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+// A running machine whose node has no internal IP address indicates a networking failure.
+func ExpectNewNodeHasInternalIP(testFramework framework.Framework, ctx context.Context, index int) bool {
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	_, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Waiting for the replacement machine %s to have a node", newMachine.Name))
+
+	if ok := Eventually(komega.Object(newMachine), ctx).Should(HaveField("Status.NodeRef", Not(BeNil())),
+		"expected replacement machine %s to have a node", newMachine.Name); !ok {
+		return false
+	}
+
+	// The node addresses are populated by the cloud controller manager once the node has registered,
+	// so allow some time for them to be reported.
+	By(fmt.Sprintf("Checking the node for machine %s has an internal IP address", newMachine.Name))
+
+	return Eventually(func() (string, error) {
+		return testFramework.NodeInternalIP(newMachine)
+	}, ctx).WithPolling(10*time.Second).ShouldNot(BeEmpty(), "node for replacement machine %s should have an internal IP address", newMachine.Name)
+}
+
+// ExpectReplacedNodeKubeletVersionConsistent checks that the node of the replacement machine for the given index runs
+// a kubelet with the same major and minor version as the cluster.
+// A mismatch would mean the replacement machine booted from the wrong image.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+// The check is skipped when the node does not report its kubelet version.
+func ExpectReplacedNodeKubeletVersionConsistent(testFramework framework.Framework, ctx context.Context, index int) bool {
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	_, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Waiting for the replacement machine %s to have a node", newMachine.Name))
+
+	if ok := Eventually(komega.Object(newMachine), ctx).Should(HaveField("Status.NodeRef", Not(BeNil())),
+		"expected replacement machine %s to have a node", newMachine.Name); !ok {
+		return false
+	}
+
+	kubeletVersion, err := testFramework.NodeKubeletVersion(newMachine)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the kubelet version of the node for machine %s", newMachine.Name); !ok {
+		return false
+	}
+
+	if kubeletVersion == "" {
+		By(fmt.Sprintf("Skipping kubelet version check as the node for machine %s does not report its kubelet version", newMachine.Name))
+		return true
+	}
+
+	clusterVersion, err := testFramework.APIServerVersion()
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the cluster version"); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the node for machine %s runs kubelet version %s, matching cluster version %s", newMachine.Name, kubeletVersion, clusterVersion))
+
+	matches, err := framework.KubeletVersionMatchesClusterVersion(kubeletVersion, clusterVersion)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to compare the kubelet and cluster versions"); !ok {
+		return false
+	}
+
+	return Expect(matches).To(BeTrue(), "node for replacement machine %s should run kubelet version matching the cluster: kubelet %s, cluster %s",
+		newMachine.Name, kubeletVersion, clusterVersion)
+}
+
+// ExpectReplacedMachineInSameRegion checks that the replacement machine for the given index is created in the same
+// region as the machine it replaces.
+// A rollout should never move a control plane machine to a different region, so this guards against a badly
+// misconfigured template.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+// The check is skipped when the provider spec of the original machine does not set the region.
+func ExpectReplacedMachineInSameRegion(testFramework framework.Framework, ctx context.Context, index int) bool {
+	switch testFramework.GetPlatformType() {
+	case configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType:
+	default:
+		By(fmt.Sprintf("Skipping region check as the region is not known for platform %s", testFramework.GetPlatformType()))
+		return true
+	}
+
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	oldMachine, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	originalRegion, err := framework.ProviderSpecRegion(oldMachine.Spec.ProviderSpec.Value)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to read the region of machine %s", oldMachine.Name); !ok {
+		return false
+	}
+
+	if originalRegion == "" {
+		By(fmt.Sprintf("Skipping region check as the provider spec of machine %s does not set the region", oldMachine.Name))
+		return true
+	}
+
+	region, err := framework.ProviderSpecRegion(newMachine.Spec.ProviderSpec.Value)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to read the region of machine %s", newMachine.Name); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the replacement machine %s is in region %s", newMachine.Name, originalRegion))
+
+	return Expect(region).To(Equal(originalRegion), "replacement machine %s should be in the same region as machine %s", newMachine.Name, oldMachine.Name)
+}
+
+// ExpectCloudIdentityAttached checks that the replacement machine for the given index is created with the cloud
+// identity of the control plane machine set template, that is the IAM instance profile on AWS, the managed identity
+// on Azure and the service account on GCP.
+// Without its identity, the replacement machine cannot reach the cloud APIs the control plane relies on.
+// The identity is checked on the replacement machine once it is running, the machine API only reports the machine as
+// running once the instance has been created, with the identity from its provider spec.
+// The e2e framework has no cloud API clients, so the identity attached to the instance at the cloud level cannot
+// be queried, and this part of the check is always skipped.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+// On platforms without cloud identities, or when the template attaches no identity, this check is skipped.
+func ExpectCloudIdentityAttached(testFramework framework.Framework, ctx context.Context, index int) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	templateIdentity, err := framework.ProviderSpecCloudIdentity(cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value)
+	if errors.Is(err, framework.ErrCloudIdentityNotSupported) {
+		By(fmt.Sprintf("Skipping cloud identity check as cloud identities are not supported on platform %s", testFramework.GetPlatformType()))
+		return true
+	}
+
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the cloud identity of the template"); !ok {
+		return false
+	}
+
+	if templateIdentity == "" {
+		By("Skipping cloud identity check as the template does not attach a cloud identity")
+		return true
+	}
+
+	if ok := EventuallyIndexIsBeingReplaced(ctx, index); !ok {
+		return false
+	}
+
+	_, newMachine, ok := getOldAndNewMachineForIndex(ctx, testFramework, index)
+	if !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the replacement machine %s has the cloud identity %s of the template", newMachine.Name, templateIdentity))
+
+	identity, err := framework.ProviderSpecCloudIdentity(newMachine.Spec.ProviderSpec.Value)
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the cloud identity of the replacement machine"); !ok {
+		return false
+	}
+
+	if ok := Expect(identity).To(Equal(templateIdentity), "replacement machine %s should have the cloud identity of the template", newMachine.Name); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Waiting for the replacement machine %s instance to be created with its cloud identity", newMachine.Name))
+
+	if ok := Eventually(komega.Object(newMachine), ctx).Should(HaveField("Status.Phase", HaveValue(Equal("Running"))),
+		"expected replacement machine %s to be running", newMachine.Name); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Skipping cloud level identity check for machine %s as the e2e framework has no cloud API credentials", newMachine.Name))
+
+	return true
+}
+
+// induceMachineUpdateConflicts repeatedly updates the conflict annotation on the machine, until the context is done,
+// so that the resourceVersion of the machine keeps changing while the operator is updating it.
+// Any update the operator makes from its cached copy of the machine is then made with a stale resourceVersion.
+// It returns the last value of the annotation that was successfully written to the machine.
+func induceMachineUpdateConflicts(ctx context.Context, machine *machinev1beta1.Machine) int {
+	written := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return written
+		case <-time.After(time.Second):
+		}
+
+		next := written + 1
+
+		// Conflicts with the operator are expected here, the update is retried on the next iteration.
+		if err := komega.Update(machine, func() {
+			annotations := machine.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+
+			annotations[e2eConflictAnnotation] = strconv.Itoa(next)
+			machine.SetAnnotations(annotations)
+		})(); err == nil {
+			written = next
+		}
+	}
+}
@@ -18,11 +18,21 @@ package helpers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
 
@@ -31,6 +41,14 @@ import (
 	"github.com/openshift/cluster-control-plane-machine-set-operator/test/e2e/framework"
 )
 
+var (
+	// errMachineRemovedHostingAPIServerLeader is returned when a machine is removed while it hosts the API server leader.
+	errMachineRemovedHostingAPIServerLeader = errors.New("machine was removed while hosting the API server leader")
+
+	// errOperatorLeaderChanged is returned when the operator leader changes while the previous leader pod is running.
+	errOperatorLeaderChanged = errors.New("operator leader changed while the previous leader pod was running")
+)
+
 // CheckRolloutForIndex first checks that a new machine is created in the correct index,
 // and then checks that the new machine in the index is replaced correctly.
 func CheckRolloutForIndex(testFramework framework.Framework, ctx context.Context, idx int, strategy machinev1.ControlPlaneMachineSetStrategyType) bool {
@@ -80,19 +98,436 @@ func CheckReplicasDoesNotExceedSurgeCapacity(ctx context.Context) bool {
 	)), "control plane machines should never go above 4 replicas, or below 3 replicas")
 }
 
+// CheckTotalReplicasNeverExceedDesiredPlusOne checks that, during a rollout, the total number of
+// control plane machines never exceeds the desired number of replicas plus 1 additional machine.
+// Unlike the per index surge checks, this catches the operator surging multiple indexes at once.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+func CheckTotalReplicasNeverExceedDesiredPlusOne(testFramework framework.Framework, ctx context.Context) bool {
+	return CheckTotalReplicasNeverExceedDesiredPlusSurge(testFramework, ctx, 1)
+}
+
+// CheckTotalReplicasNeverExceedDesiredPlusSurge checks that, during a rollout, the total number of
+// control plane machines never exceeds the desired number of replicas plus the given surge.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+func CheckTotalReplicasNeverExceedDesiredPlusSurge(testFramework framework.Framework, ctx context.Context, surge int) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	if ok := Expect(cpms.Spec.Replicas).ToNot(BeNil(), "control plane machine set should have replicas set"); !ok {
+		return false
+	}
+
+	maxReplicas := int(*cpms.Spec.Replicas) + surge
+
+	By(fmt.Sprintf("Checking the total number of control plane machines never goes above %d replicas", maxReplicas))
+
+	machineSelector := runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())
+
+	return Consistently(komega.ObjectList(&machinev1beta1.MachineList{}, machineSelector)).WithContext(ctx).Should(
+		HaveField("Items", WithTransform(func(machines []machinev1beta1.Machine) int {
+			return len(machines)
+		}, BeNumerically("<=", maxReplicas))),
+		"control plane machines should never go above %d replicas", maxReplicas,
+	)
+}
+
+// ExpectAtMostOneMachineDeletingAtATime checks that, during a rollout, no more than one control plane machine is being
+// deleted at any time. The RollingUpdate strategy replaces one machine at a time, so a second machine being deleted
+// means the operator has removed an extra etcd member, risking quorum.
+// It is intended to be run as an async check, so that a violation cancels the rollout context.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+func ExpectAtMostOneMachineDeletingAtATime(testFramework framework.Framework, ctx context.Context) bool {
+	By("Checking at most one control plane machine is being deleted at a time")
+
+	return Consistently(func() ([]string, error) {
+		machines, err := testFramework.MachinesBeingDeleted()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the control plane machines being deleted: %w", err)
+		}
+
+		names := []string{}
+
+		for _, machine := range machines {
+			names = append(names, machine.Name)
+		}
+
+		return names, nil
+	}).WithContext(ctx).Should(WithTransform(func(names []string) int {
+		return len(names)
+	}, BeNumerically("<=", 1)), "at most one control plane machine should be deleted at a time")
+}
+
+// CheckReadyReplicasNeverBelowQuorum checks that, during a rollout, the number of ready replicas reported in the
+// control plane machine set status never falls below quorum, that is 2 for 3 replicas, and 3 for 5 replicas.
+// This complements the machine level checks by asserting the availability reported to users.
+// It is intended to be run as an async check, so that a violation cancels the rollout context.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+func CheckReadyReplicasNeverBelowQuorum(testFramework framework.Framework, ctx context.Context) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	if ok := Expect(cpms.Spec.Replicas).ToNot(BeNil(), "control plane machine set should have replicas set"); !ok {
+		return false
+	}
+
+	quorum := *cpms.Spec.Replicas/2 + 1
+
+	By(fmt.Sprintf("Checking the control plane never has fewer than %d ready replicas", quorum))
+
+	return Consistently(komega.Object(cpms)).WithContext(ctx).Should(
+		HaveField("Status.ReadyReplicas", BeNumerically(">=", quorum)),
+		"control plane should maintain quorum throughout the rollout",
+	)
+}
+
+// masterMachineConfigPoolName is the name of the machine config pool containing the control plane nodes.
+const masterMachineConfigPoolName = "master"
+
+// machineConfigPoolGVK is the group version kind of the machine config pools managed by the machine config operator.
+// The machine config operator API is not vendored, so machine config pools are read as unstructured objects.
+//
+//nolint:gochecknoglobals
+var machineConfigPoolGVK = schema.GroupVersionKind{Group: "machineconfiguration.openshift.io", Version: "v1", Kind: "MachineConfigPool"}
+
+// machineConfigPoolState is the subset of the machine config pool status that must remain stable during a rollout.
+type machineConfigPoolState struct {
+	// RenderedConfig is the name of the rendered machine config targeted by the pool.
+	RenderedConfig string
+
+	// Degraded is the status of the Degraded condition of the pool.
+	Degraded string
+}
+
+// ExpectMachineConfigPoolsStable checks that, during a rollout, the master machine config pool does not become
+// degraded, and continues to target the same rendered machine config.
+// The number of machines in the pool is expected to change as nodes join and leave, so is not checked.
+// On clusters without the machine config operator, this check is skipped.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+func ExpectMachineConfigPoolsStable(testFramework framework.Framework, ctx context.Context) bool {
+	k8sClient := testFramework.GetClient()
+
+	getState := func() (machineConfigPoolState, error) {
+		pool := &unstructured.Unstructured{}
+		pool.SetGroupVersionKind(machineConfigPoolGVK)
+
+		if err := k8sClient.Get(ctx, runtimeclient.ObjectKey{Name: masterMachineConfigPoolName}, pool); err != nil {
+			return machineConfigPoolState{}, fmt.Errorf("could not get machine config pool %s: %w", masterMachineConfigPoolName, err)
+		}
+
+		renderedConfig, _, err := unstructured.NestedString(pool.Object, "spec", "configuration", "name")
+		if err != nil {
+			return machineConfigPoolState{}, fmt.Errorf("could not read the rendered config of machine config pool %s: %w", masterMachineConfigPoolName, err)
+		}
+
+		conditions, _, err := unstructured.NestedSlice(pool.Object, "status", "conditions")
+		if err != nil {
+			return machineConfigPoolState{}, fmt.Errorf("could not read the conditions of machine config pool %s: %w", masterMachineConfigPoolName, err)
+		}
+
+		state := machineConfigPoolState{RenderedConfig: renderedConfig}
+
+		for _, condition := range conditions {
+			conditionMap, ok := condition.(map[string]interface{})
+			if !ok || conditionMap["type"] != "Degraded" {
+				continue
+			}
+
+			state.Degraded, _ = conditionMap["status"].(string)
+		}
+
+		return state, nil
+	}
+
+	pool := &unstructured.Unstructured{}
+	pool.SetGroupVersionKind(machineConfigPoolGVK)
+
+	if err := k8sClient.Get(ctx, runtimeclient.ObjectKey{Name: masterMachineConfigPoolName}, pool); apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+		By("Skipping machine config pool check as the master machine config pool does not exist")
+		return true
+	}
+
+	initialState, err := getState()
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the master machine config pool"); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the master machine config pool is not degraded and continues to target %s", initialState.RenderedConfig))
+
+	return Consistently(getState).WithContext(ctx).Should(SatisfyAll(
+		HaveField("RenderedConfig", Equal(initialState.RenderedConfig)),
+		HaveField("Degraded", Not(Equal("True"))),
+	), "master machine config pool should remain stable during the rollout")
+}
+
 // checkRolloutProgress monitors the progress of each index in the rollout in turn.
 func checkRolloutProgress(testFramework framework.Framework, ctx context.Context) bool {
-	if ok := CheckRolloutForIndex(testFramework, ctx, 0, machinev1.RollingUpdate); !ok {
+	indexes, err := testFramework.ControlPlaneMachineIndexes()
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the control plane machine indexes"); !ok {
 		return false
 	}
 
-	if ok := CheckRolloutForIndex(testFramework, ctx, 1, machinev1.RollingUpdate); !ok {
+	for i, idx := range indexes {
+		// An index may already have a replacement, only check each index once.
+		if i > 0 && indexes[i-1] == idx {
+			continue
+		}
+
+		if ok := CheckRolloutForIndex(testFramework, ctx, idx, machinev1.RollingUpdate); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckEtcdLeaderReplacedLast checks, during a full rollout of a 5 replica control plane, that the machine hosting
+// the etcd leader before the rollout disrupts etcd is not the first machine to be replaced.
+// Replacing the leader first forces an additional leader election before any other member is replaced, so ideally
+// the leader is left until last. The operator replaces indexes in ascending order, regardless of etcd leadership, so
+// this check documents the gap and is only run when the CPMS_E2E_ENABLE_ETCD_LEADER_ORDER_CHECK environment variable
+// is set.
+// On clusters without 5 replicas, or when the etcd leader cannot be observed, this check is skipped.
+// It is intended to be run as an async check, so that a violation cancels the rollout context.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+func CheckEtcdLeaderReplacedLast(testFramework framework.Framework, ctx context.Context) bool {
+	if value, ok := os.LookupEnv(etcdLeaderOrderEnvVar); !ok || value == "" {
+		By(fmt.Sprintf("Skipping etcd leader replacement order check as the environment variable %s is not set", etcdLeaderOrderEnvVar))
+		return true
+	}
+
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
 		return false
 	}
 
-	if ok := CheckRolloutForIndex(testFramework, ctx, 2, machinev1.RollingUpdate); !ok {
+	if cpms.Spec.Replicas == nil || *cpms.Spec.Replicas != 5 {
+		By("Skipping etcd leader replacement order check as the control plane does not have 5 replicas")
+		return true
+	}
+
+	if _, err := testFramework.EtcdLeaderNodeName(); err != nil {
+		By(fmt.Sprintf("Skipping etcd leader replacement order check as the etcd leader cannot be observed: %v", err))
+		return true
+	}
+
+	By("Checking the etcd leader machine is not the first machine to be replaced")
+
+	machineSelector := runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())
+
+	var leaderMachineName, firstReplacedMachineName string
+
+	if ok := Eventually(func() (string, error) {
+		machineList := &machinev1beta1.MachineList{}
+		if err := testFramework.GetClient().List(ctx, machineList, machineSelector); err != nil {
+			return "", fmt.Errorf("failed to list machines: %w", err)
+		}
+
+		for _, machine := range machineList.Items {
+			if machine.GetDeletionTimestamp() != nil {
+				firstReplacedMachineName = machine.Name
+				return firstReplacedMachineName, nil
+			}
+		}
+
+		// Only track the leader until the first machine starts deleting so that
+		// the leader is the one observed before the rollout disrupted etcd.
+		// The leader may briefly be unobservable, in which case the last observed leader is kept.
+		if leaderNodeName, err := testFramework.EtcdLeaderNodeName(); err == nil {
+			for _, machine := range machineList.Items {
+				if machine.Status.NodeRef != nil && machine.Status.NodeRef.Name == leaderNodeName {
+					leaderMachineName = machine.Name
+				}
+			}
+		}
+
+		return "", nil
+	}, ctx).WithPolling(5*time.Second).ShouldNot(BeEmpty(), "a control plane machine should be replaced"); !ok {
 		return false
 	}
 
-	return true
+	if leaderMachineName == "" {
+		By("Skipping etcd leader replacement order check as the etcd leader was not observed on a control plane machine")
+		return true
+	}
+
+	return Expect(firstReplacedMachineName).ToNot(Equal(leaderMachineName),
+		"machine %s hosting the etcd leader should not be the first machine replaced", leaderMachineName)
+}
+
+// CheckAPIServerLeaderTransfersDuringRollout checks that, during a rollout, the kube-apiserver leader moves off each
+// machine being deleted before that machine is removed, minimising the disruption to the API.
+// A machine observed to host the leader while it is being deleted must not be removed until the leader has been
+// observed on another machine.
+// When the kube-apiserver leader cannot be observed, the check is skipped.
+// It is intended to be run as an async check, so that a violation cancels the rollout context.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+func CheckAPIServerLeaderTransfersDuringRollout(testFramework framework.Framework, ctx context.Context) bool {
+	machineSelector := runtimeclient.MatchingLabels(framework.ControlPlaneMachineSetSelectorLabels())
+
+	machineList := &machinev1beta1.MachineList{}
+	if ok := Expect(testFramework.GetClient().List(ctx, machineList, machineSelector)).To(Succeed(), "should be able to list control plane machines"); !ok {
+		return false
+	}
+
+	for i := range machineList.Items {
+		if machineList.Items[i].Status.NodeRef == nil {
+			continue
+		}
+
+		if _, err := testFramework.IsMachineHostingAPIServerLeader(&machineList.Items[i]); errors.Is(err, framework.ErrAPIServerLeaderNotObservable) {
+			By(fmt.Sprintf("Skipping API server leader transfer check: %v", err))
+			return true
+		}
+
+		break
+	}
+
+	By("Checking the API server leader moves off each machine before it is removed")
+
+	// Tracks the deleting machines last observed to host the API server leader.
+	hostingLeader := map[string]bool{}
+
+	return Consistently(func() error {
+		machineList := &machinev1beta1.MachineList{}
+		if err := testFramework.GetClient().List(ctx, machineList, machineSelector); err != nil {
+			return fmt.Errorf("failed to list machines: %w", err)
+		}
+
+		existing := map[string]bool{}
+
+		for i := range machineList.Items {
+			machine := &machineList.Items[i]
+			existing[machine.Name] = true
+
+			if machine.GetDeletionTimestamp() == nil || machine.Status.NodeRef == nil {
+				continue
+			}
+
+			hosting, err := testFramework.IsMachineHostingAPIServerLeader(machine)
+
+			switch {
+			case errors.Is(err, framework.ErrAPIServerLeaderNotObservable):
+				// The lease may briefly have no holder while the leader changes.
+				continue
+			case err != nil:
+				return fmt.Errorf("failed to check whether machine %s hosts the API server leader: %w", machine.Name, err)
+			}
+
+			hostingLeader[machine.Name] = hosting
+		}
+
+		for name, hosting := range hostingLeader {
+			if hosting && !existing[name] {
+				return fmt.Errorf("%w: %s", errMachineRemovedHostingAPIServerLeader, name)
+			}
+
+			if !existing[name] {
+				delete(hostingLeader, name)
+			}
+		}
+
+		return nil
+	}).WithContext(ctx).Should(Succeed(), "the API server leader should move off each machine before it is removed")
+}
+
+// ExpectLeaderStableDuringRollout checks that, during a rollout, the operator leadership does not flap, which would
+// indicate that the operator is crashing, and could otherwise look like a slow rollout.
+// The leader may move when its pod is removed, for example when the node hosting it is drained, but any other change
+// of the lease holder, including the same pod acquiring the lease with a new identity after a restart, is a failure.
+// When the operator leader cannot be observed, the check is skipped.
+// It is intended to be run as an async check, so that a violation cancels the rollout context.
+// This function explicitly takes a context which is expected to have a timeout in the parent scope.
+func ExpectLeaderStableDuringRollout(testFramework framework.Framework, ctx context.Context) bool {
+	leader, err := testFramework.OperatorLeaderIdentity()
+	if errors.Is(err, framework.ErrOperatorLeaderNotObservable) {
+		By(fmt.Sprintf("Skipping operator leader stability check: %v", err))
+		return true
+	}
+
+	if ok := Expect(err).ToNot(HaveOccurred(), "should be able to get the operator leader"); !ok {
+		return false
+	}
+
+	By(fmt.Sprintf("Checking the operator leader %s remains stable during the rollout", leader))
+
+	return Consistently(func() error {
+		identity, err := testFramework.OperatorLeaderIdentity()
+
+		switch {
+		case errors.Is(err, framework.ErrOperatorLeaderNotObservable):
+			// The lease may briefly have no holder while the leader changes.
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to get the operator leader: %w", err)
+		case identity == leader:
+			return nil
+		}
+
+		previousLeader := leader
+		leader = identity
+
+		// The identity is of the form <pod name>_<uuid>, pod names cannot contain underscores.
+		podName := previousLeader
+		if idx := strings.LastIndex(previousLeader, "_"); idx > 0 {
+			podName = previousLeader[:idx]
+		}
+
+		pod := &corev1.Pod{}
+		key := runtimeclient.ObjectKey{Namespace: framework.MachineAPINamespace, Name: podName}
+
+		if err := testFramework.GetClient().Get(ctx, key, pod); apierrors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to get the previous operator leader pod %s: %w", podName, err)
+		}
+
+		if pod.GetDeletionTimestamp() != nil {
+			return nil
+		}
+
+		return fmt.Errorf("%w: from %s to %s", errOperatorLeaderChanged, previousLeader, identity)
+	}).WithContext(ctx).Should(Succeed(), "the operator leader should only change when the leader pod is removed")
+}
+
+// checkRollingUpdateCompletes waits for a full rolling update of the control plane machines to complete,
+// checking that the surge capacity is respected and that each index is replaced in turn.
+func checkRollingUpdateCompletes(testFramework framework.Framework, rolloutTimeout time.Duration) bool {
+	cpms := testFramework.NewEmptyControlPlaneMachineSet()
+
+	if ok := Expect(komega.Get(cpms)()).To(Succeed(), "control plane machine set should exist"); !ok {
+		return false
+	}
+
+	rolloutCtx, cancel := context.WithTimeout(testFramework.GetContext(), rolloutTimeout)
+	defer cancel()
+
+	wg := &sync.WaitGroup{}
+
+	framework.Async(wg, cancel, func() bool {
+		return CheckReplicasDoesNotExceedSurgeCapacity(rolloutCtx)
+	})
+
+	framework.Async(wg, cancel, func() bool {
+		return CheckTotalReplicasNeverExceedDesiredPlusOne(testFramework, rolloutCtx)
+	})
+
+	framework.Async(wg, cancel, func() bool {
+		return WaitForControlPlaneMachineSetDesiredReplicas(rolloutCtx, cpms.DeepCopy())
+	})
+
+	framework.Async(wg, cancel, func() bool {
+		return checkRolloutProgress(testFramework, rolloutCtx)
+	})
+
+	wg.Wait()
+
+	// If there's an error in the context, either it timed out or one of the async checks failed.
+	return Expect(rolloutCtx.Err()).ToNot(HaveOccurred(), "rollout should have completed successfully")
 }
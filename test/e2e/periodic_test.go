@@ -45,5 +45,29 @@ var _ = Describe("ControlPlaneMachineSet Operator", framework.Periodic(), func()
 			})
 		})
 
+		Context("and an additional load balancer is configured", func() {
+			helpers.ItShouldRolloutOnLoadBalancerConfigChange(testFramework)
+		})
+
+		Context("and the placement tenancy is changed", func() {
+			helpers.ItShouldRolloutOnTenancyChange(testFramework)
+		})
+
+		Context("and the service account is changed", func() {
+			helpers.ItShouldRolloutOnGCPServiceAccountChange(testFramework)
+		})
+
+		Context("and the boot image is changed", func() {
+			helpers.ItShouldRolloutOnImageChange(testFramework)
+		})
+
+		Context("and the root disk size is increased", func() {
+			helpers.ItShouldRolloutOnRootDiskSizeIncrease(testFramework)
+		})
+
+		Context("and the disk type is changed", func() {
+			helpers.ItShouldRolloutOnDiskTypeChange(testFramework)
+		})
+
 	})
 })
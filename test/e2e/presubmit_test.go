@@ -36,6 +36,7 @@ var _ = Describe("ControlPlaneMachineSet Operator", framework.PreSubmit(), func(
 	Context("With an active ControlPlaneMachineSet", func() {
 		BeforeEach(func() {
 			helpers.EnsureActiveControlPlaneMachineSet(testFramework)
+			helpers.ExpectObservedGenerationTracksSpec(testFramework, testFramework.GetContext())
 		}, OncePerOrdered)
 
 		Context("and the instance type of index 1 is not as expected", func() {
@@ -46,15 +47,149 @@ var _ = Describe("ControlPlaneMachineSet Operator", framework.PreSubmit(), func(
 			helpers.ItShouldRollingUpdateReplaceTheOutdatedMachine(testFramework, 1)
 		})
 
+		Context("and the root disk size of index 1 is not as expected", func() {
+			BeforeEach(func() {
+				helpers.IncreaseControlPlaneMachineDiskSize(testFramework, 1)
+			})
+
+			helpers.ItShouldRollingUpdateReplaceTheOutdatedMachine(testFramework, 1)
+		})
+
+		Context("and the disk type of index 2 is not as expected", func() {
+			BeforeEach(func() {
+				helpers.ChangeControlPlaneMachineDiskType(testFramework, 2)
+			})
+
+			helpers.ItShouldRollingUpdateReplaceTheOutdatedMachine(testFramework, 2)
+		})
+
+		Context("and the security groups of index 1 are not as expected", func() {
+			BeforeEach(func() {
+				helpers.AddControlPlaneMachineSecurityGroup(testFramework, 1)
+			})
+
+			helpers.ItShouldRollingUpdateReplaceTheOutdatedMachine(testFramework, 1)
+		})
+
+		Context("and the root volume IOPS are changed", func() {
+			helpers.ItShouldCarryRootVolumeIOPSOnRollout(testFramework, 1)
+		})
+
+		Context("and the root disk encryption key is rotated", func() {
+			helpers.ItShouldRolloutOnKMSKeyRotation(testFramework, 1)
+		})
+
+		Context("and the template provider spec uses another compatible API version", func() {
+			helpers.ItShouldHandleProviderSpecVersionSkew(testFramework)
+		})
+
+		Context("and the failure domains are reduced below the number of replicas", func() {
+			helpers.ItShouldHandleFewerDomainsThanReplicas(testFramework)
+		})
+
+		Context("and the replacement machine node is slow to become ready", func() {
+			helpers.ItShouldWaitForNodeReadyBeforeDeletion(testFramework, 0)
+		})
+
+		Context("and the ControlPlaneMachineSet is deactivated", func() {
+			helpers.ItShouldStopManagingWhenDeactivated(testFramework, 2)
+		})
+
+		Context("and the Machine API controllers are temporarily unavailable", func() {
+			helpers.ItShouldRecoverAfterMachineAPIOutage(testFramework)
+		})
+
+		Context("and the operator cannot reach the API server", func() {
+			helpers.ItShouldReportNotReadyWithoutAPIAccess(testFramework)
+		})
+
+		Context("and the user data secret does not exist", func() {
+			helpers.ItShouldDegradeOnMissingUserDataSecret(testFramework)
+		})
+
+		Context("and the replacement machines repeatedly fail to provision", func() {
+			helpers.ItShouldBackoffOnRepeatedProvisionFailures(testFramework)
+		})
+
+		Context("and a failure domain has no capacity", func() {
+			helpers.ItShouldHandleZeroCapacityFailureDomain(testFramework)
+		})
+
+		Context("and the cloud quota cannot accommodate a surge machine", func() {
+			helpers.ItShouldWarnWhenSurgeExceedsQuota(testFramework)
+		})
+
+		Context("and the template has more tags than the cloud allows", func() {
+			helpers.ItShouldSurfaceExcessiveTagsError(testFramework)
+		})
+
+		Context("and the template references a subnet that does not exist", func() {
+			helpers.ItShouldDegradeOnInvalidSubnet(testFramework)
+		})
+
+		Context("and the API server certificates are rotated", func() {
+			helpers.ItShouldCompleteRolloutDuringCertRotation(testFramework)
+		})
+
+		Context("and the webhook serving certificate is rotated", func() {
+			helpers.ItShouldValidateThroughWebhookCertRotation(testFramework)
+		})
+
+		Context("and the operator restarts during a rollout", func() {
+			helpers.ItShouldResumeRolloutAfterOperatorRestart(testFramework)
+		})
+
+		Context("and an outdated machine is stuck in deletion", func() {
+			helpers.ItShouldRecoverFromStuckDeletingMachine(testFramework, 2)
+		})
+
+		Context("and an outdated machine has a pre-terminate hook", func() {
+			helpers.ItShouldHonourPreTerminateHookDuringRollout(testFramework, 1)
+		})
+
+		Context("and the operator is running with multiple replicas", func() {
+			helpers.ItShouldNotDoubleActWithMultipleReplicas(testFramework)
+		})
+
+		Context("and a managed machine loses its owner reference", func() {
+			helpers.ItShouldReAdoptOrphanedMachine(testFramework, 1)
+		})
+
+		Context("and a machine is updated concurrently with the operator", func() {
+			helpers.ItShouldRetryOnMachineUpdateConflict(testFramework, 1)
+		})
+
+		Context("and an index is missing from a 5 replica control plane", func() {
+			helpers.ItShouldRecreateMissingIndexInFiveReplica(testFramework)
+		})
+
+		Context("and a 5 replica control plane is scaled down to 3 replicas", func() {
+			helpers.ItShouldScaleDownHighestIndexesFirst(testFramework)
+		})
+
+		Context("and a majority of the control plane machines are unhealthy", func() {
+			helpers.ItShouldNotRemoveMachinesBelowQuorum(testFramework)
+		})
+
+		Context("and a control plane machine does not follow the index naming scheme", func() {
+			helpers.ItShouldHandleMachineWithoutIndexSuffix(testFramework)
+		})
+
+		Context("and the template has label values of the maximum length", func() {
+			helpers.ItShouldHandleLongLabelValues(testFramework)
+		})
+
 		Context("with the OnDelete update strategy", func() {
 			var originalStrategy machinev1.ControlPlaneMachineSetStrategyType
 
 			BeforeEach(func() {
 				originalStrategy = helpers.EnsureControlPlaneMachineSetUpdateStrategy(testFramework, machinev1.OnDelete)
+				helpers.ExpectObservedGenerationTracksSpec(testFramework, testFramework.GetContext())
 			}, OncePerOrdered)
 
 			AfterEach(func() {
 				helpers.EnsureControlPlaneMachineSetUpdateStrategy(testFramework, originalStrategy)
+				helpers.ExpectObservedGenerationTracksSpec(testFramework, testFramework.GetContext())
 			}, OncePerOrdered)
 
 			Context("and the instance type of index 2 is not as expected", Ordered, func() {
@@ -72,13 +207,50 @@ var _ = Describe("ControlPlaneMachineSet Operator", framework.PreSubmit(), func(
 
 				helpers.ItShouldOnDeleteReplaceTheOutDatedMachineWhenDeleted(testFramework, 2)
 			})
+
+			Context("and the strategy is switched to RollingUpdate with an outdated machine", func() {
+				helpers.ItShouldStartPendingRolloutOnStrategySwitch(testFramework, 1)
+			})
+
+			Context("and multiple outdated machines are deleted together", func() {
+				helpers.ItShouldRespectSurgeWhenMultipleDeletedUnderOnDelete(testFramework, []int{0, 2})
+			})
+
+			Context("and the replacement machine node never registers", func() {
+				helpers.ItShouldHandleMachineWithoutNode(testFramework, 1)
+			})
+
+			Context("and the cloud provider fails to create the replacement machine", func() {
+				helpers.ItShouldSurfaceProviderErrors(testFramework, 1)
+			})
 		})
 
 		Context("and the ControlPlaneMachineSet is up to date", Ordered, func() {
 			BeforeEach(func() {
 				helpers.EnsureControlPlaneMachineSetUpdated(testFramework)
+				helpers.ExpectObservedGenerationTracksSpec(testFramework, testFramework.GetContext())
 			})
 
+			helpers.ItShouldNotRollOnCloudNormalizedFields(testFramework)
+			helpers.ItShouldHandleDuplicateFailureDomains(testFramework)
+			helpers.ItShouldHandleMoreDomainsThanReplicas(testFramework)
+			helpers.ItShouldNormalizeFailureDomainStrings(testFramework)
+			helpers.ItShouldPreferFailureDomainOverTemplateZone(testFramework)
+			helpers.ItShouldNotRollOnTagReorder(testFramework)
+			helpers.ItShouldNotRollAfterProviderSpecDefaulting(testFramework)
+			helpers.ItShouldIgnoreMachineStatusDifferences(testFramework, 1)
+			helpers.ItShouldIgnoreProviderSpecMetadataName(testFramework, 2)
+			helpers.ItShouldNotRollDueToTimestampComparison(testFramework)
+			helpers.ItShouldReactToInfrastructureChange(testFramework)
+			helpers.ItShouldRejectAnEmptyTemplate(testFramework)
+			helpers.ItShouldRejectUnknownFailureDomainsPlatform(testFramework)
+			helpers.ItShouldRequireFailureDomainOnAWS(testFramework)
+			helpers.ItShouldRejectInvalidStrategies(testFramework)
+			helpers.ItShouldOnlySurgeByOne(testFramework)
+			helpers.ItShouldRejectZeroReplicas(testFramework)
+			helpers.ItShouldRecreateAllMachines(testFramework)
+			helpers.ItShouldRemoveTheFinalizerOnUninstall(testFramework)
+
 			Context("and the ControlPlaneMachineSet is deleted", func() {
 				BeforeEach(func() {
 					helpers.EnsureControlPlaneMachineSetDeleted(testFramework)
@@ -86,6 +258,7 @@ var _ = Describe("ControlPlaneMachineSet Operator", framework.PreSubmit(), func(
 
 				AfterEach(func() {
 					helpers.EnsureActiveControlPlaneMachineSet(testFramework)
+					helpers.ExpectObservedGenerationTracksSpec(testFramework, testFramework.GetContext())
 				})
 
 				helpers.ItShouldUninstallTheControlPlaneMachineSet(testFramework)
@@ -95,10 +268,19 @@ var _ = Describe("ControlPlaneMachineSet Operator", framework.PreSubmit(), func(
 					BeforeEach(func() {
 						helpers.EnsureControlPlaneMachineSetUpdated(testFramework)
 						helpers.EnsureActiveControlPlaneMachineSet(testFramework)
+						helpers.ExpectObservedGenerationTracksSpec(testFramework, testFramework.GetContext())
 					})
 
 					helpers.ItShouldNotCauseARollout(testFramework)
 					helpers.ItShouldCheckAllControlPlaneMachinesHaveCorrectOwnerReferences(testFramework)
+
+					Context("and the instance type of index 0 is not as expected", func() {
+						BeforeEach(func() {
+							helpers.IncreaseControlPlaneMachineInstanceSize(testFramework, 0)
+						})
+
+						helpers.ItShouldRollingUpdateReplaceTheOutdatedMachine(testFramework, 0)
+					})
 				})
 			})
 		})
@@ -114,6 +296,11 @@ var _ = Describe("ControlPlaneMachineSet Operator", framework.PreSubmit(), func(
 				helpers.EnsureControlPlaneMachineSetUpdated(testFramework)
 			})
 
+			Context("and the ControlPlaneMachineSet is activated", func() {
+				helpers.ItShouldBeginManagingMachinesWhenActivated(testFramework)
+				helpers.ItShouldNotRollOnActivationWhenUpToDate(testFramework)
+			})
+
 			AfterEach(func() {
 				helpers.EnsureControlPlaneMachineSetUpdated(testFramework)
 			})
@@ -124,6 +311,7 @@ var _ = Describe("ControlPlaneMachineSet Operator", framework.PreSubmit(), func(
 				BeforeEach(func() {
 					opts.TestFramework = testFramework
 					opts.UID = helpers.GetControlPlaneMachineSetUID(testFramework)
+					opts.Generated = helpers.IsControlPlaneMachineSetGenerated(testFramework)
 					opts.Index, opts.OriginalProviderSpec, opts.UpdatedProviderSpec = helpers.IncreaseNewestControlPlaneMachineInstanceSize(testFramework)
 				})
 